@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"web-crawler/env"
 	"web-crawler/models"
 
 	"gorm.io/driver/mysql"
@@ -27,11 +28,11 @@ type DatabaseConfig struct {
 // GetConfigFromEnv reads database configuration from environment variables
 func GetConfigFromEnv() DatabaseConfig {
 	return DatabaseConfig{
-		Host:     getEnvWithDefault("DB_HOST", "localhost"),
-		Port:     getEnvWithDefault("DB_PORT", "3306"),
-		User:     getEnvWithDefault("DB_USER", "crawler_user"),
-		Password: getEnvWithDefault("DB_PASSWORD", "crawler_password"),
-		Name:     getEnvWithDefault("DB_NAME", "crawler_db"),
+		Host:     env.String("DB_HOST", "localhost"),
+		Port:     env.String("DB_PORT", "3306"),
+		User:     env.String("DB_USER", "crawler_user"),
+		Password: env.String("DB_PASSWORD", "crawler_password"),
+		Name:     env.String("DB_NAME", "crawler_db"),
 	}
 }
 
@@ -115,17 +116,57 @@ func runMigrations() error {
 		&models.URL{},
 		&models.CrawlResult{},
 		&models.FoundLink{},
+		&models.PageImage{},
+		&models.StructuredData{},
+		&models.PageForm{},
 		&models.APIToken{},
+		&models.PageSnapshot{},
+		&models.ReparseJob{},
+		&models.CrawlAttempt{},
+		&models.CrawlWorker{},
+		&models.CrawlRecovery{},
+		&models.CrawlBatch{},
+		&models.CrawlBatchItem{},
+		&models.QueuePause{},
 	)
 
 	if err != nil {
 		return fmt.Errorf("auto-migration failed: %v", err)
 	}
 
+	if err := migrateLegacyLoginForms(); err != nil {
+		return err
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
 
+// migrateLegacyLoginForms carries the has_login_form flag of results crawled
+// before login confidence scores over to login_confidence, then drops the
+// column. A re-parse replaces the carried-over score with the page's signals.
+func migrateLegacyLoginForms() error {
+	migrator := DB.Migrator()
+	if !migrator.HasColumn(&models.CrawlResult{}, "has_login_form") {
+		return nil
+	}
+
+	// Safe to repeat if dropping the column fails
+	result := DB.Model(&models.CrawlResult{}).
+		Where("has_login_form = ? AND login_confidence < ?", true, models.LoginFormThreshold).
+		UpdateColumn("login_confidence", models.LoginFormThreshold)
+	if result.Error != nil {
+		return fmt.Errorf("failed to migrate has_login_form: %v", result.Error)
+	}
+
+	if err := migrator.DropColumn(&models.CrawlResult{}, "has_login_form"); err != nil {
+		return fmt.Errorf("failed to drop has_login_form: %v", err)
+	}
+
+	log.Printf("Migrated has_login_form to login_confidence for %d crawl results", result.RowsAffected)
+	return nil
+}
+
 // Close closes the database connection
 func Close() error {
 	if DB == nil {
@@ -139,11 +180,3 @@ func Close() error {
 
 	return sqlDB.Close()
 }
-
-// getEnvWithDefault returns environment variable value or default if not set
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"testing"
+
+	"web-crawler/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrateLegacyLoginForms(t *testing.T) {
+	var err error
+	DB, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := DB.AutoMigrate(&models.URL{}, &models.CrawlResult{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	// A database from before login confidence scores
+	if err := DB.Exec("ALTER TABLE `crawl_results` ADD `has_login_form` numeric DEFAULT false").Error; err != nil {
+		t.Fatalf("Failed to add legacy column: %v", err)
+	}
+	legacy := []struct {
+		hasLoginForm bool
+		confidence   int
+		expected     int
+	}{
+		{true, 0, models.LoginFormThreshold},
+		{true, 80, 80},
+		{false, 0, 0},
+	}
+	ids := make([]uint, len(legacy))
+	for i, row := range legacy {
+		result := models.CrawlResult{URLID: uint(i + 1), LoginConfidence: row.confidence}
+		if err := DB.Create(&result).Error; err != nil {
+			t.Fatalf("Failed to create crawl result: %v", err)
+		}
+		DB.Exec("UPDATE crawl_results SET has_login_form = ? WHERE id = ?", row.hasLoginForm, result.ID)
+		ids[i] = result.ID
+	}
+
+	// The second run finds no legacy column
+	for run := 0; run < 2; run++ {
+		if err := migrateLegacyLoginForms(); err != nil {
+			t.Fatalf("migrateLegacyLoginForms failed: %v", err)
+		}
+	}
+
+	if DB.Migrator().HasColumn(&models.CrawlResult{}, "has_login_form") {
+		t.Error("Expected the has_login_form column to be dropped")
+	}
+	for i, row := range legacy {
+		var result models.CrawlResult
+		DB.First(&result, ids[i])
+		if result.LoginConfidence != row.expected {
+			t.Errorf("has_login_form=%v, confidence %d: expected %d, got %d",
+				row.hasLoginForm, row.confidence, row.expected, result.LoginConfidence)
+		}
+	}
+}
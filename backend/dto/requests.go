@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+
+	"web-crawler/models"
 )
 
 // AddURLRequest represents a request to add a new URL for crawling
@@ -64,12 +66,15 @@ type ValidateTokenRequest struct {
 
 // PaginationRequest represents common pagination parameters
 type PaginationRequest struct {
-	Page     int    `form:"page,default=1" binding:"min=1"`
-	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
-	Search   string `form:"search"`
-	Status   string `form:"status"`
-	SortBy   string `form:"sort_by,default=created_at"`
-	SortDir  string `form:"sort_dir,default=desc"`
+	Page      int    `form:"page,default=1" binding:"min=1"`
+	PageSize  int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Search    string `form:"search"`
+	Status    string `form:"status"`
+	Changed   *bool  `form:"changed"`    // Filter on change since the previous crawl
+	ErrorType string `form:"error_type"` // Filter on the classification of the last failure
+	Schema    string `form:"schema"`     // Filter on a schema.org type in the structured data
+	SortBy    string `form:"sort_by,default=created_at"`
+	SortDir   string `form:"sort_dir,default=desc"`
 }
 
 // Validate validates pagination parameters
@@ -92,24 +97,25 @@ func (p *PaginationRequest) Validate() error {
 		return fmt.Errorf("sort_by must be one of: %s", strings.Join(validSortFields, ", "))
 	}
 	
-	// Validate status filter
-	if p.Status != "" {
-		validStatuses := []string{"queued", "running", "completed", "error"}
-		isValidStatus := false
-		for _, status := range validStatuses {
-			if p.Status == status {
-				isValidStatus = true
-				break
-			}
-		}
-		if !isValidStatus {
-			return fmt.Errorf("status must be one of: %s", strings.Join(validStatuses, ", "))
-		}
+	// Validate filters
+	if err := p.Filter().Validate(); err != nil {
+		return err
 	}
 	
 	return nil
 }
 
+// Filter returns the URL filter part of the request
+func (p *PaginationRequest) Filter() models.URLFilter {
+	return models.URLFilter{
+		Search:    p.Search,
+		Status:    p.Status,
+		Changed:   p.Changed,
+		ErrorType: p.ErrorType,
+		Schema:    p.Schema,
+	}
+}
+
 // GetOffset calculates the database offset for pagination
 func (p *PaginationRequest) GetOffset() int {
 	return (p.Page - 1) * p.PageSize
@@ -146,6 +146,18 @@ func TestPaginationRequestValidate(t *testing.T) {
 	if err := req.Validate(); err == nil {
 		t.Error("Invalid status should return error")
 	}
+	
+	// Test error type filter
+	req.Status = "error"
+	req.ErrorType = "timeout"
+	if err := req.Validate(); err != nil {
+		t.Errorf("Known error type should not return error: %v", err)
+	}
+	
+	req.ErrorType = "invalid_type"
+	if err := req.Validate(); err == nil {
+		t.Error("Invalid error type should return error")
+	}
 }
 
 func TestPaginationRequestGetOffset(t *testing.T) {
@@ -70,6 +70,10 @@ type URLResponse struct {
 	URL          string               `json:"url"`
 	Status       models.URLStatus     `json:"status"`
 	ErrorMessage *string              `json:"error_message,omitempty"`
+	ErrorType    *string              `json:"error_type,omitempty"`
+	RetryCount   int                  `json:"retry_count"`
+	NextRetryAt  *time.Time           `json:"next_retry_at,omitempty"`
+	WorkerID     *string              `json:"worker_id,omitempty"`
 	CreatedAt    time.Time            `json:"created_at"`
 	UpdatedAt    time.Time            `json:"updated_at"`
 	CrawlResult  *CrawlResultResponse `json:"crawl_result,omitempty"`
@@ -81,13 +85,219 @@ type CrawlResultResponse struct {
 	HTMLVersion            *string                `json:"html_version"`
 	PageTitle              *string                `json:"page_title"`
 	HeadingCounts          map[string]int         `json:"heading_counts"`
+	HeadingIssuesCount     int                    `json:"heading_issues_count"`
 	InternalLinksCount     int                    `json:"internal_links_count"`
 	ExternalLinksCount     int                    `json:"external_links_count"`
 	InaccessibleLinksCount int                    `json:"inaccessible_links_count"`
-	HasLoginForm           bool                   `json:"has_login_form"`
+	SavedLinksCount        int                    `json:"saved_links_count"`
+	LinksTruncated         bool                   `json:"links_truncated"`
+	HasLoginForm           bool                   `json:"has_login_form"` // login_confidence reaches models.LoginFormThreshold
+	Forms                  FormSummaryResponse    `json:"forms"`
+	Meta                   MetaTagsResponse       `json:"meta"`
+	SocialPreview          models.SocialPreview   `json:"social_preview"`
+	StructuredData         StructuredDataSummaryResponse `json:"structured_data"`
+	Images                 ImageSummaryResponse   `json:"images"`
 	CrawledAt              time.Time              `json:"crawled_at"`
 	CrawlDurationMs        *int                   `json:"crawl_duration_ms"`
 	TotalLinks             int                    `json:"total_links"`
+	Timing                 CrawlTimingResponse    `json:"timing"`
+	TLS                    *TLSResponse           `json:"tls,omitempty"`
+	SecurityHeadersScore   *int                   `json:"security_headers_score"`
+	SecurityHeadersGrade   *string                `json:"security_headers_grade"`
+	LastCheckedAt          *time.Time             `json:"last_checked_at"`
+	NotModifiedChecks      int                    `json:"not_modified_checks"`
+	ContentHash            *string                `json:"content_hash"`
+	TextHash               *string                `json:"text_hash"`
+	ChangeStatus           models.ChangeStatus    `json:"change_status"`
+	ContentChangedAt       *time.Time             `json:"content_changed_at"`
+}
+
+// MetaTagsResponse holds the meta tags of a crawled page
+type MetaTagsResponse struct {
+	Description  *string             `json:"description"`
+	Robots       *string             `json:"robots"`
+	Noindex      bool                `json:"noindex"`
+	Nofollow     bool                `json:"nofollow"`
+	CanonicalURL *string             `json:"canonical_url"`
+	Viewport     *string             `json:"viewport"`
+	ThemeColor   *string             `json:"theme_color"`
+	OpenGraph    map[string][]string `json:"open_graph"`
+	Twitter      map[string][]string `json:"twitter"`
+}
+
+// StructuredDataSummaryResponse summarizes the structured data of a crawled page
+type StructuredDataSummaryResponse struct {
+	Count  int      `json:"count"`
+	Errors int      `json:"errors"`
+	Types  []string `json:"types"`
+}
+
+// StructuredDataResponse represents a structured data item in API responses
+type StructuredDataResponse struct {
+	ID        uint                        `json:"id"`
+	Position  int                         `json:"position"`
+	Format    models.StructuredDataFormat `json:"format"`
+	Types     []string                    `json:"types"`
+	Payload   string                      `json:"payload"`
+	Valid     bool                        `json:"valid"`
+	Truncated bool                        `json:"truncated"` // Payload was cut to fit the size limits
+	Error     *string                     `json:"error"`
+}
+
+// ImageSummaryResponse counts a page's images by the issues found on them
+type ImageSummaryResponse struct {
+	Total             int `json:"total"`
+	MissingAlt        int `json:"missing_alt"`
+	EmptyAlt          int `json:"empty_alt"`
+	PoorAlt           int `json:"poor_alt"`
+	MissingDimensions int `json:"missing_dimensions"`
+	Lazy              int `json:"lazy"`
+}
+
+// FormSummaryResponse summarizes the forms of a crawled page and how likely it is to have a login
+type FormSummaryResponse struct {
+	Total           int                  `json:"total"`
+	Insecure        int                  `json:"insecure"`
+	LoginConfidence int                  `json:"login_confidence"`
+	LoginSignals    []models.LoginSignal `json:"login_signals"`
+}
+
+// CrawlTimingResponse breaks a crawl's duration down into fetch phases and parsing.
+// Phases that were not measured (e.g. TLS on plain HTTP, DNS on a reused connection) are null.
+type CrawlTimingResponse struct {
+	DNSLookupMs       *int `json:"dns_lookup_ms"`
+	TCPConnectMs      *int `json:"tcp_connect_ms"`
+	TLSHandshakeMs    *int `json:"tls_handshake_ms"`
+	TimeToFirstByteMs *int `json:"time_to_first_byte_ms"`
+	DownloadMs        *int `json:"download_ms"`
+	FetchDurationMs   *int `json:"fetch_duration_ms"`
+	ParseDurationMs   *int `json:"parse_duration_ms"`
+}
+
+// TLSResponse represents the TLS connection and certificate chain of an HTTPS crawl
+type TLSResponse struct {
+	Version          string                   `json:"version"`
+	CipherSuite      string                   `json:"cipher_suite"`
+	Subject          *string                  `json:"subject"`
+	Issuer           *string                  `json:"issuer"`
+	DNSNames         []string                 `json:"dns_names"`
+	NotBefore        *time.Time               `json:"not_before"`
+	NotAfter         *time.Time               `json:"not_after"`
+	DaysUntilExpiry  *int                     `json:"days_until_expiry"`
+	CertificateChain []models.CertificateInfo `json:"certificate_chain"`
+}
+
+// ExpiringCertificateResponse represents a URL whose certificate expires soon
+type ExpiringCertificateResponse struct {
+	URLID           uint      `json:"url_id"`
+	URL             string    `json:"url"`
+	Subject         *string   `json:"subject"`
+	Issuer          *string   `json:"issuer"`
+	DNSNames        []string  `json:"dns_names"`
+	NotAfter        time.Time `json:"not_after"`
+	DaysUntilExpiry int       `json:"days_until_expiry"`
+	IsExpired       bool      `json:"is_expired"`
+	CrawledAt       time.Time `json:"crawled_at"`
+}
+
+// SecurityHeadersResponse represents the security header report of a URL's last crawl
+type SecurityHeadersResponse struct {
+	URLID           uint                           `json:"url_id"`
+	URL             string                         `json:"url"`
+	Score           *int                           `json:"score"`
+	Grade           *string                        `json:"grade"`
+	Findings        []models.SecurityHeaderFinding `json:"findings"`
+	ResponseHeaders map[string][]string            `json:"response_headers"`
+	CrawledAt       time.Time                      `json:"crawled_at"`
+}
+
+// SnapshotResponse represents a stored raw HTML snapshot in API responses
+type SnapshotResponse struct {
+	ID             uint      `json:"id"`
+	ContentHash    string    `json:"content_hash"`
+	Size           int64     `json:"size"`
+	CompressedSize int64     `json:"compressed_size"`
+	ContentType    string    `json:"content_type"`
+	StatusCode     int       `json:"status_code"`
+	FinalURL       string    `json:"final_url"`
+	FetchedAt      time.Time `json:"fetched_at"`
+}
+
+// CrawlAttemptResponse represents one crawl attempt and its log in API responses
+type CrawlAttemptResponse struct {
+	ID           uint                       `json:"id"`
+	Attempt      int                        `json:"attempt"`
+	Outcome      models.CrawlAttemptOutcome `json:"outcome"`
+	StartedAt    time.Time                  `json:"started_at"`
+	FinishedAt   *time.Time                 `json:"finished_at"`
+	DurationMs   *int                       `json:"duration_ms"`
+	FinalURL     *string                    `json:"final_url"`
+	StatusCode   *int                       `json:"status_code"`
+	Bytes        *int64                     `json:"bytes"`
+	ErrorType    *string                    `json:"error_type"`
+	ErrorMessage *string                    `json:"error_message"`
+	LogLines     []string                   `json:"log_lines"`
+}
+
+// CrawlRecoveryResponse represents a stale crawl recovered by the reaper in API responses
+type CrawlRecoveryResponse struct {
+	ID             uint                       `json:"id"`
+	URLID          uint                       `json:"url_id"`
+	URL            string                     `json:"url"`
+	WorkerID       *string                    `json:"worker_id"`
+	CrawlStartedAt *time.Time                 `json:"crawl_started_at"`
+	Reason         models.CrawlRecoveryReason `json:"reason"`
+	Action         models.CrawlRecoveryAction `json:"action"`
+	Message        string                     `json:"message"`
+	ReaperID       string                     `json:"reaper_id"`
+	CreatedAt      time.Time                  `json:"created_at"`
+}
+
+// ReparseJobResponse represents a snapshot re-parse job in API responses
+type ReparseJobResponse struct {
+	ID              uint                    `json:"id"`
+	Status          models.ReparseJobStatus `json:"status"`
+	BatchSize       int                     `json:"batch_size"`
+	Total           int                     `json:"total"`
+	Processed       int                     `json:"processed"`
+	Succeeded       int                     `json:"succeeded"`
+	Failed          int                     `json:"failed"`
+	Skipped         int                     `json:"skipped"`
+	ProgressPercent float64                 `json:"progress_percent"`
+	LastURLID       uint                    `json:"last_url_id"`
+	LastError       *string                 `json:"last_error"`
+	StartedAt       *time.Time              `json:"started_at"`
+	FinishedAt      *time.Time              `json:"finished_at"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
+
+// CrawlBatchResponse represents a batch crawl and its progress in API responses
+type CrawlBatchResponse struct {
+	ID              uint                    `json:"id"`
+	Status          models.CrawlBatchStatus `json:"status"`
+	Priority        models.CrawlPriority    `json:"priority"`
+	URLIDs          []uint                  `json:"url_ids,omitempty"`
+	Filter          *models.URLFilter       `json:"filter,omitempty"`
+	Total           int                     `json:"total"`
+	Processed       int                     `json:"processed"`
+	Queued          int                     `json:"queued"`
+	Skipped         int                     `json:"skipped"`
+	Failed          int                     `json:"failed"`
+	ProgressPercent float64                 `json:"progress_percent"`
+	LastError       *string                 `json:"last_error"`
+	StartedAt       *time.Time              `json:"started_at"`
+	FinishedAt      *time.Time              `json:"finished_at"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
+
+// CrawlBatchItemResponse represents one URL of a batch crawl in API responses
+type CrawlBatchItemResponse struct {
+	URLID     uint                         `json:"url_id"`
+	URL       string                       `json:"url"`
+	Outcome   models.CrawlBatchItemOutcome `json:"outcome"`
+	Reason    *string                      `json:"reason,omitempty"`
+	QueuedAt  *time.Time                   `json:"queued_at,omitempty"`
+	URLStatus *models.URLStatus            `json:"url_status"` // Current crawl status; null if the URL was deleted
 }
 
 // FoundLinkResponse represents a found link in API responses
@@ -96,18 +306,62 @@ type FoundLinkResponse struct {
 	LinkURL      string  `json:"link_url"`
 	LinkText     *string `json:"link_text"`
 	IsInternal   bool    `json:"is_internal"`
+	IsImage      bool    `json:"is_image"`
 	IsAccessible *bool   `json:"is_accessible"`
 	StatusCode   *int    `json:"status_code"`
 	ErrorMessage *string `json:"error_message"`
+	Occurrences  int      `json:"occurrences"`
+	AnchorTexts  []string `json:"anchor_texts"`
+	Region       *string  `json:"region"`
+	Rel          []string `json:"rel"`
+	Target       *string  `json:"target"`
+	Title        *string  `json:"title"`
 	IsBroken     bool    `json:"is_broken"`
 	StatusCategory string `json:"status_category"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+// PageImageResponse represents an image found on a page in API responses
+type PageImageResponse struct {
+	ID            uint                    `json:"id"`
+	Position      int                     `json:"position"`
+	Src           *string                 `json:"src"`
+	Srcset        []models.ImageCandidate `json:"srcset"`
+	InPicture     bool                    `json:"in_picture"`
+	Alt           *string                 `json:"alt"`
+	AltStatus     models.ImageAltStatus   `json:"alt_status"`
+	Width         *int                    `json:"width"`
+	Height        *int                    `json:"height"`
+	HasDimensions bool                    `json:"has_dimensions"`
+	Lazy          bool                    `json:"lazy"`
+}
+
+// PageFormResponse represents a form found on a page in API responses
+type PageFormResponse struct {
+	ID              uint                 `json:"id"`
+	Position        int                  `json:"position"`
+	Action          *string              `json:"action"`
+	Method          *string              `json:"method"`
+	Standalone      bool                 `json:"standalone"`
+	Fields          []models.FormField   `json:"fields"`
+	HasCSRFToken    bool                 `json:"has_csrf_token"`
+	InsecureAction  bool                 `json:"insecure_action"`
+	LoginConfidence int                  `json:"login_confidence"`
+	LoginSignals    []models.LoginSignal `json:"login_signals"`
+	IsLogin         bool                 `json:"is_login"`
+}
+
+// HeadingOutlineResponse represents the heading outline of a crawled page
+type HeadingOutlineResponse struct {
+	Issues   map[models.HeadingIssue]int `json:"issues"`   // Headings flagged with each issue
+	Headings []models.HeadingNode        `json:"headings"` // Top-level headings, with nested headings as children
+}
+
 // URLDetailResponse represents detailed URL information with links
 type URLDetailResponse struct {
 	URLResponse
-	FoundLinks []FoundLinkResponse `json:"found_links"`
+	FoundLinks     []FoundLinkResponse     `json:"found_links"`
+	HeadingOutline *HeadingOutlineResponse `json:"heading_outline"` // nil until the URL is crawled
 }
 
 // TokenValidationResponse represents token validation response
@@ -124,6 +378,10 @@ func FromURL(url *models.URL) URLResponse {
 		URL:          url.URL,
 		Status:       url.Status,
 		ErrorMessage: url.ErrorMessage,
+		ErrorType:    url.ErrorType,
+		RetryCount:   url.RetryCount,
+		NextRetryAt:  url.NextRetryAt,
+		WorkerID:     url.WorkerID,
 		CreatedAt:    url.CreatedAt,
 		UpdatedAt:    url.UpdatedAt,
 	}
@@ -143,32 +401,432 @@ func FromCrawlResult(result *models.CrawlResult) *CrawlResultResponse {
 		HTMLVersion:            result.HTMLVersion,
 		PageTitle:              result.PageTitle,
 		HeadingCounts:          result.GetHeadingCounts(),
+		HeadingIssuesCount:     result.HeadingIssuesCount,
 		InternalLinksCount:     result.InternalLinksCount,
 		ExternalLinksCount:     result.ExternalLinksCount,
 		InaccessibleLinksCount: result.InaccessibleLinksCount,
-		HasLoginForm:           result.HasLoginForm,
+		SavedLinksCount:        result.SavedLinksCount,
+		LinksTruncated:         result.LinksTruncated,
+		HasLoginForm:           result.HasLoginForm(),
+		Forms:                  FromFormSummary(result),
+		Meta:                   FromMetaTags(result),
+		SocialPreview:          result.GetSocialPreview(),
+		StructuredData:         FromStructuredDataSummary(result),
+		Images:                 FromImageSummary(result),
 		CrawledAt:              result.CrawledAt,
 		CrawlDurationMs:        result.CrawlDurationMs,
 		TotalLinks:             result.GetTotalLinks(),
+		Timing: CrawlTimingResponse{
+			DNSLookupMs:       result.DNSLookupMs,
+			TCPConnectMs:      result.TCPConnectMs,
+			TLSHandshakeMs:    result.TLSHandshakeMs,
+			TimeToFirstByteMs: result.TimeToFirstByteMs,
+			DownloadMs:        result.DownloadMs,
+			FetchDurationMs:   result.FetchDurationMs,
+			ParseDurationMs:   result.ParseDurationMs,
+		},
+		TLS:                  FromTLSDetails(result),
+		SecurityHeadersScore: result.SecurityHeadersScore,
+		SecurityHeadersGrade: result.SecurityHeadersGrade,
+		LastCheckedAt:        result.LastCheckedAt,
+		NotModifiedChecks:    result.NotModifiedChecks,
+		ContentHash:          result.ContentHash,
+		TextHash:             result.TextHash,
+		ChangeStatus:         result.ChangeStatus,
+		ContentChangedAt:     result.ContentChangedAt,
+	}
+}
+
+// FromMetaTags builds the meta tags block of a crawl result
+func FromMetaTags(result *models.CrawlResult) MetaTagsResponse {
+	openGraph := result.OpenGraph
+	if openGraph == nil {
+		openGraph = map[string][]string{}
+	}
+	twitter := result.TwitterTags
+	if twitter == nil {
+		twitter = map[string][]string{}
+	}
+
+	return MetaTagsResponse{
+		Description:  result.MetaDescription,
+		Robots:       result.MetaRobots,
+		Noindex:      result.RobotsNoindex,
+		Nofollow:     result.RobotsNofollow,
+		CanonicalURL: result.CanonicalURL,
+		Viewport:     result.Viewport,
+		ThemeColor:   result.ThemeColor,
+		OpenGraph:    openGraph,
+		Twitter:      twitter,
+	}
+}
+
+// FromStructuredDataSummary builds the structured data block of a crawl result
+func FromStructuredDataSummary(result *models.CrawlResult) StructuredDataSummaryResponse {
+	types := result.SchemaTypes
+	if types == nil {
+		types = []string{}
+	}
+
+	return StructuredDataSummaryResponse{
+		Count:  result.StructuredDataCount,
+		Errors: result.StructuredDataErrorCount,
+		Types:  types,
+	}
+}
+
+// FromImageSummary builds the image summary block of a crawl result
+func FromImageSummary(result *models.CrawlResult) ImageSummaryResponse {
+	return ImageSummaryResponse{
+		Total:             result.ImagesCount,
+		MissingAlt:        result.ImagesMissingAltCount,
+		EmptyAlt:          result.ImagesEmptyAltCount,
+		PoorAlt:           result.ImagesPoorAltCount,
+		MissingDimensions: result.ImagesMissingDimensionsCount,
+		Lazy:              result.ImagesLazyCount,
+	}
+}
+
+// FromHeadingOutline builds the heading outline of a crawl result, or nil if the URL has not been crawled
+func FromHeadingOutline(result *models.CrawlResult) *HeadingOutlineResponse {
+	if result == nil {
+		return nil
+	}
+
+	issues := make(map[models.HeadingIssue]int, len(models.HeadingIssues))
+	for _, issue := range models.HeadingIssues {
+		issues[issue] = 0
+	}
+	for _, heading := range result.Headings {
+		for _, issue := range heading.Issues {
+			issues[issue]++
+		}
+	}
+
+	return &HeadingOutlineResponse{
+		Issues:   issues,
+		Headings: result.GetHeadingOutline(),
+	}
+}
+
+// FromFormSummary builds the forms block of a crawl result
+func FromFormSummary(result *models.CrawlResult) FormSummaryResponse {
+	signals := result.LoginSignals
+	if signals == nil {
+		signals = []models.LoginSignal{}
+	}
+
+	return FormSummaryResponse{
+		Total:           result.FormsCount,
+		Insecure:        result.InsecureFormsCount,
+		LoginConfidence: result.LoginConfidence,
+		LoginSignals:    signals,
+	}
+}
+
+// FromTLSDetails builds the TLS block of a crawl result, or nil for plain HTTP crawls
+func FromTLSDetails(result *models.CrawlResult) *TLSResponse {
+	if result.TLSVersion == nil {
+		return nil
+	}
+
+	response := &TLSResponse{
+		Version:          *result.TLSVersion,
+		Subject:          result.CertSubject,
+		Issuer:           result.CertIssuer,
+		NotBefore:        result.CertNotBefore,
+		NotAfter:         result.CertNotAfter,
+		DNSNames:         []string{},
+		CertificateChain: result.CertChain,
+	}
+
+	if result.TLSCipherSuite != nil {
+		response.CipherSuite = *result.TLSCipherSuite
+	}
+
+	if leaf := result.GetLeafCertificate(); leaf != nil {
+		days := leaf.DaysUntilExpiry(time.Now())
+		response.DaysUntilExpiry = &days
+		response.DNSNames = leaf.DNSNames
+	}
+
+	return response
+}
+
+// FromExpiringCertificate converts a crawl result with its URL to ExpiringCertificateResponse
+func FromExpiringCertificate(result *models.CrawlResult, now time.Time) ExpiringCertificateResponse {
+	response := ExpiringCertificateResponse{
+		URLID:     result.URLID,
+		Subject:   result.CertSubject,
+		Issuer:    result.CertIssuer,
+		DNSNames:  []string{},
+		CrawledAt: result.CrawledAt,
+	}
+
+	if result.URL != nil {
+		response.URL = result.URL.URL
+	}
+
+	if leaf := result.GetLeafCertificate(); leaf != nil {
+		response.DNSNames = leaf.DNSNames
+		response.NotAfter = leaf.NotAfter
+		response.DaysUntilExpiry = leaf.DaysUntilExpiry(now)
+		response.IsExpired = leaf.IsExpired(now)
+	}
+
+	return response
+}
+
+// FromSecurityHeaders converts a URL and its crawl result to SecurityHeadersResponse
+func FromSecurityHeaders(url *models.URL, result *models.CrawlResult) SecurityHeadersResponse {
+	response := SecurityHeadersResponse{
+		URLID:           url.ID,
+		URL:             url.URL,
+		Score:           result.SecurityHeadersScore,
+		Grade:           result.SecurityHeadersGrade,
+		Findings:        result.SecurityHeaderFindings,
+		ResponseHeaders: result.ResponseHeaders,
+		CrawledAt:       result.CrawledAt,
+	}
+
+	if response.Findings == nil {
+		response.Findings = []models.SecurityHeaderFinding{}
+	}
+
+	return response
+}
+
+// FromSnapshots converts a slice of models.PageSnapshot to slice of SnapshotResponse
+func FromSnapshots(snapshots []models.PageSnapshot) []SnapshotResponse {
+	responses := make([]SnapshotResponse, len(snapshots))
+	for i, snapshot := range snapshots {
+		responses[i] = SnapshotResponse{
+			ID:             snapshot.ID,
+			ContentHash:    snapshot.ContentHash,
+			Size:           snapshot.Size,
+			CompressedSize: snapshot.CompressedSize,
+			ContentType:    snapshot.ContentType,
+			StatusCode:     snapshot.StatusCode,
+			FinalURL:       snapshot.FinalURL,
+			FetchedAt:      snapshot.FetchedAt,
+		}
+	}
+	return responses
+}
+
+// FromCrawlAttempts converts a slice of models.CrawlAttempt to slice of CrawlAttemptResponse
+func FromCrawlAttempts(attempts []models.CrawlAttempt) []CrawlAttemptResponse {
+	responses := make([]CrawlAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		logLines := attempt.LogLines
+		if logLines == nil {
+			logLines = []string{}
+		}
+
+		responses[i] = CrawlAttemptResponse{
+			ID:           attempt.ID,
+			Attempt:      attempt.Attempt,
+			Outcome:      attempt.Outcome,
+			StartedAt:    attempt.StartedAt,
+			FinishedAt:   attempt.FinishedAt,
+			DurationMs:   attempt.DurationMs,
+			FinalURL:     attempt.FinalURL,
+			StatusCode:   attempt.StatusCode,
+			Bytes:        attempt.Bytes,
+			ErrorType:    attempt.ErrorType,
+			ErrorMessage: attempt.ErrorMessage,
+			LogLines:     logLines,
+		}
+	}
+	return responses
+}
+
+// FromCrawlRecoveries converts a slice of models.CrawlRecovery to slice of CrawlRecoveryResponse
+func FromCrawlRecoveries(recoveries []models.CrawlRecovery) []CrawlRecoveryResponse {
+	responses := make([]CrawlRecoveryResponse, len(recoveries))
+	for i, recovery := range recoveries {
+		responses[i] = CrawlRecoveryResponse{
+			ID:             recovery.ID,
+			URLID:          recovery.URLID,
+			URL:            recovery.URL,
+			WorkerID:       recovery.WorkerID,
+			CrawlStartedAt: recovery.CrawlStartedAt,
+			Reason:         recovery.Reason,
+			Action:         recovery.Action,
+			Message:        recovery.Message,
+			ReaperID:       recovery.ReaperID,
+			CreatedAt:      recovery.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// FromCrawlBatch converts a models.CrawlBatch to CrawlBatchResponse
+func FromCrawlBatch(batch *models.CrawlBatch) CrawlBatchResponse {
+	return CrawlBatchResponse{
+		ID:              batch.ID,
+		Status:          batch.Status,
+		Priority:        batch.Priority,
+		URLIDs:          batch.URLIDs,
+		Filter:          batch.Filter,
+		Total:           batch.Total,
+		Processed:       batch.Processed,
+		Queued:          batch.Queued,
+		Skipped:         batch.Skipped,
+		Failed:          batch.Failed,
+		ProgressPercent: batch.Progress(),
+		LastError:       batch.LastError,
+		StartedAt:       batch.StartedAt,
+		FinishedAt:      batch.FinishedAt,
+		CreatedAt:       batch.CreatedAt,
+	}
+}
+
+// FromCrawlBatchItems converts batch items to responses, adding the current status of each URL
+func FromCrawlBatchItems(items []models.CrawlBatchItem, statuses map[uint]models.URLStatus) []CrawlBatchItemResponse {
+	responses := make([]CrawlBatchItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = CrawlBatchItemResponse{
+			URLID:    item.URLID,
+			URL:      item.URL,
+			Outcome:  item.Outcome,
+			Reason:   item.Reason,
+			QueuedAt: item.QueuedAt,
+		}
+		if status, ok := statuses[item.URLID]; ok {
+			responses[i].URLStatus = &status
+		}
+	}
+	return responses
+}
+
+// FromReparseJob converts a models.ReparseJob to ReparseJobResponse
+func FromReparseJob(job *models.ReparseJob) ReparseJobResponse {
+	return ReparseJobResponse{
+		ID:              job.ID,
+		Status:          job.Status,
+		BatchSize:       job.BatchSize,
+		Total:           job.Total,
+		Processed:       job.Processed,
+		Succeeded:       job.Succeeded,
+		Failed:          job.Failed,
+		Skipped:         job.Skipped,
+		ProgressPercent: job.Progress(),
+		LastURLID:       job.LastURLID,
+		LastError:       job.LastError,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		CreatedAt:       job.CreatedAt,
 	}
 }
 
 // FromFoundLink converts a models.FoundLink to FoundLinkResponse
 func FromFoundLink(link *models.FoundLink) FoundLinkResponse {
+	anchorTexts := link.AnchorTexts
+	if anchorTexts == nil {
+		anchorTexts = []string{}
+	}
+
 	return FoundLinkResponse{
 		ID:             link.ID,
 		LinkURL:        link.LinkURL,
 		LinkText:       link.LinkText,
 		IsInternal:     link.IsInternal,
+		IsImage:        link.IsImage,
 		IsAccessible:   link.IsAccessible,
 		StatusCode:     link.StatusCode,
 		ErrorMessage:   link.ErrorMessage,
+		Occurrences:    link.Occurrences,
+		AnchorTexts:    anchorTexts,
+		Region:         link.Region,
+		Rel:            link.RelValues(),
+		Target:         link.Target,
+		Title:          link.Title,
 		IsBroken:       link.IsBroken(),
 		StatusCategory: link.GetStatusCategory(),
 		CreatedAt:      link.CreatedAt,
 	}
 }
 
+// FromPageImages converts a slice of models.PageImage to slice of PageImageResponse
+func FromPageImages(images []models.PageImage) []PageImageResponse {
+	responses := make([]PageImageResponse, len(images))
+	for i, image := range images {
+		srcset := image.Srcset
+		if srcset == nil {
+			srcset = []models.ImageCandidate{}
+		}
+
+		responses[i] = PageImageResponse{
+			ID:            image.ID,
+			Position:      image.Position,
+			Src:           image.Src,
+			Srcset:        srcset,
+			InPicture:     image.InPicture,
+			Alt:           image.Alt,
+			AltStatus:     image.AltStatus,
+			Width:         image.Width,
+			Height:        image.Height,
+			HasDimensions: image.HasDimensions(),
+			Lazy:          image.Lazy,
+		}
+	}
+	return responses
+}
+
+// FromPageForms converts a slice of models.PageForm to slice of PageFormResponse
+func FromPageForms(forms []models.PageForm) []PageFormResponse {
+	responses := make([]PageFormResponse, len(forms))
+	for i, form := range forms {
+		fields := form.Fields
+		if fields == nil {
+			fields = []models.FormField{}
+		}
+		signals := form.LoginSignals
+		if signals == nil {
+			signals = []models.LoginSignal{}
+		}
+
+		responses[i] = PageFormResponse{
+			ID:              form.ID,
+			Position:        form.Position,
+			Action:          form.Action,
+			Method:          form.Method,
+			Standalone:      form.Standalone,
+			Fields:          fields,
+			HasCSRFToken:    form.HasCSRFToken,
+			InsecureAction:  form.InsecureAction,
+			LoginConfidence: form.LoginConfidence,
+			LoginSignals:    signals,
+			IsLogin:         form.IsLogin(),
+		}
+	}
+	return responses
+}
+
+// FromStructuredData converts a slice of models.StructuredData to slice of StructuredDataResponse
+func FromStructuredData(items []models.StructuredData) []StructuredDataResponse {
+	responses := make([]StructuredDataResponse, len(items))
+	for i, item := range items {
+		types := item.Types
+		if types == nil {
+			types = []string{}
+		}
+
+		responses[i] = StructuredDataResponse{
+			ID:        item.ID,
+			Position:  item.Position,
+			Format:    item.Format,
+			Types:     types,
+			Payload:   item.Payload,
+			Valid:     item.Valid,
+			Truncated: item.Truncated,
+			Error:     item.Error,
+		}
+	}
+	return responses
+}
+
 // FromURLs converts a slice of models.URL to slice of URLResponse
 func FromURLs(urls []models.URL) []URLResponse {
 	responses := make([]URLResponse, len(urls))
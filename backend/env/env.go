@@ -0,0 +1,47 @@
+// Package env reads configuration from environment variables
+package env
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// String returns environment variable value or default if not set
+func String(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// Int returns an integer environment variable, or the default if unset or invalid
+func Int(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Ignoring invalid %s=%q: not an integer", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// Bool returns a boolean environment variable, or the default if unset or invalid
+func Bool(key string, defaultValue bool) bool {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Ignoring invalid %s=%q: not a boolean", key, value)
+		return defaultValue
+	}
+	return parsed
+}
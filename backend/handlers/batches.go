@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"web-crawler/database"
+	"web-crawler/dto"
+	"web-crawler/models"
+	"web-crawler/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BatchHandler handles batch crawls of URLs selected by ID or filter
+type BatchHandler struct {
+	batches *services.BatchCrawler
+}
+
+// NewBatchHandler creates a new batch crawl handler
+func NewBatchHandler(batches *services.BatchCrawler) *BatchHandler {
+	return &BatchHandler{
+		batches: batches,
+	}
+}
+
+// StartBatch creates a batch crawl and queues its URLs in the background
+// POST /api/crawls/batch
+func (h *BatchHandler) StartBatch(c *gin.Context) {
+	var req struct {
+		URLIDs   []uint               `json:"url_ids"`
+		Filter   *models.URLFilter    `json:"filter"`
+		Priority models.CrawlPriority `json:"priority" binding:"omitempty,oneof=high normal low"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_REQUEST",
+			"Invalid request format",
+			err.Error(),
+		))
+		return
+	}
+
+	batch, err := h.batches.StartBatch(services.BatchRequest{
+		URLIDs:   req.URLIDs,
+		Filter:   req.Filter,
+		Priority: req.Priority,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBatchSelection):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_SELECTION",
+				"Invalid batch selection",
+				err.Error(),
+			))
+		case errors.Is(err, services.ErrBatchURLsNotFound):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"URLS_NOT_FOUND",
+				"Some URLs not found",
+				"One or more URL IDs do not exist",
+			))
+		case errors.Is(err, services.ErrBatchEmpty):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"NO_MATCHING_URLS",
+				"No URLs match the batch",
+				"",
+			))
+		case errors.Is(err, services.ErrBatchTooLarge):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"TOO_MANY_URLS",
+				"Too many URLs in batch",
+				err.Error(),
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+				"BATCH_ERROR",
+				"Failed to start batch crawl",
+				err.Error(),
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse(dto.FromCrawlBatch(batch)))
+}
+
+// GetBatch returns the progress of a batch crawl and the outcome of its URLs
+// GET /api/crawls/batch/:id
+func (h *BatchHandler) GetBatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid batch ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 1000 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_LIMIT",
+				"Invalid limit",
+				"limit must be between 1 and 1000",
+			))
+			return
+		}
+	}
+
+	offset := 0
+	if raw := c.Query("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_OFFSET",
+				"Invalid offset",
+				"offset must be a non-negative integer",
+			))
+			return
+		}
+	}
+
+	outcome := models.CrawlBatchItemOutcome(c.Query("outcome"))
+	switch outcome {
+	case "", models.BatchItemPending, models.BatchItemQueued, models.BatchItemSkipped, models.BatchItemFailed:
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_OUTCOME",
+			"Invalid outcome filter",
+			"outcome must be one of: pending, queued, skipped, failed",
+		))
+		return
+	}
+
+	var batch models.CrawlBatch
+	result := database.DB.First(&batch, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"BATCH_NOT_FOUND",
+				"Batch not found",
+				"",
+			))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch batch",
+			result.Error.Error(),
+		))
+		return
+	}
+
+	query := database.DB.Model(&models.CrawlBatchItem{}).Where("batch_id = ?", batch.ID)
+	if outcome != "" {
+		query = query.Where("outcome = ?", outcome)
+	}
+
+	var itemCount int64
+	if err := query.Count(&itemCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to count batch items",
+			err.Error(),
+		))
+		return
+	}
+
+	var items []models.CrawlBatchItem
+	if err := query.Order("id").Offset(offset).Limit(limit).Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch batch items",
+			err.Error(),
+		))
+		return
+	}
+
+	// Current status of each URL, so callers can follow the crawls themselves
+	urlIDs := make([]uint, len(items))
+	for i, item := range items {
+		urlIDs[i] = item.URLID
+	}
+	var urls []models.URL
+	if len(urlIDs) > 0 {
+		if err := database.DB.Select("id", "status").Where("id IN ?", urlIDs).Find(&urls).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+				"DATABASE_ERROR",
+				"Failed to fetch URL statuses",
+				err.Error(),
+			))
+			return
+		}
+	}
+	statuses := make(map[uint]models.URLStatus, len(urls))
+	for _, url := range urls {
+		statuses[url.ID] = url.Status
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"batch":      dto.FromCrawlBatch(&batch),
+		"items":      dto.FromCrawlBatchItems(items, statuses),
+		"item_count": itemCount,
+		"limit":      limit,
+		"offset":     offset,
+	}))
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/dto"
+	"web-crawler/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CertificateHandler handles TLS certificate reporting requests
+type CertificateHandler struct{}
+
+// NewCertificateHandler creates a new certificate handler
+func NewCertificateHandler() *CertificateHandler {
+	return &CertificateHandler{}
+}
+
+// ListExpiringCertificates returns URLs whose TLS certificate expires within N days
+// (already expired certificates are included)
+// GET /api/certificates/expiring?days=30
+func (h *CertificateHandler) ListExpiringCertificates(c *gin.Context) {
+	const defaultDays = 30
+	const maxDays = 3650
+
+	days := defaultDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 || parsed > maxDays {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_PARAMS",
+				"Invalid days parameter",
+				"days must be an integer between 0 and 3650",
+			))
+			return
+		}
+		days = parsed
+	}
+
+	now := time.Now().UTC()
+	cutoff := now.AddDate(0, 0, days)
+
+	var results []models.CrawlResult
+	err := database.DB.
+		Preload("URL").
+		Where("cert_not_after IS NOT NULL AND cert_not_after <= ?", cutoff).
+		Order("cert_not_after ASC").
+		Find(&results).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch expiring certificates",
+			err.Error(),
+		))
+		return
+	}
+
+	certificates := make([]dto.ExpiringCertificateResponse, len(results))
+	for i := range results {
+		certificates[i] = dto.FromExpiringCertificate(&results[i], now)
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"days":         days,
+		"cutoff":       cutoff,
+		"total":        len(certificates),
+		"certificates": certificates,
+	}))
+}
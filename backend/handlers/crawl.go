@@ -39,6 +39,25 @@ func (h *CrawlHandler) StartCrawl(c *gin.Context) {
 		return
 	}
 
+	var req struct {
+		Priority models.CrawlPriority `json:"priority" binding:"omitempty,oneof=high normal low"`
+	}
+
+	// The body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_REQUEST",
+				"Invalid request format",
+				err.Error(),
+			))
+			return
+		}
+	}
+	if req.Priority == "" {
+		req.Priority = models.PriorityNormal
+	}
+
 	// Get URL from database
 	var url models.URL
 	result := database.DB.First(&url, id)
@@ -70,9 +89,24 @@ func (h *CrawlHandler) StartCrawl(c *gin.Context) {
 		return
 	}
 
+	// Mark the URL queued before queueing it, so a fast worker's final status is not overwritten
+	previousStatus := url.Status
+	previousError := url.ErrorMessage
+	if url.Status != models.StatusQueued {
+		updates := map[string]interface{}{
+			"status":        models.StatusQueued,
+			"error_message": nil,
+		}
+		database.DB.Model(&url).Updates(updates)
+	}
+
 	// Queue the URL for crawling
-	if err := h.crawlManager.QueueURL(uint(id), url.URL); err != nil {
+	if err := h.crawlManager.QueueURLWithPriority(uint(id), url.URL, req.Priority); err != nil {
 		log.Printf("Failed to queue URL ID=%d: %v", id, err)
+		database.DB.Model(&url).Updates(map[string]interface{}{
+			"status":        previousStatus,
+			"error_message": previousError,
+		})
 		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse(
 			"QUEUE_FULL",
 			"Crawl queue is full",
@@ -83,20 +117,12 @@ func (h *CrawlHandler) StartCrawl(c *gin.Context) {
 
 	log.Printf("Successfully queued URL ID=%d for crawling", id)
 
-	// Update URL status to queued (if not already)
-	if url.Status != models.StatusQueued {
-		updates := map[string]interface{}{
-			"status":        models.StatusQueued,
-			"error_message": nil,
-		}
-		database.DB.Model(&url).Updates(updates)
-	}
-
 	c.JSON(http.StatusAccepted, dto.SuccessResponse(gin.H{
 		"message":    "Crawl started successfully",
 		"url_id":     id,
 		"url":        url.URL,
 		"status":     "queued",
+		"priority":   req.Priority,
 		"queue_info": h.crawlManager.GetQueueStatus(),
 	}))
 }
@@ -137,18 +163,36 @@ func (h *CrawlHandler) GetCrawlStatus(c *gin.Context) {
 
 	// Build status response
 	statusResponse := gin.H{
-		"url_id":     id,
-		"url":        url.URL,
-		"status":     url.Status,
-		"created_at": url.CreatedAt,
-		"updated_at": url.UpdatedAt,
-		"queue_info": h.crawlManager.GetQueueStatus(),
+		"url_id":      id,
+		"url":         url.URL,
+		"status":      url.Status,
+		"retry_count": url.RetryCount,
+		"created_at":  url.CreatedAt,
+		"updated_at":  url.UpdatedAt,
+		"queue_info":  h.crawlManager.GetQueueStatus(),
 	}
 
 	if url.ErrorMessage != nil {
 		statusResponse["error_message"] = *url.ErrorMessage
 	}
 
+	if url.ErrorType != nil {
+		statusResponse["error_type"] = *url.ErrorType
+	}
+
+	// A transient failure is waiting to be retried
+	if url.NextRetryAt != nil {
+		statusResponse["next_retry_at"] = *url.NextRetryAt
+	}
+
+	// The worker holding a running crawl, and since when
+	if url.WorkerID != nil {
+		statusResponse["worker_id"] = *url.WorkerID
+	}
+	if url.CrawlStartedAt != nil {
+		statusResponse["crawl_started_at"] = *url.CrawlStartedAt
+	}
+
 	// Include crawl result if available
 	if url.CrawlResult != nil {
 		statusResponse["crawl_result"] = dto.FromCrawlResult(url.CrawlResult)
@@ -161,7 +205,8 @@ func (h *CrawlHandler) GetCrawlStatus(c *gin.Context) {
 // POST /api/crawls/bulk
 func (h *CrawlHandler) StartBulkCrawl(c *gin.Context) {
 	var req struct {
-		URLIDs []uint `json:"url_ids" binding:"required,min=1"`
+		URLIDs   []uint               `json:"url_ids" binding:"required,min=1"`
+		Priority models.CrawlPriority `json:"priority" binding:"omitempty,oneof=high normal low"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -173,6 +218,10 @@ func (h *CrawlHandler) StartBulkCrawl(c *gin.Context) {
 		return
 	}
 
+	if req.Priority == "" {
+		req.Priority = models.PriorityNormal
+	}
+
 	// Limit bulk operation size
 	const maxBulkSize = 10
 	if len(req.URLIDs) > maxBulkSize {
@@ -221,20 +270,26 @@ func (h *CrawlHandler) StartBulkCrawl(c *gin.Context) {
 			result["status"] = "skipped"
 			result["reason"] = "already in progress"
 		} else {
+			// Mark the URL queued first, so a fast worker's final status is not overwritten
+			previousStatus := url.Status
+			previousError := url.ErrorMessage
+			if url.Status != models.StatusQueued {
+				updates := map[string]interface{}{
+					"status":        models.StatusQueued,
+					"error_message": nil,
+				}
+				database.DB.Model(&url).Updates(updates)
+			}
+
 			// Try to queue the URL
-			if err := h.crawlManager.QueueURL(url.ID, url.URL); err != nil {
+			if err := h.crawlManager.QueueURLWithPriority(url.ID, url.URL, req.Priority); err != nil {
+				database.DB.Model(&url).Updates(map[string]interface{}{
+					"status":        previousStatus,
+					"error_message": previousError,
+				})
 				result["status"] = "failed"
 				result["reason"] = err.Error()
 			} else {
-				// Update URL status to queued
-				if url.Status != models.StatusQueued {
-					updates := map[string]interface{}{
-						"status":        models.StatusQueued,
-						"error_message": nil,
-					}
-					database.DB.Model(&url).Updates(updates)
-				}
-
 				result["status"] = "queued"
 				successCount++
 			}
@@ -248,6 +303,7 @@ func (h *CrawlHandler) StartBulkCrawl(c *gin.Context) {
 		"total_urls":    len(urls),
 		"queued_count":  successCount,
 		"skipped_count": len(urls) - successCount,
+		"priority":      req.Priority,
 		"results":       queueResults,
 		"queue_info":    h.crawlManager.GetQueueStatus(),
 	}))
@@ -271,10 +327,102 @@ func (h *CrawlHandler) GetQueueStatus(c *gin.Context) {
 	database.DB.Model(&models.URL{}).Where("status = ?", models.StatusCompleted).Count(&stats.CompletedCount)
 	database.DB.Model(&models.URL{}).Where("status = ?", models.StatusError).Count(&stats.ErrorCount)
 
+	// Break failed URLs down by error type
+	var errorCounts []struct {
+		ErrorType *string
+		Count     int64
+	}
+	database.DB.Model(&models.URL{}).
+		Select("error_type, COUNT(*) AS count").
+		Where("status = ?", models.StatusError).
+		Group("error_type").
+		Scan(&errorCounts)
+
+	errorBreakdown := make(map[string]int64, len(errorCounts))
+	for _, row := range errorCounts {
+		errorType := "unknown" // Failed before error types were recorded
+		if row.ErrorType != nil {
+			errorType = *row.ErrorType
+		}
+		errorBreakdown[errorType] += row.Count
+	}
+
 	response := gin.H{
-		"queue_manager":  queueStatus,
-		"database_stats": stats,
+		"queue_manager":   queueStatus,
+		"database_stats":  stats,
+		"error_breakdown": errorBreakdown,
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(response))
+}
+
+// queuePauseRequest optionally limits a pause or resume to one host
+type queuePauseRequest struct {
+	Host string `json:"host"` // Host name or URL; empty = the whole queue
+}
+
+// PauseQueue stops the worker from starting new jobs, globally or for one host.
+// In-flight jobs finish and URLs can still be queued.
+// POST /api/crawls/queue/pause
+func (h *CrawlHandler) PauseQueue(c *gin.Context) {
+	h.setQueuePaused(c, true)
+}
+
+// ResumeQueue lets the worker take jobs again, globally or for one host
+// POST /api/crawls/queue/resume
+func (h *CrawlHandler) ResumeQueue(c *gin.Context) {
+	h.setQueuePaused(c, false)
+}
+
+// setQueuePaused pauses or resumes the queue or one host and responds with the queue status
+func (h *CrawlHandler) setQueuePaused(c *gin.Context, pause bool) {
+	var req queuePauseRequest
+
+	// The body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_REQUEST",
+				"Invalid request format",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	response := gin.H{}
+
+	if req.Host == "" {
+		var changed bool
+		if pause {
+			changed = h.crawlManager.Pause()
+		} else {
+			changed = h.crawlManager.Resume()
+		}
+		response["scope"] = "global"
+		response["changed"] = changed
+	} else {
+		var host string
+		var changed bool
+		var err error
+		if pause {
+			host, changed, err = h.crawlManager.PauseHost(req.Host)
+		} else {
+			host, changed, err = h.crawlManager.ResumeHost(req.Host)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_HOST",
+				"Invalid host",
+				err.Error(),
+			))
+			return
+		}
+		response["scope"] = "host"
+		response["host"] = host
+		response["changed"] = changed
 	}
 
+	response["queue_info"] = h.crawlManager.GetQueueStatus()
 	c.JSON(http.StatusOK, dto.SuccessResponse(response))
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"web-crawler/database"
+	"web-crawler/dto"
+	"web-crawler/models"
+	"web-crawler/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryHandler handles the audit log of stale crawls recovered by the reaper
+type RecoveryHandler struct {
+	reaper *services.Reaper
+}
+
+// NewRecoveryHandler creates a new recovery handler
+func NewRecoveryHandler(reaper *services.Reaper) *RecoveryHandler {
+	return &RecoveryHandler{
+		reaper: reaper,
+	}
+}
+
+// ListRecoveries returns the most recent recoveries, newest first, optionally for one URL
+// GET /api/admin/recoveries
+func (h *RecoveryHandler) ListRecoveries(c *gin.Context) {
+	query := database.DB.Order("id DESC").Limit(100)
+
+	if raw := c.Query("url_id"); raw != "" {
+		urlID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_ID",
+				"Invalid URL ID",
+				"url_id must be a positive integer",
+			))
+			return
+		}
+		query = query.Where("url_id = ?", urlID)
+	}
+
+	var recoveries []models.CrawlRecovery
+	if err := query.Find(&recoveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch recoveries",
+			err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"recoveries": dto.FromCrawlRecoveries(recoveries),
+	}))
+}
+
+// SweepStaleCrawls runs the reaper immediately instead of waiting for its next sweep
+// POST /api/admin/recoveries/sweep
+func (h *RecoveryHandler) SweepStaleCrawls(c *gin.Context) {
+	recoveries, err := h.reaper.Sweep()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"SWEEP_ERROR",
+			"Failed to sweep stale crawls",
+			err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"recovered":  len(recoveries),
+		"recoveries": dto.FromCrawlRecoveries(recoveries),
+	}))
+}
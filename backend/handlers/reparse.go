@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"web-crawler/database"
+	"web-crawler/dto"
+	"web-crawler/models"
+	"web-crawler/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReparseHandler handles admin jobs that re-parse stored snapshots
+type ReparseHandler struct {
+	reparser *services.Reparser
+}
+
+// NewReparseHandler creates a new re-parse handler
+func NewReparseHandler(reparser *services.Reparser) *ReparseHandler {
+	return &ReparseHandler{
+		reparser: reparser,
+	}
+}
+
+// StartReparse starts a job that re-parses the latest snapshot of every URL
+// POST /api/admin/reparse
+func (h *ReparseHandler) StartReparse(c *gin.Context) {
+	var req struct {
+		BatchSize int `json:"batch_size" binding:"omitempty,min=1,max=1000"`
+	}
+
+	// The body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_REQUEST",
+				"Invalid request format",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	job, err := h.reparser.StartJob(req.BatchSize)
+	if err != nil {
+		h.respondJobError(c, err, "Failed to start re-parse job")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse(dto.FromReparseJob(job)))
+}
+
+// ListReparseJobs returns re-parse jobs, newest first
+// GET /api/admin/reparse
+func (h *ReparseHandler) ListReparseJobs(c *gin.Context) {
+	var jobs []models.ReparseJob
+	if err := database.DB.Order("id DESC").Limit(50).Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch re-parse jobs",
+			err.Error(),
+		))
+		return
+	}
+
+	responses := make([]dto.ReparseJobResponse, len(jobs))
+	for i := range jobs {
+		responses[i] = dto.FromReparseJob(&jobs[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"jobs":       responses,
+		"is_running": h.reparser.IsRunning(),
+	}))
+}
+
+// GetReparseJob returns the progress of a re-parse job
+// GET /api/admin/reparse/:id
+func (h *ReparseHandler) GetReparseJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid job ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+
+	var job models.ReparseJob
+	result := database.DB.First(&job, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"JOB_NOT_FOUND",
+				"Re-parse job not found",
+				"",
+			))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch re-parse job",
+			result.Error.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(dto.FromReparseJob(&job)))
+}
+
+// ResumeReparseJob continues a failed or interrupted job from its last checkpoint
+// POST /api/admin/reparse/:id/resume
+func (h *ReparseHandler) ResumeReparseJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid job ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+
+	job, err := h.reparser.ResumeJob(uint(id))
+	if err != nil {
+		h.respondJobError(c, err, "Failed to resume re-parse job")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse(dto.FromReparseJob(job)))
+}
+
+// respondJobError maps re-parser errors to API error responses
+func (h *ReparseHandler) respondJobError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse(
+			"JOB_NOT_FOUND",
+			"Re-parse job not found",
+			"",
+		))
+	case errors.Is(err, services.ErrReparseRunning):
+		c.JSON(http.StatusConflict, dto.ErrorResponse(
+			"REPARSE_RUNNING",
+			"A re-parse job is already running",
+			"",
+		))
+	case errors.Is(err, services.ErrReparseCompleted):
+		c.JSON(http.StatusConflict, dto.ErrorResponse(
+			"REPARSE_COMPLETED",
+			"Re-parse job has already completed",
+			"Start a new job instead",
+		))
+	case errors.Is(err, services.ErrSnapshotsDisabled):
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse(
+			"SNAPSHOTS_DISABLED",
+			"Raw HTML snapshots are disabled",
+			"Set SNAPSHOTS_ENABLED=true to store snapshots",
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"REPARSE_ERROR",
+			message,
+			err.Error(),
+		))
+	}
+}
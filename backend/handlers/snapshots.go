@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"web-crawler/database"
+	"web-crawler/dto"
+	"web-crawler/models"
+	"web-crawler/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SnapshotHandler handles requests for stored raw HTML snapshots
+type SnapshotHandler struct {
+	store *services.SnapshotStore
+}
+
+// NewSnapshotHandler creates a new snapshot handler (store may be nil if snapshots are disabled)
+func NewSnapshotHandler(store *services.SnapshotStore) *SnapshotHandler {
+	return &SnapshotHandler{
+		store: store,
+	}
+}
+
+// ListSnapshots returns the stored crawls of a URL, newest first
+// GET /api/urls/:id/crawls
+func (h *SnapshotHandler) ListSnapshots(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid URL ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+
+	var url models.URL
+	result := database.DB.First(&url, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"URL_NOT_FOUND",
+				"URL not found",
+				"",
+			))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch URL",
+			result.Error.Error(),
+		))
+		return
+	}
+
+	var snapshots []models.PageSnapshot
+	if err := database.DB.Where("url_id = ?", id).Order("fetched_at DESC, id DESC").Find(&snapshots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch snapshots",
+			err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"url_id":    url.ID,
+		"url":       url.URL,
+		"snapshots": dto.FromSnapshots(snapshots),
+	}))
+}
+
+// GetRawSnapshot returns the raw HTML fetched by a crawl. It is served as
+// plain text so stored pages are never rendered on the API's origin.
+// GET /api/urls/:id/crawls/:crawlId/raw
+func (h *SnapshotHandler) GetRawSnapshot(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid URL ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+
+	crawlID, err := strconv.ParseUint(c.Param("crawlId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid crawl ID",
+			"Crawl ID must be a positive integer",
+		))
+		return
+	}
+
+	if h.store == nil {
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse(
+			"SNAPSHOTS_DISABLED",
+			"Raw HTML snapshots are disabled",
+			"Set SNAPSHOTS_ENABLED=true to store snapshots",
+		))
+		return
+	}
+
+	var snapshot models.PageSnapshot
+	result := database.DB.Where("id = ? AND url_id = ?", crawlID, id).First(&snapshot)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"SNAPSHOT_NOT_FOUND",
+				"Snapshot not found",
+				"",
+			))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch snapshot",
+			result.Error.Error(),
+		))
+		return
+	}
+
+	body, err := h.store.LoadBody(&snapshot)
+	if err != nil {
+		if errors.Is(err, services.ErrBlobNotFound) {
+			c.JSON(http.StatusGone, dto.ErrorResponse(
+				"SNAPSHOT_BLOB_MISSING",
+				"Snapshot content is no longer available",
+				"",
+			))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"STORAGE_ERROR",
+			"Failed to load snapshot",
+			err.Error(),
+		))
+		return
+	}
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Snapshot-Content-Hash", snapshot.ContentHash)
+	if c.Query("download") == "true" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="snapshot-%d-%d.html"`, id, crawlID))
+	}
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(body))
+}
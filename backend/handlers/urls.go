@@ -1,23 +1,30 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"web-crawler/database"
 	"web-crawler/dto"
 	"web-crawler/models"
+	"web-crawler/services"
 	
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // URLHandler handles URL-related requests
-type URLHandler struct{}
+type URLHandler struct {
+	snapshots *services.SnapshotStore
+}
 
-// NewURLHandler creates a new URL handler
-func NewURLHandler() *URLHandler {
-	return &URLHandler{}
+// NewURLHandler creates a new URL handler (snapshots may be nil if snapshots are disabled)
+func NewURLHandler(snapshots *services.SnapshotStore) *URLHandler {
+	return &URLHandler{
+		snapshots: snapshots,
+	}
 }
 
 // ListURLs returns a paginated list of URLs
@@ -47,14 +54,7 @@ func (h *URLHandler) ListURLs(c *gin.Context) {
 	query := database.DB.Model(&models.URL{}).Preload("CrawlResult")
 	
 	// Apply filters
-	if req.Status != "" {
-		query = query.Where("status = ?", req.Status)
-	}
-	
-	if req.Search != "" {
-		searchPattern := "%" + req.Search + "%"
-		query = query.Where("url LIKE ?", searchPattern)
-	}
+	query = req.Filter().Apply(query)
 	
 	// Get total count for pagination
 	var total int64
@@ -188,7 +188,7 @@ func (h *URLHandler) GetURL(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.SuccessResponse(dto.FromURL(&url)))
 }
 
-// GetURLDetails returns detailed URL information including found links
+// GetURLDetails returns detailed URL information including found links and the heading outline
 // GET /api/urls/:id/details
 func (h *URLHandler) GetURLDetails(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -201,10 +201,55 @@ func (h *URLHandler) GetURLDetails(c *gin.Context) {
 		return
 	}
 	
+	// Optional filters on the found links
+	region := c.Query("region")
+	if region != "" && !models.IsValidLinkRegion(region) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_PARAMS",
+			"Invalid region filter",
+			"region must be one of: "+strings.Join(models.LinkRegions, ", "),
+		))
+		return
+	}
+	rel := c.Query("rel")
+	if rel != "" && !models.IsValidLinkRel(rel) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_PARAMS",
+			"Invalid rel filter",
+			"rel must be one of: "+strings.Join(models.LinkRelValues, ", "),
+		))
+		return
+	}
+	var image *bool
+	if raw := c.Query("image"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_PARAMS",
+				"Invalid image filter",
+				"image must be true or false",
+			))
+			return
+		}
+		image = &value
+	}
+	
 	var url models.URL
 	result := database.DB.
 		Preload("CrawlResult").
-		Preload("FoundLinks").
+		Preload("FoundLinks", func(db *gorm.DB) *gorm.DB {
+			if region != "" {
+				db = db.Where("region = ?", region)
+			}
+			if rel != "" {
+				// Keywords are stored space-separated and none contains another
+				db = db.Where("rel LIKE ?", "%"+rel+"%")
+			}
+			if image != nil {
+				db = db.Where("is_image = ?", *image)
+			}
+			return db
+		}).
 		First(&url, id)
 	
 	if result.Error != nil {
@@ -227,13 +272,355 @@ func (h *URLHandler) GetURLDetails(c *gin.Context) {
 	
 	// Build detailed response
 	response := dto.URLDetailResponse{
-		URLResponse: dto.FromURL(&url),
-		FoundLinks:  dto.FromFoundLinks(url.FoundLinks),
+		URLResponse:    dto.FromURL(&url),
+		FoundLinks:     dto.FromFoundLinks(url.FoundLinks),
+		HeadingOutline: dto.FromHeadingOutline(url.CrawlResult),
 	}
 	
 	c.JSON(http.StatusOK, dto.SuccessResponse(response))
 }
 
+// GetSecurityHeaders returns the security header report from the URL's last crawl
+// GET /api/urls/:id/security-headers
+func (h *URLHandler) GetSecurityHeaders(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid URL ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+	
+	var url models.URL
+	result := database.DB.Preload("CrawlResult").First(&url, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"URL_NOT_FOUND",
+				"URL not found",
+				"",
+			))
+			return
+		}
+		
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch URL",
+			result.Error.Error(),
+		))
+		return
+	}
+	
+	if url.CrawlResult == nil || url.CrawlResult.SecurityHeadersScore == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse(
+			"REPORT_NOT_FOUND",
+			"No security header report available",
+			"Crawl the URL to generate a report",
+		))
+		return
+	}
+	
+	c.JSON(http.StatusOK, dto.SuccessResponse(dto.FromSecurityHeaders(&url, url.CrawlResult)))
+}
+
+// GetURLAttempts returns the URL's most recent crawl attempts, newest first
+// GET /api/urls/:id/attempts
+func (h *URLHandler) GetURLAttempts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid URL ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+	
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 50 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_LIMIT",
+				"Invalid limit",
+				"limit must be between 1 and 50",
+			))
+			return
+		}
+	}
+	
+	var url models.URL
+	result := database.DB.First(&url, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"URL_NOT_FOUND",
+				"URL not found",
+				"",
+			))
+			return
+		}
+		
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch URL",
+			result.Error.Error(),
+		))
+		return
+	}
+	
+	var attempts []models.CrawlAttempt
+	if err := database.DB.Where("url_id = ?", id).Order("id DESC").Limit(limit).Find(&attempts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch crawl attempts",
+			err.Error(),
+		))
+		return
+	}
+	
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"url_id":   url.ID,
+		"url":      url.URL,
+		"attempts": dto.FromCrawlAttempts(attempts),
+	}))
+}
+
+// GetURLImages returns the image inventory of the URL's last crawl, in document order
+// GET /api/urls/:id/images
+func (h *URLHandler) GetURLImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid URL ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+	
+	altStatus := c.Query("alt_status")
+	if altStatus != "" && !models.IsValidImageAltStatus(altStatus) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_PARAMS",
+			"Invalid alt_status filter",
+			"alt_status must be one of: ok, missing, empty, filename, duplicate",
+		))
+		return
+	}
+	
+	var url models.URL
+	result := database.DB.Preload("CrawlResult").First(&url, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"URL_NOT_FOUND",
+				"URL not found",
+				"",
+			))
+			return
+		}
+		
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch URL",
+			result.Error.Error(),
+		))
+		return
+	}
+	
+	if url.CrawlResult == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse(
+			"RESULT_NOT_FOUND",
+			"No crawl result available",
+			"Crawl the URL to inventory its images",
+		))
+		return
+	}
+	
+	query := database.DB.Where("url_id = ?", id)
+	if altStatus != "" {
+		query = query.Where("alt_status = ?", altStatus)
+	}
+	
+	var images []models.PageImage
+	if err := query.Order("position").Find(&images).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch page images",
+			err.Error(),
+		))
+		return
+	}
+	
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"url_id":  url.ID,
+		"url":     url.URL,
+		"summary": dto.FromImageSummary(url.CrawlResult),
+		"images":  dto.FromPageImages(images),
+	}))
+}
+
+// GetURLStructuredData returns the JSON-LD, microdata and RDFa items of the URL's last crawl
+// GET /api/urls/:id/structured-data
+func (h *URLHandler) GetURLStructuredData(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid URL ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+	
+	format := c.Query("format")
+	if format != "" && !models.IsValidStructuredDataFormat(format) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_PARAMS",
+			"Invalid format filter",
+			"format must be one of: json-ld, microdata, rdfa",
+		))
+		return
+	}
+	
+	var url models.URL
+	result := database.DB.Preload("CrawlResult").First(&url, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"URL_NOT_FOUND",
+				"URL not found",
+				"",
+			))
+			return
+		}
+		
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch URL",
+			result.Error.Error(),
+		))
+		return
+	}
+	
+	if url.CrawlResult == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse(
+			"RESULT_NOT_FOUND",
+			"No crawl result available",
+			"Crawl the URL to extract its structured data",
+		))
+		return
+	}
+	
+	query := database.DB.Where("url_id = ?", id)
+	if format != "" {
+		query = query.Where("format = ?", format)
+	}
+	
+	var items []models.StructuredData
+	if err := query.Order("position").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch structured data",
+			err.Error(),
+		))
+		return
+	}
+	
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"url_id":  url.ID,
+		"url":     url.URL,
+		"summary": dto.FromStructuredDataSummary(url.CrawlResult),
+		"items":   dto.FromStructuredData(items),
+	}))
+}
+
+// GetURLForms returns the forms inventory of the URL's last crawl, in document order
+// GET /api/urls/:id/forms
+func (h *URLHandler) GetURLForms(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+			"INVALID_ID",
+			"Invalid URL ID",
+			"ID must be a positive integer",
+		))
+		return
+	}
+	
+	var login *bool
+	if raw := c.Query("login"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse(
+				"INVALID_PARAMS",
+				"Invalid login filter",
+				"login must be true or false",
+			))
+			return
+		}
+		login = &value
+	}
+	
+	var url models.URL
+	result := database.DB.Preload("CrawlResult").First(&url, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse(
+				"URL_NOT_FOUND",
+				"URL not found",
+				"",
+			))
+			return
+		}
+		
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch URL",
+			result.Error.Error(),
+		))
+		return
+	}
+	
+	if url.CrawlResult == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse(
+			"RESULT_NOT_FOUND",
+			"No crawl result available",
+			"Crawl the URL to inventory its forms",
+		))
+		return
+	}
+	
+	query := database.DB.Where("url_id = ?", id)
+	if login != nil {
+		if *login {
+			query = query.Where("login_confidence >= ?", models.LoginFormThreshold)
+		} else {
+			query = query.Where("login_confidence < ?", models.LoginFormThreshold)
+		}
+	}
+	
+	var forms []models.PageForm
+	if err := query.Order("position").Find(&forms).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to fetch page forms",
+			err.Error(),
+		))
+		return
+	}
+	
+	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
+		"url_id":  url.ID,
+		"url":     url.URL,
+		"summary": dto.FromFormSummary(url.CrawlResult),
+		"forms":   dto.FromPageForms(forms),
+	}))
+}
+
 // DeleteURL deletes a URL and all related data
 // DELETE /api/urls/:id
 func (h *URLHandler) DeleteURL(c *gin.Context) {
@@ -268,6 +655,16 @@ func (h *URLHandler) DeleteURL(c *gin.Context) {
 		return
 	}
 	
+	snapshotKeys, err := h.snapshotKeys([]uint{url.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to load URL snapshots",
+			err.Error(),
+		))
+		return
+	}
+	
 	// Delete URL (cascading delete will handle related records)
 	if err := database.DB.Delete(&url).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
@@ -278,6 +675,8 @@ func (h *URLHandler) DeleteURL(c *gin.Context) {
 		return
 	}
 	
+	h.removeSnapshotBlobs(snapshotKeys)
+	
 	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
 		"message": "URL deleted successfully",
 		"id":      id,
@@ -300,6 +699,16 @@ func (h *URLHandler) BulkDeleteURLs(c *gin.Context) {
 		return
 	}
 	
+	snapshotKeys, err := h.snapshotKeys(req.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse(
+			"DATABASE_ERROR",
+			"Failed to load URL snapshots",
+			err.Error(),
+		))
+		return
+	}
+	
 	// Delete URLs in transaction
 	tx := database.DB.Begin()
 	defer func() {
@@ -321,9 +730,31 @@ func (h *URLHandler) BulkDeleteURLs(c *gin.Context) {
 	
 	tx.Commit()
 	
+	h.removeSnapshotBlobs(snapshotKeys)
+	
 	c.JSON(http.StatusOK, dto.SuccessResponse(gin.H{
 		"message":       "URLs deleted successfully",
 		"deleted_count": result.RowsAffected,
 		"ids":           req.IDs,
 	}))
-}
\ No newline at end of file
+}
+
+// snapshotKeys returns the snapshot blob keys of URLs about to be deleted
+func (h *URLHandler) snapshotKeys(ids []uint) ([]string, error) {
+	if h.snapshots == nil {
+		return nil, nil
+	}
+	return h.snapshots.StorageKeys(ids)
+}
+
+// removeSnapshotBlobs deletes the blobs that the deleted URLs' snapshots were
+// the last to reference. The snapshot rows go with the URLs through the cascade;
+// the URLs are already deleted, so a failure is only logged.
+func (h *URLHandler) removeSnapshotBlobs(keys []string) {
+	if h.snapshots == nil || len(keys) == 0 {
+		return
+	}
+	if _, err := h.snapshots.RemoveUnreferencedBlobs(keys); err != nil {
+		log.Printf("Failed to remove snapshot blobs of deleted URLs: %v", err)
+	}
+}
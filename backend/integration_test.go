@@ -3,15 +3,20 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"web-crawler/database"
 	"web-crawler/dto"
 	"web-crawler/handlers"
 	"web-crawler/middleware"
 	"web-crawler/models"
+	"web-crawler/services"
 	
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/sqlite"
@@ -31,7 +36,13 @@ func setupTestDB(t *testing.T) {
 		&models.URL{},
 		&models.CrawlResult{},
 		&models.FoundLink{},
+		&models.PageImage{},
+		&models.StructuredData{},
+		&models.PageForm{},
 		&models.APIToken{},
+		&models.CrawlAttempt{},
+		&models.CrawlWorker{},
+		&models.QueuePause{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
@@ -53,7 +64,7 @@ func setupTestRouter() *gin.Engine {
 	
 	// Add auth middleware to protected routes
 	authHandler := handlers.NewAuthHandler()
-	urlHandler := handlers.NewURLHandler()
+	urlHandler := handlers.NewURLHandler(nil)
 	
 	// Public routes
 	public := router.Group("/api")
@@ -225,4 +236,148 @@ func TestInvalidToken(t *testing.T) {
 	if w.Code != http.StatusUnauthorized {
 		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, w.Code)
 	}
-}
\ No newline at end of file
+}
+// setupCrawlTestRouter creates a test router with crawl routes backed by recorded fixtures
+func setupCrawlTestRouter(t *testing.T, fixturesDir string) *gin.Engine {
+	// The crawl worker runs on its own goroutine; share the in-memory database with it
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		t.Fatalf("Failed to access test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	
+	fetcher, err := services.NewFixtureFetcher(fixturesDir, nil)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+	
+	config := services.DefaultManagerConfig()
+	config.Crawler = &services.CrawlerConfig{RateLimit: 0}
+	config.Fetcher = fetcher
+	crawlManager := services.NewCrawlManagerWithConfig(config)
+	crawlManager.Start()
+	t.Cleanup(crawlManager.Stop)
+	
+	router := setupTestRouter()
+	crawlHandler := handlers.NewCrawlHandler(crawlManager)
+	
+	protected := router.Group("/api")
+	protected.Use(middleware.AuthMiddleware())
+	{
+		protected.GET("/urls/:id/details", handlers.NewURLHandler(nil).GetURLDetails)
+		protected.GET("/urls/:id/attempts", handlers.NewURLHandler(nil).GetURLAttempts)
+		protected.POST("/urls/:id/crawl", crawlHandler.StartCrawl)
+		protected.GET("/urls/:id/crawl/status", crawlHandler.GetCrawlStatus)
+	}
+	
+	return router
+}
+
+func TestCrawl_EndToEndWithFixtures(t *testing.T) {
+	setupTestDB(t)
+	
+	// Record a page to be served instead of the network
+	fixturesDir := t.TempDir()
+	os.WriteFile(filepath.Join(fixturesDir, "example.json"),
+		[]byte(`{"url": "https://example.com", "headers": {"Content-Type": "text/html"}}`), 0o644)
+	os.WriteFile(filepath.Join(fixturesDir, "example.html"),
+		[]byte(`<html><head><title>Recorded</title></head><body><h1>Hi</h1><a href="/docs">Docs</a></body></html>`), 0o644)
+	
+	router := setupCrawlTestRouter(t, fixturesDir)
+	
+	url := models.URL{URL: "https://example.com", Status: models.StatusCompleted}
+	database.DB.Create(&url)
+	
+	// Start the crawl
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/urls/%d/crawl", url.ID), nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	
+	// Poll the status endpoint until the crawl finishes
+	var status string
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/urls/%d/crawl/status", url.ID), nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+		
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		
+		var response struct {
+			Data struct {
+				Status string `json:"status"`
+			} `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		
+		status = response.Data.Status
+		if status == string(models.StatusCompleted) || status == string(models.StatusError) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	
+	if status != string(models.StatusCompleted) {
+		t.Fatalf("Expected crawl to complete, got status %q", status)
+	}
+	
+	// Check the parsed results through the details endpoint
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/api/urls/%d/details", url.ID), nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	
+	var details struct {
+		Data dto.URLDetailResponse `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &details)
+	
+	result := details.Data.CrawlResult
+	if result == nil {
+		t.Fatal("Expected crawl result in details")
+	}
+	if result.PageTitle == nil || *result.PageTitle != "Recorded" {
+		t.Errorf("Expected page title 'Recorded', got %v", result.PageTitle)
+	}
+	if len(details.Data.FoundLinks) != 1 || details.Data.FoundLinks[0].LinkURL != "https://example.com/docs" {
+		t.Errorf("Expected one link to https://example.com/docs, got %+v", details.Data.FoundLinks)
+	}
+	
+	// The attempt log is finalized right after the URL completes
+	var attempts []dto.CrawlAttemptResponse
+	deadline = time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/urls/%d/attempts", url.ID), nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+		
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		
+		var response struct {
+			Data struct {
+				Attempts []dto.CrawlAttemptResponse `json:"attempts"`
+			} `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		
+		attempts = response.Data.Attempts
+		if len(attempts) > 0 && attempts[0].Outcome != models.AttemptRunning {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	
+	if len(attempts) != 1 || attempts[0].Outcome != models.AttemptCompleted {
+		t.Fatalf("Expected one completed attempt, got %+v", attempts)
+	}
+	if attempts[0].StatusCode == nil || *attempts[0].StatusCode != http.StatusOK || len(attempts[0].LogLines) == 0 {
+		t.Errorf("Expected status 200 and log lines, got %+v", attempts[0])
+	}
+}
@@ -27,10 +27,27 @@ func main() {
 	defer database.Close()
 
 	// Initialize and start crawl manager
-	crawlManager := services.NewCrawlManager()
+	managerConfig, err := services.ManagerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure crawl manager: %v", err)
+	}
+	crawlManager := services.NewCrawlManagerWithConfig(managerConfig)
 	crawlManager.Start()
 	defer crawlManager.Stop()
 
+	// Resume a snapshot re-parse job interrupted by a restart
+	reparser := services.NewReparser(crawlManager)
+	reparser.ResumeInterrupted()
+
+	// Continue batch crawls interrupted by a restart
+	batchCrawler := services.NewBatchCrawler(crawlManager)
+	batchCrawler.ResumeInterrupted()
+
+	// Recover URLs left "running" by a crashed or hung worker
+	reaper := services.NewReaper(crawlManager, services.ReaperConfigFromEnv())
+	reaper.Start()
+	defer reaper.Stop()
+
 	// Set up graceful shutdown
 	setupGracefulShutdown(crawlManager)
 
@@ -58,8 +75,13 @@ func main() {
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler()
-	urlHandler := handlers.NewURLHandler()
+	urlHandler := handlers.NewURLHandler(crawlManager.SnapshotStore())
 	crawlHandler := handlers.NewCrawlHandler(crawlManager)
+	certificateHandler := handlers.NewCertificateHandler()
+	snapshotHandler := handlers.NewSnapshotHandler(crawlManager.SnapshotStore())
+	reparseHandler := handlers.NewReparseHandler(reparser)
+	recoveryHandler := handlers.NewRecoveryHandler(reaper)
+	batchHandler := handlers.NewBatchHandler(batchCrawler)
 
 	// Health check endpoint (no auth required)
 	router.GET("/health", func(c *gin.Context) {
@@ -100,19 +122,49 @@ func main() {
 			urls.POST("", urlHandler.AddURL)
 			urls.GET("/:id", urlHandler.GetURL)
 			urls.GET("/:id/details", urlHandler.GetURLDetails)
+			urls.GET("/:id/security-headers", urlHandler.GetSecurityHeaders)
+			urls.GET("/:id/attempts", urlHandler.GetURLAttempts)
+			urls.GET("/:id/images", urlHandler.GetURLImages)
+			urls.GET("/:id/structured-data", urlHandler.GetURLStructuredData)
+			urls.GET("/:id/forms", urlHandler.GetURLForms)
 			urls.DELETE("/:id", urlHandler.DeleteURL)
 			urls.DELETE("/bulk", urlHandler.BulkDeleteURLs)
 
 			// Crawl control routes
 			urls.POST("/:id/crawl", crawlHandler.StartCrawl)
 			urls.GET("/:id/crawl/status", crawlHandler.GetCrawlStatus)
+
+			// Crawl history and raw HTML snapshots
+			urls.GET("/:id/crawls", snapshotHandler.ListSnapshots)
+			urls.GET("/:id/crawls/:crawlId/raw", snapshotHandler.GetRawSnapshot)
 		}
 
 		// Crawl management routes
 		crawls := protected.Group("/crawls")
 		{
 			crawls.POST("/bulk", crawlHandler.StartBulkCrawl)
+			crawls.POST("/batch", batchHandler.StartBatch)
+			crawls.GET("/batch/:id", batchHandler.GetBatch)
 			crawls.GET("/queue/status", crawlHandler.GetQueueStatus)
+			crawls.POST("/queue/pause", crawlHandler.PauseQueue)
+			crawls.POST("/queue/resume", crawlHandler.ResumeQueue)
+		}
+
+		// Certificate monitoring routes
+		certificates := protected.Group("/certificates")
+		{
+			certificates.GET("/expiring", certificateHandler.ListExpiringCertificates)
+		}
+
+		// Admin routes
+		admin := protected.Group("/admin")
+		{
+			admin.POST("/reparse", reparseHandler.StartReparse)
+			admin.GET("/reparse", reparseHandler.ListReparseJobs)
+			admin.GET("/reparse/:id", reparseHandler.GetReparseJob)
+			admin.POST("/reparse/:id/resume", reparseHandler.ResumeReparseJob)
+			admin.GET("/recoveries", recoveryHandler.ListRecoveries)
+			admin.POST("/recoveries/sweep", recoveryHandler.SweepStaleCrawls)
 		}
 	}
 
@@ -128,18 +180,40 @@ func main() {
 					"me":       "GET /api/auth/me (auth required)",
 				},
 				"urls": gin.H{
-					"list":         "GET /api/urls (auth required)",
-					"create":       "POST /api/urls (auth required)",
-					"get":          "GET /api/urls/:id (auth required)",
-					"details":      "GET /api/urls/:id/details (auth required)",
-					"delete":       "DELETE /api/urls/:id (auth required)",
-					"bulk_delete":  "DELETE /api/urls/bulk (auth required)",
-					"start_crawl":  "POST /api/urls/:id/crawl (auth required)",
-					"crawl_status": "GET /api/urls/:id/crawl/status (auth required)",
+					"list":             "GET /api/urls (auth required)",
+					"create":           "POST /api/urls (auth required)",
+					"get":              "GET /api/urls/:id (auth required)",
+					"details":          "GET /api/urls/:id/details (auth required)",
+					"security_headers": "GET /api/urls/:id/security-headers (auth required)",
+					"attempts":         "GET /api/urls/:id/attempts (auth required)",
+					"images":           "GET /api/urls/:id/images (auth required)",
+					"structured_data":  "GET /api/urls/:id/structured-data (auth required)",
+					"forms":            "GET /api/urls/:id/forms (auth required)",
+					"delete":           "DELETE /api/urls/:id (auth required)",
+					"bulk_delete":      "DELETE /api/urls/bulk (auth required)",
+					"start_crawl":      "POST /api/urls/:id/crawl (auth required)",
+					"crawl_status":     "GET /api/urls/:id/crawl/status (auth required)",
+					"crawls":           "GET /api/urls/:id/crawls (auth required)",
+					"raw_snapshot":     "GET /api/urls/:id/crawls/:crawlId/raw (auth required)",
 				},
 				"crawls": gin.H{
 					"bulk_crawl":   "POST /api/crawls/bulk (auth required)",
+					"batch_crawl":  "POST /api/crawls/batch (auth required)",
+					"batch_status": "GET /api/crawls/batch/:id (auth required)",
 					"queue_status": "GET /api/crawls/queue/status (auth required)",
+					"pause_queue":  "POST /api/crawls/queue/pause (auth required)",
+					"resume_queue": "POST /api/crawls/queue/resume (auth required)",
+				},
+				"certificates": gin.H{
+					"expiring": "GET /api/certificates/expiring?days=30 (auth required)",
+				},
+				"admin": gin.H{
+					"start_reparse":  "POST /api/admin/reparse (auth required)",
+					"reparse_jobs":   "GET /api/admin/reparse (auth required)",
+					"reparse_job":    "GET /api/admin/reparse/:id (auth required)",
+					"resume_reparse": "POST /api/admin/reparse/:id/resume (auth required)",
+					"recoveries":     "GET /api/admin/recoveries (auth required)",
+					"sweep_stale":    "POST /api/admin/recoveries/sweep (auth required)",
 				},
 			},
 			"authentication": gin.H{
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// CertificateInfo describes one certificate from a server's TLS chain
+type CertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	DNSNames     []string  `json:"dns_names"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	IsCA         bool      `json:"is_ca"`
+}
+
+// DaysUntilExpiry returns the number of whole days until the certificate expires
+// (negative if it has already expired)
+func (ci *CertificateInfo) DaysUntilExpiry(now time.Time) int {
+	return int(ci.NotAfter.Sub(now).Hours() / 24)
+}
+
+// IsExpired checks if the certificate is past its validity period
+func (ci *CertificateInfo) IsExpired(now time.Time) bool {
+	return now.After(ci.NotAfter)
+}
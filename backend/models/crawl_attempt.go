@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// CrawlAttemptOutcome represents how a single crawl attempt ended
+type CrawlAttemptOutcome string
+
+const (
+	AttemptRunning     CrawlAttemptOutcome = "running"      // Still in progress
+	AttemptCompleted   CrawlAttemptOutcome = "completed"    // Page fetched, parsed and saved
+	AttemptNotModified CrawlAttemptOutcome = "not_modified" // Server answered 304 Not Modified
+	AttemptRetrying    CrawlAttemptOutcome = "retrying"     // Failed, another attempt is scheduled
+	AttemptFailed      CrawlAttemptOutcome = "failed"       // Failed, no more attempts
+	AttemptAbandoned   CrawlAttemptOutcome = "abandoned"    // Never finished; closed by the stale crawl reaper
+)
+
+// CrawlAttempt records one attempt to crawl a URL, including retries.
+// The row is created when the attempt starts and completed when it ends.
+type CrawlAttempt struct {
+	ID           uint                `json:"id" gorm:"primaryKey"`
+	URLID        uint                `json:"url_id" gorm:"not null;index"`
+	Attempt      int                 `json:"attempt"` // 1 for the first attempt, incremented on each retry
+	WorkerID     string              `json:"worker_id" gorm:"type:varchar(100)"`
+	Outcome      CrawlAttemptOutcome `json:"outcome" gorm:"type:varchar(20);not null;default:'running';index"`
+	StartedAt    time.Time           `json:"started_at" gorm:"index"`
+	FinishedAt   *time.Time          `json:"finished_at"`
+	DurationMs   *int                `json:"duration_ms"`
+	FinalURL     *string             `json:"final_url" gorm:"type:varchar(2048)"`
+	StatusCode   *int                `json:"status_code"`
+	Bytes        *int64              `json:"bytes"`
+	ErrorType    *string             `json:"error_type" gorm:"type:varchar(50)"`
+	ErrorMessage *string             `json:"error_message" gorm:"type:text"`
+	LogLines     []string            `json:"log_lines" gorm:"type:mediumtext;serializer:json"` // Bounded log of fetch, parse and save; a full log can exceed 64KB
+
+	// Relationships
+	URL *URL `json:"url,omitempty" gorm:"foreignKey:URLID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName overrides the table name
+func (CrawlAttempt) TableName() string {
+	return "crawl_attempts"
+}
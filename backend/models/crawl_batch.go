@@ -0,0 +1,88 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// CrawlBatchStatus represents the state of a batch crawl
+type CrawlBatchStatus string
+
+const (
+	BatchPending   CrawlBatchStatus = "pending"   // Created; matching URLs not resolved yet
+	BatchRunning   CrawlBatchStatus = "running"   // Queueing its URLs
+	BatchCompleted CrawlBatchStatus = "completed" // Every URL was queued, skipped or failed
+	BatchFailed    CrawlBatchStatus = "failed"    // Stopped early; see LastError
+)
+
+// CrawlBatchItemOutcome is what happened to one URL of a batch
+type CrawlBatchItemOutcome string
+
+const (
+	BatchItemPending CrawlBatchItemOutcome = "pending" // Not queued yet
+	BatchItemQueued  CrawlBatchItemOutcome = "queued"
+	BatchItemSkipped CrawlBatchItemOutcome = "skipped" // Already running, or deleted before it was queued
+	BatchItemFailed  CrawlBatchItemOutcome = "failed"
+)
+
+// CrawlBatch is a request to crawl many URLs, selected by ID or by a URL
+// filter. Its URLs are resolved into CrawlBatchItems when it starts and
+// are then queued in the background, waiting for room when the queue is full.
+type CrawlBatch struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	Status     CrawlBatchStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	Priority   CrawlPriority    `json:"priority" gorm:"type:varchar(10);not null"`
+	URLIDs     []uint           `json:"url_ids,omitempty" gorm:"type:text;serializer:json"` // Requested IDs, if selected by ID
+	Filter     *URLFilter       `json:"filter,omitempty" gorm:"type:text;serializer:json"`  // Requested filter, if selected by filter
+	Total      int              `json:"total"`                                              // URLs matched when the batch started
+	Processed  int              `json:"processed"`                                          // Queued + Skipped + Failed
+	Queued     int              `json:"queued"`
+	Skipped    int              `json:"skipped"`
+	Failed     int              `json:"failed"`
+	LastError  *string          `json:"last_error" gorm:"type:text"`
+	StartedAt  *time.Time       `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at"`
+	CreatedAt  time.Time        `json:"created_at" gorm:"index"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (CrawlBatch) TableName() string {
+	return "crawl_batches"
+}
+
+// Progress returns the processed fraction of the batch as a percentage, rounded to two decimals
+func (b *CrawlBatch) Progress() float64 {
+	if b.Total == 0 {
+		if b.Status == BatchCompleted {
+			return 100
+		}
+		return 0
+	}
+	progress := float64(b.Processed) / float64(b.Total) * 100
+	if progress > 100 {
+		progress = 100
+	}
+	return math.Round(progress*100) / 100
+}
+
+// CrawlBatchItem is one URL of a batch and what happened when it was queued.
+// It has no foreign key to urls so the batch history survives URL deletion.
+type CrawlBatchItem struct {
+	ID        uint                  `json:"id" gorm:"primaryKey"`
+	BatchID   uint                  `json:"batch_id" gorm:"not null;index:idx_batch_outcome"`
+	URLID     uint                  `json:"url_id" gorm:"not null;index"`
+	URL       string                `json:"url" gorm:"type:varchar(2048)"`
+	Outcome   CrawlBatchItemOutcome `json:"outcome" gorm:"type:varchar(20);not null;default:'pending';index:idx_batch_outcome"`
+	Reason    *string               `json:"reason,omitempty" gorm:"type:text"` // Why the URL was skipped or failed
+	QueuedAt  *time.Time            `json:"queued_at,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+
+	Batch *CrawlBatch `json:"-" gorm:"foreignKey:BatchID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName overrides the table name
+func (CrawlBatchItem) TableName() string {
+	return "crawl_batch_items"
+}
@@ -0,0 +1,31 @@
+package models
+
+// CrawlErrorTypes lists the error types recorded in urls.error_type when a crawl fails
+var CrawlErrorTypes = []string{
+	"timeout",          // Request or body read timed out
+	"dns_error",        // Host name could not be resolved
+	"connection_error", // Connection refused, reset or failed to dial
+	"tls_error",        // Certificate verification failed
+	"redirect_error",   // Too many redirects
+	"network",          // Any other network failure
+	"http_error",       // Non-2xx HTTP status
+	"invalid_url",      // URL could not be requested
+	"invalid_content",  // Response is not HTML
+	"too_large",        // Response exceeds the page size limit
+	"read_error",       // Response body could not be read
+	"parse_error",      // HTML could not be parsed
+	"database_error",   // Results could not be saved
+	"queue_error",      // A retry could not be queued
+	"abandoned",        // Crawl stopped without finishing and was recovered by the reaper
+	"panic",            // Crawl job panicked; the message includes a stack excerpt
+}
+
+// IsValidCrawlErrorType checks if the error type is one of CrawlErrorTypes
+func IsValidCrawlErrorType(errorType string) bool {
+	for _, t := range CrawlErrorTypes {
+		if t == errorType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package models
+
+// CrawlPriority orders crawl jobs in the queue
+type CrawlPriority string
+
+const (
+	PriorityHigh   CrawlPriority = "high"   // Interactive crawls a user is waiting on
+	PriorityNormal CrawlPriority = "normal" // Default for API-triggered crawls
+	PriorityLow    CrawlPriority = "low"    // Background and system-initiated crawls
+)
+
+// CrawlPriorities lists the priorities from highest to lowest
+var CrawlPriorities = []CrawlPriority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// IsValidCrawlPriority checks if the priority is one of CrawlPriorities
+func IsValidCrawlPriority(priority string) bool {
+	for _, p := range CrawlPriorities {
+		if string(p) == priority {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// CrawlRecoveryReason explains why the reaper took a crawl away from its worker
+type CrawlRecoveryReason string
+
+const (
+	RecoveryLeaseExpired CrawlRecoveryReason = "lease_expired" // Running for longer than the lease timeout
+	RecoveryWorkerLost   CrawlRecoveryReason = "worker_lost"   // Owning worker stopped sending heartbeats
+	RecoveryNoOwner      CrawlRecoveryReason = "no_owner"      // Running without a recorded worker
+)
+
+// CrawlRecoveryAction is what the reaper did with a stale crawl
+type CrawlRecoveryAction string
+
+const (
+	RecoveryRequeued CrawlRecoveryAction = "requeued"
+	RecoveryFailed   CrawlRecoveryAction = "failed"
+)
+
+// CrawlRecovery is an audit record of a stale "running" URL recovered by the reaper.
+// It has no foreign key so the record outlives the URL.
+type CrawlRecovery struct {
+	ID             uint                `json:"id" gorm:"primaryKey"`
+	URLID          uint                `json:"url_id" gorm:"not null;index"`
+	URL            string              `json:"url" gorm:"type:varchar(2048)"`
+	WorkerID       *string             `json:"worker_id" gorm:"type:varchar(100)"` // Worker that held the crawl
+	CrawlStartedAt *time.Time          `json:"crawl_started_at"`
+	Reason         CrawlRecoveryReason `json:"reason" gorm:"type:varchar(20);not null"`
+	Action         CrawlRecoveryAction `json:"action" gorm:"type:varchar(20);not null"`
+	Message        string              `json:"message" gorm:"type:text"`
+	ReaperID       string              `json:"reaper_id" gorm:"type:varchar(100)"` // Worker whose reaper made the recovery
+	CreatedAt      time.Time           `json:"created_at" gorm:"index"`
+}
+
+// TableName overrides the table name
+func (CrawlRecovery) TableName() string {
+	return "crawl_recoveries"
+}
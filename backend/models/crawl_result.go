@@ -6,6 +6,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// ChangeStatus describes how a page changed relative to its previous crawl
+type ChangeStatus string
+
+const (
+	ChangeNew        ChangeStatus = "new"            // First crawl, nothing to compare against
+	ChangeChanged    ChangeStatus = "changed"        // Visible text changed
+	ChangeMarkupOnly ChangeStatus = "markup_changed" // HTML changed but visible text did not
+	ChangeUnchanged  ChangeStatus = "unchanged"      // Identical content (or 304 Not Modified)
+)
+
 // CrawlResult stores the extracted data from crawling a URL
 type CrawlResult struct {
 	ID    uint `json:"id" gorm:"primaryKey"`
@@ -23,16 +33,104 @@ type CrawlResult struct {
 	InternalLinksCount     int     `json:"internal_links_count" gorm:"default:0"`
 	ExternalLinksCount     int     `json:"external_links_count" gorm:"default:0"`
 	InaccessibleLinksCount int     `json:"inaccessible_links_count" gorm:"default:0"`
-	HasLoginForm           bool    `json:"has_login_form" gorm:"default:false"`
+	SavedLinksCount        int     `json:"saved_links_count" gorm:"default:0"`   // Links stored in found_links
+	LinksTruncated         bool    `json:"links_truncated" gorm:"default:false"` // Links beyond the per-page limit were not stored
+
+	// Heading outline (h1-h6 in document order, up to 500)
+	Headings           []Heading `json:"headings" gorm:"type:mediumtext;serializer:json"`
+	HeadingIssuesCount int       `json:"heading_issues_count" gorm:"default:0"` // Headings with at least one issue
+
+	// Meta tags
+	MetaDescription *string             `json:"meta_description" gorm:"type:text"`
+	MetaRobots      *string             `json:"meta_robots" gorm:"type:varchar(255)"`      // Lower-cased robots meta content
+	RobotsNoindex   bool                `json:"robots_noindex" gorm:"default:false;index"` // noindex or none
+	RobotsNofollow  bool                `json:"robots_nofollow" gorm:"default:false"`      // nofollow or none
+	CanonicalURL    *string             `json:"canonical_url" gorm:"type:varchar(2048)"`   // Resolved <link rel="canonical">
+	Viewport        *string             `json:"viewport" gorm:"type:varchar(255)"`
+	ThemeColor      *string             `json:"theme_color" gorm:"type:varchar(50)"`
+	OpenGraph       map[string][]string `json:"open_graph" gorm:"type:mediumtext;serializer:json"`   // og:* properties
+	TwitterTags     map[string][]string `json:"twitter_tags" gorm:"type:mediumtext;serializer:json"` // twitter:* properties
+
+	// Structured data summary (the items themselves are in structured_data)
+	StructuredDataCount      int      `json:"structured_data_count" gorm:"default:0"`
+	StructuredDataErrorCount int      `json:"structured_data_error_count" gorm:"default:0"`  // JSON-LD blocks that are not valid JSON
+	SchemaTypes              []string `json:"schema_types" gorm:"type:text;serializer:json"` // Distinct schema.org types, sorted
+
+	// Forms inventory summary (the forms themselves are in page_forms)
+	FormsCount         int           `json:"forms_count" gorm:"default:0"`
+	InsecureFormsCount int           `json:"insecure_forms_count" gorm:"default:0"`          // Forms submitting over HTTP from an HTTPS page
+	LoginConfidence    int           `json:"login_confidence" gorm:"default:0;index"`        // 0-100, from the strongest login form and sign-in buttons
+	LoginSignals       []LoginSignal `json:"login_signals" gorm:"type:text;serializer:json"` // Evidence behind LoginConfidence
+
+	// Image inventory summary (the images themselves are in page_images)
+	ImagesCount                  int `json:"images_count" gorm:"default:0"`
+	ImagesMissingAltCount        int `json:"images_missing_alt_count" gorm:"default:0"`        // No alt attribute
+	ImagesEmptyAltCount          int `json:"images_empty_alt_count" gorm:"default:0"`          // alt="" (decorative)
+	ImagesPoorAltCount           int `json:"images_poor_alt_count" gorm:"default:0"`           // File name or duplicate alt
+	ImagesMissingDimensionsCount int `json:"images_missing_dimensions_count" gorm:"default:0"` // width or height missing
+	ImagesLazyCount              int `json:"images_lazy_count" gorm:"default:0"`
 
 	// Metadata
 	CrawledAt       time.Time `json:"crawled_at" gorm:"index"`
 	CrawlDurationMs *int      `json:"crawl_duration_ms"`
 
+	// Timing breakdown (NULL = phase not measured, e.g. TLS on plain HTTP)
+	DNSLookupMs       *int `json:"dns_lookup_ms"`
+	TCPConnectMs      *int `json:"tcp_connect_ms"`
+	TLSHandshakeMs    *int `json:"tls_handshake_ms"`
+	TimeToFirstByteMs *int `json:"time_to_first_byte_ms"`
+	DownloadMs        *int `json:"download_ms"`
+	FetchDurationMs   *int `json:"fetch_duration_ms"`
+	ParseDurationMs   *int `json:"parse_duration_ms"`
+
+	// TLS details (NULL for plain HTTP)
+	TLSVersion     *string           `json:"tls_version" gorm:"type:varchar(20)"`
+	TLSCipherSuite *string           `json:"tls_cipher_suite" gorm:"type:varchar(100)"`
+	CertSubject    *string           `json:"cert_subject" gorm:"type:varchar(500)"`
+	CertIssuer     *string           `json:"cert_issuer" gorm:"type:varchar(500)"`
+	CertNotBefore  *time.Time        `json:"cert_not_before"`
+	CertNotAfter   *time.Time        `json:"cert_not_after" gorm:"index"`
+	CertChain      []CertificateInfo `json:"cert_chain" gorm:"type:text;serializer:json"` // Leaf first
+
+	// Response headers and security header audit
+	ResponseHeaders        map[string][]string     `json:"response_headers" gorm:"type:text;serializer:json"`
+	SecurityHeadersScore   *int                    `json:"security_headers_score" gorm:"index"`
+	SecurityHeadersGrade   *string                 `json:"security_headers_grade" gorm:"type:varchar(2)"`
+	SecurityHeaderFindings []SecurityHeaderFinding `json:"security_header_findings" gorm:"type:text;serializer:json"`
+
+	// Conditional re-crawl validators
+	ETag              *string    `json:"etag" gorm:"type:varchar(255)"`
+	LastModified      *string    `json:"last_modified" gorm:"type:varchar(100)"`
+	LastCheckedAt     *time.Time `json:"last_checked_at"`                      // Last crawl, including 304 checks
+	NotModifiedChecks int        `json:"not_modified_checks" gorm:"default:0"` // 304 checks since the page was last fetched
+
+	// Change detection
+	ContentHash      *string      `json:"content_hash" gorm:"type:varchar(64)"` // SHA256 of normalized HTML
+	TextHash         *string      `json:"text_hash" gorm:"type:varchar(64)"`    // SHA256 of visible text
+	ChangeStatus     ChangeStatus `json:"change_status" gorm:"type:varchar(20);default:'new';index"`
+	ContentChangedAt *time.Time   `json:"content_changed_at"` // Last crawl where the visible text changed
+
+	// Raw HTML snapshot this result was parsed from (NULL if snapshots are disabled)
+	SnapshotID *uint `json:"snapshot_id" gorm:"index"`
+
 	// Relationships
 	URL *URL `json:"url,omitempty" gorm:"foreignKey:URLID"`
 }
 
+// SocialPreview is how a page is likely to appear when shared. Each field comes
+// from Open Graph first, then Twitter Cards, then the plain page metadata.
+type SocialPreview struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Image       *string `json:"image"`
+	ImageAlt    *string `json:"image_alt"`
+	URL         *string `json:"url"`
+	SiteName    *string `json:"site_name"`
+	Type        *string `json:"type"`
+	TwitterCard *string `json:"twitter_card"`
+	TwitterSite *string `json:"twitter_site"`
+}
+
 // TableName overrides the table name
 func (CrawlResult) TableName() string {
 	return "crawl_results"
@@ -50,6 +148,60 @@ func (cr *CrawlResult) GetHeadingCounts() map[string]int {
 	}
 }
 
+// HasLoginForm reports whether the page likely has a login form
+func (cr *CrawlResult) HasLoginForm() bool {
+	return cr.LoginConfidence >= LoginFormThreshold
+}
+
+// GetHeadingOutline nests the page's headings into its document outline
+func (cr *CrawlResult) GetHeadingOutline() []HeadingNode {
+	return BuildHeadingOutline(cr.Headings)
+}
+
+// GetSocialPreview builds the page's social preview from its meta tags
+func (cr *CrawlResult) GetSocialPreview() SocialPreview {
+	og := func(key string) *string { return metaValue(cr.OpenGraph, "og:"+key) }
+	twitter := func(key string) *string { return metaValue(cr.TwitterTags, "twitter:"+key) }
+
+	return SocialPreview{
+		Title:       firstMeta(og("title"), twitter("title"), cr.PageTitle),
+		Description: firstMeta(og("description"), twitter("description"), cr.MetaDescription),
+		Image:       firstMeta(og("image"), og("image:secure_url"), og("image:url"), twitter("image"), twitter("image:src")),
+		ImageAlt:    firstMeta(og("image:alt"), twitter("image:alt")),
+		URL:         firstMeta(og("url"), cr.CanonicalURL),
+		SiteName:    og("site_name"),
+		Type:        og("type"),
+		TwitterCard: twitter("card"),
+		TwitterSite: twitter("site"),
+	}
+}
+
+// metaValue returns the first value of a social property, or nil
+func metaValue(properties map[string][]string, key string) *string {
+	if values := properties[key]; len(values) > 0 && values[0] != "" {
+		return &values[0]
+	}
+	return nil
+}
+
+// firstMeta returns the first non-empty value
+func firstMeta(values ...*string) *string {
+	for _, value := range values {
+		if value != nil && *value != "" {
+			return value
+		}
+	}
+	return nil
+}
+
+// GetLeafCertificate returns the server certificate from the stored chain, or nil for plain HTTP
+func (cr *CrawlResult) GetLeafCertificate() *CertificateInfo {
+	if len(cr.CertChain) == 0 {
+		return nil
+	}
+	return &cr.CertChain[0]
+}
+
 // GetTotalLinks returns total internal + external links
 func (cr *CrawlResult) GetTotalLinks() int {
 	return cr.InternalLinksCount + cr.ExternalLinksCount
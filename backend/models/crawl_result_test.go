@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestCrawlResultGetSocialPreview(t *testing.T) {
+	title := "Page title"
+	description := "Meta description"
+	canonical := "https://example.com/canonical"
+
+	// Open Graph wins over Twitter Cards, which win over page metadata
+	result := CrawlResult{
+		PageTitle:       &title,
+		MetaDescription: &description,
+		CanonicalURL:    &canonical,
+		OpenGraph: map[string][]string{
+			"og:title":     {"OG title"},
+			"og:image:url": {"https://example.com/og.png"},
+			"og:type":      {"website"},
+		},
+		TwitterTags: map[string][]string{
+			"twitter:title":       {"Twitter title"},
+			"twitter:description": {"Twitter description"},
+			"twitter:image":       {"https://example.com/twitter.png"},
+			"twitter:card":        {"summary"},
+		},
+	}
+
+	preview := result.GetSocialPreview()
+	got := map[string]*string{
+		"title":        preview.Title,
+		"description":  preview.Description,
+		"image":        preview.Image,
+		"url":          preview.URL,
+		"type":         preview.Type,
+		"twitter_card": preview.TwitterCard,
+	}
+	want := map[string]string{
+		"title":        "OG title",
+		"description":  "Twitter description",
+		"image":        "https://example.com/og.png",
+		"url":          "https://example.com/canonical",
+		"type":         "website",
+		"twitter_card": "summary",
+	}
+	for field, value := range got {
+		if value == nil || *value != want[field] {
+			t.Errorf("%s: expected %q, got %v", field, want[field], value)
+		}
+	}
+	if preview.SiteName != nil || preview.ImageAlt != nil || preview.TwitterSite != nil {
+		t.Errorf("Expected missing properties to be nil, got %+v", preview)
+	}
+
+	// Without social tags the preview falls back to the page itself
+	plain := CrawlResult{PageTitle: &title, MetaDescription: &description}
+	preview = plain.GetSocialPreview()
+	if preview.Title == nil || *preview.Title != title || preview.Description == nil || *preview.Description != description || preview.Image != nil {
+		t.Errorf("Expected the page title and description, got %+v", preview)
+	}
+}
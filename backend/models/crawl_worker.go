@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// CrawlWorker is a running crawl manager. Its heartbeat tells the reaper
+// whether the URLs it holds in "running" are still being worked on.
+type CrawlWorker struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	Hostname        string    `json:"hostname" gorm:"type:varchar(255)"`
+	StartedAt       time.Time `json:"started_at"`
+	LastHeartbeatAt time.Time `json:"last_heartbeat_at" gorm:"index"`
+}
+
+// TableName overrides the table name
+func (CrawlWorker) TableName() string {
+	return "crawl_workers"
+}
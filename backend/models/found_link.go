@@ -1,9 +1,43 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
+// LinkRegions lists the page regions a link can be found in, from landmark
+// elements or their ARIA roles. Links outside any landmark have no region.
+var LinkRegions = []string{
+	"header", // <header> or role="banner"
+	"nav",    // <nav> or role="navigation"
+	"main",   // <main> or role="main"
+	"aside",  // <aside> or role="complementary"
+	"footer", // <footer> or role="contentinfo"
+}
+
+// LinkRelValues lists the rel keywords recorded on found links
+var LinkRelValues = []string{"nofollow", "sponsored", "ugc", "noopener"}
+
+// IsValidLinkRegion checks if the region is one of LinkRegions
+func IsValidLinkRegion(region string) bool {
+	for _, r := range LinkRegions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidLinkRel checks if the keyword is one of LinkRelValues
+func IsValidLinkRel(rel string) bool {
+	for _, r := range LinkRelValues {
+		if r == rel {
+			return true
+		}
+	}
+	return false
+}
+
 // FoundLink represents a link discovered during crawling
 type FoundLink struct {
 	ID           uint    `json:"id" gorm:"primaryKey"`
@@ -11,9 +45,16 @@ type FoundLink struct {
 	LinkURL      string  `json:"link_url" gorm:"type:varchar(2048);not null"`
 	LinkText     *string `json:"link_text" gorm:"type:varchar(500)"`
 	IsInternal   bool    `json:"is_internal" gorm:"not null;index"`
+	IsImage      bool    `json:"is_image" gorm:"not null;default:false;index"` // Anchor wraps an <img>, <picture> or <svg>
 	IsAccessible *bool   `json:"is_accessible" gorm:"index"` // NULL = not checked yet
 	StatusCode   *int    `json:"status_code" gorm:"index"`
 	ErrorMessage *string `json:"error_message" gorm:"type:text"`
+	Occurrences  int      `json:"occurrences" gorm:"not null;default:1"`        // Times the page links to LinkURL
+	AnchorTexts  []string `json:"anchor_texts" gorm:"type:text;serializer:json"` // Distinct anchor texts seen for LinkURL
+	Region       *string  `json:"region" gorm:"type:varchar(20);index"`          // Page region (LinkRegions); NULL = outside landmarks
+	Rel          *string  `json:"rel" gorm:"type:varchar(100)"`                  // Space-separated LinkRelValues, in that order
+	Target       *string  `json:"target" gorm:"type:varchar(50)"`
+	Title        *string  `json:"title" gorm:"type:varchar(500)"`
 	CreatedAt    time.Time `json:"created_at"`
 	
 	// Relationships
@@ -25,6 +66,14 @@ func (FoundLink) TableName() string {
 	return "found_links"
 }
 
+// RelValues returns the link's rel keywords
+func (fl *FoundLink) RelValues() []string {
+	if fl.Rel == nil || *fl.Rel == "" {
+		return []string{}
+	}
+	return strings.Fields(*fl.Rel)
+}
+
 // IsBroken returns true if the link is inaccessible (4xx or 5xx status codes)
 func (fl *FoundLink) IsBroken() bool {
 	if fl.StatusCode == nil {
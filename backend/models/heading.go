@@ -0,0 +1,49 @@
+package models
+
+// HeadingIssue is a problem with a heading's place in the document outline
+type HeadingIssue string
+
+const (
+	HeadingSkippedLevel HeadingIssue = "skipped_level" // Deeper than the previous visible heading by more than one level, e.g. h2 → h4
+	HeadingMultipleH1   HeadingIssue = "multiple_h1"   // An h1 after the page's first visible h1
+	HeadingEmpty        HeadingIssue = "empty"         // No text, image alt or aria-label
+	HeadingHidden       HeadingIssue = "hidden"        // Inside an element with the hidden attribute or display:none
+)
+
+// HeadingIssues lists the known heading issues
+var HeadingIssues = []HeadingIssue{HeadingSkippedLevel, HeadingMultipleH1, HeadingEmpty, HeadingHidden}
+
+// Heading is an h1-h6 element of a crawled page
+type Heading struct {
+	Level  int            `json:"level"`  // 1-6
+	Text   string         `json:"text"`   // Visible text, whitespace-collapsed
+	Hidden bool           `json:"hidden"` // Not rendered, so not part of the visible outline
+	Issues []HeadingIssue `json:"issues"`
+}
+
+// HeadingNode is a heading with the headings nested under it in the outline
+type HeadingNode struct {
+	Heading
+	Children []HeadingNode `json:"children"`
+}
+
+// BuildHeadingOutline nests headings in document order: each heading is a child
+// of the closest earlier heading with a lower level. A skipped level does not
+// add an empty node, so an h4 after an h2 is a direct child of the h2.
+func BuildHeadingOutline(headings []Heading) []HeadingNode {
+	outline, _ := buildOutlineLevel(headings, 0, 0)
+	return outline
+}
+
+// buildOutlineLevel collects the headings from start that are deeper than
+// parentLevel as siblings, and returns the index of the first heading that is not
+func buildOutlineLevel(headings []Heading, start, parentLevel int) ([]HeadingNode, int) {
+	nodes := make([]HeadingNode, 0)
+	i := start
+	for i < len(headings) && headings[i].Level > parentLevel {
+		node := HeadingNode{Heading: headings[i]}
+		node.Children, i = buildOutlineLevel(headings, i+1, headings[i].Level)
+		nodes = append(nodes, node)
+	}
+	return nodes, i
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestBuildHeadingOutline(t *testing.T) {
+	headings := []Heading{
+		{Level: 2, Text: "Intro"},
+		{Level: 1, Text: "Title"},
+		{Level: 2, Text: "Setup"},
+		{Level: 4, Text: "Skipped"},
+		{Level: 3, Text: "Install"},
+		{Level: 2, Text: "Usage"},
+		{Level: 1, Text: "Appendix"},
+	}
+
+	outline := BuildHeadingOutline(headings)
+
+	// Render the tree as "text(children...)" for a compact comparison
+	var render func(nodes []HeadingNode) string
+	render = func(nodes []HeadingNode) string {
+		out := ""
+		for i, node := range nodes {
+			if i > 0 {
+				out += " "
+			}
+			out += node.Text
+			if len(node.Children) > 0 {
+				out += "(" + render(node.Children) + ")"
+			}
+		}
+		return out
+	}
+
+	want := "Intro Title(Setup(Skipped Install) Usage) Appendix"
+	if got := render(outline); got != want {
+		t.Errorf("Expected outline %q, got %q", want, got)
+	}
+
+	if outline := BuildHeadingOutline(nil); outline == nil || len(outline) != 0 {
+		t.Errorf("Expected an empty outline, got %v", outline)
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginSignal is a piece of evidence that a form or page is a login
+type LoginSignal string
+
+const (
+	LoginPasswordField   LoginSignal = "password_field"   // A password input
+	LoginIdentifierField LoginSignal = "identifier_field" // A short form asking for an email or username, e.g. the first step of a two-step login
+	LoginText            LoginSignal = "login_text"       // "Log in" or "Sign in" on a submit button or in the form's id, class, name or aria-label
+	LoginAction          LoginSignal = "login_action"     // The action URL looks like a login endpoint, e.g. /login or /session
+	LoginOAuthButton     LoginSignal = "oauth_button"     // A "Sign in with Google" or "Continue with GitHub" button or link on the page
+	LoginRegistration    LoginSignal = "registration"     // A new-password field, a password confirmation or sign-up text, which point to a registration form
+)
+
+// LoginSignals lists the known login signals
+var LoginSignals = []LoginSignal{
+	LoginPasswordField, LoginIdentifierField, LoginText, LoginAction, LoginOAuthButton, LoginRegistration,
+}
+
+// LoginFormThreshold is the login confidence from which a page counts as having a login form
+const LoginFormThreshold = 50
+
+// FormField is a named control of a form
+type FormField struct {
+	Name         string `json:"name"`                   // name attribute, or id if there is no name
+	Type         string `json:"type"`                   // Lower-cased input type, or "select" / "textarea"
+	Autocomplete string `json:"autocomplete,omitempty"` // Lower-cased autocomplete hint, e.g. "current-password"
+}
+
+// PageForm is a form found on a crawled page. Controls outside any <form>
+// are grouped into one standalone form with no action or method.
+type PageForm struct {
+	ID              uint          `json:"id" gorm:"primaryKey"`
+	URLID           uint          `json:"url_id" gorm:"not null;index"`
+	Position        int           `json:"position" gorm:"not null"`                 // Document order, from 0
+	Action          *string       `json:"action" gorm:"type:varchar(2048)"`         // Resolved action; the page URL when the attribute is missing
+	Method          *string       `json:"method" gorm:"type:varchar(10)"`           // "get" or "post"
+	Standalone      bool          `json:"standalone" gorm:"not null;default:false"` // Controls outside any <form>
+	Fields          []FormField   `json:"fields" gorm:"type:text;serializer:json"`
+	HasCSRFToken    bool          `json:"has_csrf_token" gorm:"not null;default:false"`        // Hidden field named like a CSRF token
+	InsecureAction  bool          `json:"insecure_action" gorm:"not null;default:false;index"` // Submits over plain HTTP from an HTTPS page
+	LoginConfidence int           `json:"login_confidence" gorm:"not null;default:0;index"`    // 0-100
+	LoginSignals    []LoginSignal `json:"login_signals" gorm:"type:text;serializer:json"`
+	CreatedAt       time.Time     `json:"created_at"`
+
+	// Relationships
+	URL *URL `json:"url,omitempty" gorm:"foreignKey:URLID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName overrides the table name
+func (PageForm) TableName() string {
+	return "page_forms"
+}
+
+// IsLogin reports whether the form is likely a login form
+func (pf *PageForm) IsLogin() bool {
+	return pf.LoginConfidence >= LoginFormThreshold
+}
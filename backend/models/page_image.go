@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// ImageAltStatus grades the alt text of an image
+type ImageAltStatus string
+
+const (
+	AltOK        ImageAltStatus = "ok"
+	AltMissing   ImageAltStatus = "missing"   // No alt attribute
+	AltEmpty     ImageAltStatus = "empty"     // alt="", which marks the image as decorative
+	AltFilename  ImageAltStatus = "filename"  // Looks like a file name, e.g. "IMG_0042.jpg"
+	AltDuplicate ImageAltStatus = "duplicate" // Shared with a different image on the page
+)
+
+// ImageAltStatuses lists the known alt text grades
+var ImageAltStatuses = []ImageAltStatus{AltOK, AltMissing, AltEmpty, AltFilename, AltDuplicate}
+
+// IsValidImageAltStatus checks if the status is one of ImageAltStatuses
+func IsValidImageAltStatus(status string) bool {
+	for _, s := range ImageAltStatuses {
+		if string(s) == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageCandidate is one entry of a srcset attribute
+type ImageCandidate struct {
+	URL        string `json:"url"`                  // Resolved candidate URL
+	Descriptor string `json:"descriptor,omitempty"` // Width or density descriptor, e.g. "640w" or "2x"
+}
+
+// PageImage is an <img> found on a crawled page. An image inside a <picture>
+// also carries the srcset candidates of the picture's <source> elements.
+type PageImage struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	URLID     uint             `json:"url_id" gorm:"not null;index"`
+	Position  int              `json:"position" gorm:"not null"`                 // Document order, from 0
+	Src       *string          `json:"src" gorm:"type:varchar(2048)"`            // Resolved src; data URIs keep only their media type
+	Srcset    []ImageCandidate `json:"srcset" gorm:"type:text;serializer:json"`  // img srcset, then <source> srcsets
+	InPicture bool             `json:"in_picture" gorm:"not null;default:false"` // Child of a <picture>
+	Alt       *string          `json:"alt" gorm:"type:varchar(500)"`             // NULL = no alt attribute
+	AltStatus ImageAltStatus   `json:"alt_status" gorm:"type:varchar(20);not null;index"`
+	Width     *int             `json:"width"`                              // width attribute in pixels, NULL if missing or not a number
+	Height    *int             `json:"height"`                             // height attribute in pixels, NULL if missing or not a number
+	Lazy      bool             `json:"lazy" gorm:"not null;default:false"` // loading="lazy"
+	CreatedAt time.Time        `json:"created_at"`
+
+	// Relationships
+	URL *URL `json:"url,omitempty" gorm:"foreignKey:URLID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName overrides the table name
+func (PageImage) TableName() string {
+	return "page_images"
+}
+
+// HasDimensions reports whether both width and height are set
+func (pi *PageImage) HasDimensions() bool {
+	return pi.Width != nil && pi.Height != nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// PageSnapshot records the raw HTML fetched by one crawl. The body itself lives
+// in a blob store under StorageKey; identical bodies share a single blob.
+type PageSnapshot struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	URLID          uint      `json:"url_id" gorm:"not null;index"`
+	ContentHash    string    `json:"content_hash" gorm:"type:varchar(64);not null;index"` // SHA256 of the raw body
+	StorageKey     string    `json:"-" gorm:"type:varchar(255);not null;index"`
+	Size           int64     `json:"size"`            // Uncompressed size in bytes
+	CompressedSize int64     `json:"compressed_size"` // Stored size in bytes
+	ContentType    string    `json:"content_type" gorm:"type:varchar(255)"`
+	StatusCode     int       `json:"status_code"`
+	FinalURL       string    `json:"final_url" gorm:"type:varchar(2048)"`
+	FetchedAt      time.Time `json:"fetched_at" gorm:"index"`
+
+	// Relationships
+	URL *URL `json:"url,omitempty" gorm:"foreignKey:URLID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName overrides the table name
+func (PageSnapshot) TableName() string {
+	return "page_snapshots"
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// AllHosts is the QueuePause host that pauses the whole crawl queue
+const AllHosts = "*"
+
+// QueuePause records that the crawl queue, or one host, is paused, so the
+// pause survives a restart
+type QueuePause struct {
+	Host     string    `json:"host" gorm:"primaryKey;type:varchar(255)"` // Normalized host name, or AllHosts
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// TableName overrides the table name
+func (QueuePause) TableName() string {
+	return "queue_pauses"
+}
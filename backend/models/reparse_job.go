@@ -0,0 +1,56 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// ReparseJobStatus represents the state of a snapshot re-parse job
+type ReparseJobStatus string
+
+const (
+	ReparsePending   ReparseJobStatus = "pending"
+	ReparseRunning   ReparseJobStatus = "running"
+	ReparseCompleted ReparseJobStatus = "completed"
+	ReparseFailed    ReparseJobStatus = "failed"
+)
+
+// ReparseJob tracks an admin job that re-runs the HTML parser over stored
+// snapshots. Progress is checkpointed per batch in LastURLID so an
+// interrupted job can resume where it stopped.
+type ReparseJob struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	Status     ReparseJobStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	BatchSize  int              `json:"batch_size" gorm:"not null"`
+	Total      int              `json:"total"`     // URLs with a snapshot when the job (re)started
+	Processed  int              `json:"processed"` // Succeeded + Failed + Skipped
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	Skipped    int              `json:"skipped"`     // Busy or changed by a concurrent crawl
+	LastURLID  uint             `json:"last_url_id"` // Checkpoint: URLs up to this ID are done
+	LastError  *string          `json:"last_error" gorm:"type:text"`
+	StartedAt  *time.Time       `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ReparseJob) TableName() string {
+	return "reparse_jobs"
+}
+
+// Progress returns the completed fraction of the job as a percentage, rounded to two decimals
+func (j *ReparseJob) Progress() float64 {
+	if j.Total == 0 {
+		if j.Status == ReparseCompleted {
+			return 100
+		}
+		return 0
+	}
+	progress := float64(j.Processed) / float64(j.Total) * 100
+	if progress > 100 {
+		progress = 100
+	}
+	return math.Round(progress*100) / 100
+}
@@ -0,0 +1,22 @@
+package models
+
+// SecurityHeaderStatus describes how a security header was found on a response
+type SecurityHeaderStatus string
+
+const (
+	SecurityHeaderPresent       SecurityHeaderStatus = "present"
+	SecurityHeaderWeak          SecurityHeaderStatus = "weak"
+	SecurityHeaderMisconfigured SecurityHeaderStatus = "misconfigured"
+	SecurityHeaderMissing       SecurityHeaderStatus = "missing"
+)
+
+// SecurityHeaderFinding is the audit result for a single security header
+type SecurityHeaderFinding struct {
+	Header    string               `json:"header"`
+	Status    SecurityHeaderStatus `json:"status"`
+	Severity  string               `json:"severity"` // "high", "medium", "low" or "info"
+	Value     *string              `json:"value"`
+	Message   string               `json:"message"`
+	Points    int                  `json:"points"`     // Points awarded towards the score
+	MaxPoints int                  `json:"max_points"` // Points available for this header
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// StructuredDataFormat is the syntax a structured data item was found in
+type StructuredDataFormat string
+
+const (
+	FormatJSONLD    StructuredDataFormat = "json-ld"   // <script type="application/ld+json">
+	FormatMicrodata StructuredDataFormat = "microdata" // itemscope / itemtype / itemprop
+	FormatRDFa      StructuredDataFormat = "rdfa"      // typeof / property
+)
+
+// StructuredDataFormats lists the known structured data syntaxes
+var StructuredDataFormats = []StructuredDataFormat{FormatJSONLD, FormatMicrodata, FormatRDFa}
+
+// IsValidStructuredDataFormat checks if the format is one of StructuredDataFormats
+func IsValidStructuredDataFormat(format string) bool {
+	for _, f := range StructuredDataFormats {
+		if string(f) == format {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaTypePattern matches the schema.org type names URLs can be filtered on
+var schemaTypePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// IsValidSchemaType checks that a schema.org type name is safe to filter on, e.g. "Product"
+func IsValidSchemaType(schemaType string) bool {
+	return schemaTypePattern.MatchString(schemaType)
+}
+
+// StructuredData is one JSON-LD block, or one top-level microdata or RDFa item,
+// found on a crawled page
+type StructuredData struct {
+	ID        uint                 `json:"id" gorm:"primaryKey"`
+	URLID     uint                 `json:"url_id" gorm:"not null;index"`
+	Position  int                  `json:"position" gorm:"not null"` // Document order, from 0
+	Format    StructuredDataFormat `json:"format" gorm:"type:varchar(20);not null;index"`
+	Types     []string             `json:"types" gorm:"type:text;serializer:json"` // schema.org types, including nested items
+	Payload   string               `json:"payload" gorm:"type:mediumtext"`         // Raw JSON-LD, or the item as JSON
+	Valid     bool                 `json:"valid" gorm:"not null;default:true"`
+	Truncated bool                 `json:"truncated" gorm:"not null;default:false"` // Payload was cut to fit the size limits
+	Error     *string              `json:"error" gorm:"type:text"`                  // Why a JSON-LD block could not be parsed
+	CreatedAt time.Time            `json:"created_at"`
+
+	// Relationships
+	URL *URL `json:"url,omitempty" gorm:"foreignKey:URLID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName overrides the table name
+func (StructuredData) TableName() string {
+	return "structured_data"
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 // URLStatus represents the status of a URL crawling process
@@ -15,14 +16,28 @@ const (
 	StatusError     URLStatus = "error"
 )
 
+// GormDBDataType keeps the ENUM column on MySQL and falls back to a plain
+// string column on dialects without ENUM support (SQLite in tests)
+func (URLStatus) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "mysql" {
+		return "enum('queued','running','completed','error')"
+	}
+	return "varchar(20)"
+}
+
 // URL represents a target URL for crawling
 type URL struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	URL          string    `json:"url" gorm:"type:varchar(2048);not null;uniqueIndex:unique_url,length:255"`
-	Status       URLStatus `json:"status" gorm:"type:enum('queued','running','completed','error');default:'queued';index"`
-	ErrorMessage *string   `json:"error_message,omitempty" gorm:"type:text"`
-	CreatedAt    time.Time `json:"created_at" gorm:"index"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	URL            string     `json:"url" gorm:"type:varchar(2048);not null;uniqueIndex:unique_url,length:255"`
+	Status         URLStatus  `json:"status" gorm:"default:'queued';index"`
+	ErrorMessage   *string    `json:"error_message,omitempty" gorm:"type:text"`
+	ErrorType      *string    `json:"error_type,omitempty" gorm:"type:varchar(50);index"`
+	RetryCount     int        `json:"retry_count" gorm:"default:0"`                       // Retries made by the current or last crawl
+	NextRetryAt    *time.Time `json:"next_retry_at,omitempty" gorm:"index"`               // When a transient failure will be retried
+	WorkerID       *string    `json:"worker_id,omitempty" gorm:"type:varchar(100);index"` // Worker crawling the URL while running
+	CrawlStartedAt *time.Time `json:"crawl_started_at,omitempty"`                         // Start of the running crawl's lease
+	CreatedAt      time.Time  `json:"created_at" gorm:"index"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 	
 	// Relationships
 	CrawlResult *CrawlResult `json:"crawl_result,omitempty" gorm:"foreignKey:URLID"`
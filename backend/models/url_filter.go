@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// urlFilterStatuses lists the statuses URLs can be filtered on
+var urlFilterStatuses = []string{
+	string(StatusQueued),
+	string(StatusRunning),
+	string(StatusCompleted),
+	string(StatusError),
+}
+
+// URLFilter selects URLs by the criteria supported by the URL list. It is
+// shared by GET /api/urls and batch crawls, and is stored with each batch.
+type URLFilter struct {
+	Search    string `json:"search,omitempty"`     // Substring of the URL
+	Status    string `json:"status,omitempty"`     // One of the URL statuses
+	Changed   *bool  `json:"changed,omitempty"`    // Text changed (or not) since the previous crawl
+	ErrorType string `json:"error_type,omitempty"` // Classification of the last failure
+	Schema    string `json:"schema,omitempty"`     // schema.org type in the last crawl's structured data, e.g. "Product"
+}
+
+// IsEmpty reports whether the filter matches every URL
+func (f URLFilter) IsEmpty() bool {
+	return f.Search == "" && f.Status == "" && f.Changed == nil && f.ErrorType == "" && f.Schema == ""
+}
+
+// Validate checks the status and error type against the known values, and that
+// the schema type is a plain type name
+func (f URLFilter) Validate() error {
+	if f.Status != "" {
+		isValidStatus := false
+		for _, status := range urlFilterStatuses {
+			if f.Status == status {
+				isValidStatus = true
+				break
+			}
+		}
+		if !isValidStatus {
+			return fmt.Errorf("status must be one of: %s", strings.Join(urlFilterStatuses, ", "))
+		}
+	}
+
+	if f.ErrorType != "" && !IsValidCrawlErrorType(f.ErrorType) {
+		return fmt.Errorf("error_type must be one of: %s", strings.Join(CrawlErrorTypes, ", "))
+	}
+
+	if f.Schema != "" && !IsValidSchemaType(f.Schema) {
+		return fmt.Errorf("schema must be a schema.org type name such as Product")
+	}
+
+	return nil
+}
+
+// Apply adds the filter's conditions to a query on the urls table
+func (f URLFilter) Apply(query *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+
+	if f.Search != "" {
+		searchPattern := "%" + f.Search + "%"
+		query = query.Where("url LIKE ?", searchPattern)
+	}
+
+	if f.ErrorType != "" {
+		query = query.Where("error_type = ?", f.ErrorType)
+	}
+
+	if f.Changed != nil {
+		// "changed" means the visible text moved; first crawls and markup-only changes don't count
+		changeStatuses := []ChangeStatus{ChangeUnchanged, ChangeMarkupOnly}
+		if *f.Changed {
+			changeStatuses = []ChangeStatus{ChangeChanged}
+		}
+		query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Model(&CrawlResult{}).
+			Select("url_id").
+			Where("change_status IN ?", changeStatuses))
+	}
+
+	if f.Schema != "" {
+		// Types are stored as a JSON array, so the quotes make this an exact match
+		query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Model(&StructuredData{}).
+			Select("url_id").
+			Where("types LIKE ?", `%"`+f.Schema+`"%`))
+	}
+
+	return query
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"web-crawler/env"
+)
+
+// ManagerConfigFromEnv builds the crawl manager configuration from environment variables:
+//
+//	SNAPSHOTS_ENABLED      store raw HTML snapshots (default: true)
+//	SNAPSHOT_DIR           snapshot blob directory (default: ./data/snapshots)
+//	SNAPSHOT_MAX_PER_URL   snapshots kept per URL, 0 = unlimited (default: 10)
+//	SNAPSHOT_MAX_AGE_DAYS  snapshot age limit in days, 0 = unlimited (default: 90)
+//	CRAWLER_FIXTURES_DIR   serve pages from recorded fixtures instead of the network (default: unset)
+//	MAX_LINKS_PER_PAGE     found links saved per page, 0 = unlimited (default: 200)
+func ManagerConfigFromEnv() (*ManagerConfig, error) {
+	config := DefaultManagerConfig()
+
+	config.MaxLinks = env.Int("MAX_LINKS_PER_PAGE", config.MaxLinks)
+
+	if dir := os.Getenv("CRAWLER_FIXTURES_DIR"); dir != "" {
+		fetcher, err := NewFixtureFetcher(dir, nil)
+		if err != nil {
+			return nil, err
+		}
+		config.Fetcher = fetcher
+		log.Printf("WARNING: Crawling from fixtures in %s, no network requests will be made", dir)
+	}
+
+	if env.Bool("SNAPSHOTS_ENABLED", true) {
+		dir := env.String("SNAPSHOT_DIR", "./data/snapshots")
+		blobs, err := NewFileBlobStore(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		retention := DefaultRetentionPolicy()
+		retention.MaxSnapshotsPerURL = env.Int("SNAPSHOT_MAX_PER_URL", retention.MaxSnapshotsPerURL)
+		retention.MaxAge = time.Duration(env.Int("SNAPSHOT_MAX_AGE_DAYS", int(retention.MaxAge.Hours()/24))) * 24 * time.Hour
+
+		config.SnapshotStore = NewSnapshotStore(blobs, retention)
+		log.Printf("Storing raw HTML snapshots in %s (max %d per URL, max age %v)",
+			dir, retention.MaxSnapshotsPerURL, retention.MaxAge)
+	}
+
+	return config, nil
+}
+
+// ReaperConfigFromEnv builds the stale crawl reaper configuration from environment variables:
+//
+//	REAPER_INTERVAL_SECONDS      how often to look for stale crawls (default: 60)
+//	CRAWL_LEASE_TIMEOUT_SECONDS  running time after which a crawl is stale (default: 600)
+//	WORKER_TIMEOUT_SECONDS       heartbeat age after which a worker is dead (default: 60)
+//	REAPER_REQUEUE               requeue stale crawls instead of failing them (default: true)
+//	REAPER_MAX_REQUEUES          recoveries before a URL is failed instead (default: 3)
+func ReaperConfigFromEnv() *ReaperConfig {
+	config := DefaultReaperConfig()
+
+	config.Interval = time.Duration(env.Int("REAPER_INTERVAL_SECONDS", int(config.Interval.Seconds()))) * time.Second
+	config.LeaseTimeout = time.Duration(env.Int("CRAWL_LEASE_TIMEOUT_SECONDS", int(config.LeaseTimeout.Seconds()))) * time.Second
+	config.WorkerTimeout = time.Duration(env.Int("WORKER_TIMEOUT_SECONDS", int(config.WorkerTimeout.Seconds()))) * time.Second
+	config.Requeue = env.Bool("REAPER_REQUEUE", config.Requeue)
+	config.MaxRequeues = env.Int("REAPER_MAX_REQUEUES", config.MaxRequeues)
+
+	if config.Interval <= 0 {
+		log.Printf("Ignoring REAPER_INTERVAL_SECONDS <= 0, using %v", DefaultReaperConfig().Interval)
+		config.Interval = DefaultReaperConfig().Interval
+	}
+	if heartbeat := DefaultManagerConfig().HeartbeatInterval; config.WorkerTimeout <= heartbeat {
+		log.Printf("WARNING: WORKER_TIMEOUT_SECONDS should be well above the %v worker heartbeat", heartbeat)
+	}
+
+	return config
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"web-crawler/models"
+)
+
+// ContentHashes fingerprints a page so consecutive crawls can be compared
+type ContentHashes struct {
+	BodyHash string // SHA256 of the normalized HTML body
+	TextHash string // SHA256 of the extracted visible text
+}
+
+// HashContent computes the body and text hashes of a crawled page
+func HashContent(htmlContent, textContent string) ContentHashes {
+	return ContentHashes{
+		BodyHash: sha256Hex(normalizeBody(htmlContent)),
+		TextHash: sha256Hex(textContent),
+	}
+}
+
+// normalizeBody removes differences that do not change the document:
+// line endings and runs of whitespace
+func normalizeBody(htmlContent string) string {
+	return strings.Join(strings.Fields(htmlContent), " ")
+}
+
+// sha256Hex returns the hex-encoded SHA256 hash of a string
+func sha256Hex(s string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))
+}
+
+// DetectChange compares a crawl's hashes against the previous crawl.
+// Only text changes count as "changed"; markup-only changes (e.g. rotating
+// nonces or tracking attributes) are reported separately so monitoring can ignore them.
+func DetectChange(previous *models.CrawlResult, current ContentHashes) models.ChangeStatus {
+	if previous == nil || previous.TextHash == nil || previous.ContentHash == nil {
+		return models.ChangeNew
+	}
+
+	if *previous.TextHash != current.TextHash {
+		return models.ChangeChanged
+	}
+
+	if *previous.ContentHash != current.BodyHash {
+		return models.ChangeMarkupOnly
+	}
+
+	return models.ChangeUnchanged
+}
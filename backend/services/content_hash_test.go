@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"web-crawler/models"
+)
+
+func TestHashContent_IgnoresWhitespace(t *testing.T) {
+	a := HashContent("<html>\n  <body>Hello</body>\n</html>", "Hello")
+	b := HashContent("<html>\r\n<body>Hello</body>\r\n</html>", "Hello")
+
+	if a.BodyHash != b.BodyHash {
+		t.Error("Whitespace-only differences should produce the same body hash")
+	}
+
+	if len(a.BodyHash) != 64 || len(a.TextHash) != 64 {
+		t.Errorf("Expected SHA256 hex hashes, got %q and %q", a.BodyHash, a.TextHash)
+	}
+}
+
+func TestDetectChange(t *testing.T) {
+	original := HashContent("<p>Hello</p>", "Hello")
+	previous := &models.CrawlResult{
+		ContentHash: &original.BodyHash,
+		TextHash:    &original.TextHash,
+	}
+
+	testCases := []struct {
+		name     string
+		previous *models.CrawlResult
+		current  ContentHashes
+		expected models.ChangeStatus
+	}{
+		{"first crawl", nil, original, models.ChangeNew},
+		{"previous crawl without hashes", &models.CrawlResult{}, original, models.ChangeNew},
+		{"identical content", previous, original, models.ChangeUnchanged},
+		{"markup only", previous, HashContent(`<p class="x">Hello</p>`, "Hello"), models.ChangeMarkupOnly},
+		{"text changed", previous, HashContent("<p>Goodbye</p>", "Goodbye"), models.ChangeChanged},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status := DetectChange(tc.previous, tc.current); status != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+)
+
+const (
+	maxAttemptLogLines      = 100 // Further lines are counted but not stored
+	maxAttemptLogLineLength = 500
+	maxAttemptsPerURL       = 50 // Older attempts are pruned when a new one finishes
+)
+
+// attemptRecorder keeps the crawl_attempts row for one attempt up to date.
+// Messages passed to logf go to both the process log and the attempt's log lines.
+// A nil recorder only writes to the process log.
+type attemptRecorder struct {
+	attempt *models.CrawlAttempt
+	dropped int // Log lines beyond maxAttemptLogLines
+}
+
+// startAttempt records the start of a crawl attempt. Failing to store the row
+// does not stop the crawl; finish tries to save it again.
+func startAttempt(job *CrawlJob, workerID string) *attemptRecorder {
+	attempt := &models.CrawlAttempt{
+		URLID:     job.URLID,
+		Attempt:   job.Attempt,
+		WorkerID:  workerID,
+		Outcome:   models.AttemptRunning,
+		StartedAt: time.Now(),
+		LogLines:  []string{},
+	}
+
+	if err := database.DB.Create(attempt).Error; err != nil {
+		log.Printf("Failed to record crawl attempt for URL ID=%d: %v", job.URLID, err)
+	}
+
+	return &attemptRecorder{attempt: attempt}
+}
+
+// logf logs a message and appends it, timestamped, to the attempt's log lines
+func (r *attemptRecorder) logf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Print(message)
+
+	if r == nil {
+		return
+	}
+	if len(r.attempt.LogLines) >= maxAttemptLogLines {
+		r.dropped++
+		return
+	}
+
+	if len(message) > maxAttemptLogLineLength {
+		message = truncateString(message, maxAttemptLogLineLength) + "..."
+	}
+	line := time.Now().UTC().Format("15:04:05.000") + " " + message
+	r.attempt.LogLines = append(r.attempt.LogLines, line)
+}
+
+// recordResponse stores what the fetch returned
+func (r *attemptRecorder) recordResponse(response *CrawlResponse) {
+	if r == nil {
+		return
+	}
+
+	r.attempt.FinalURL = optionalString(response.URL)
+	statusCode := response.StatusCode
+	r.attempt.StatusCode = &statusCode
+	bytes := response.ResponseSize
+	r.attempt.Bytes = &bytes
+}
+
+// finished reports whether the attempt has already been recorded as ended
+func (r *attemptRecorder) finished() bool {
+	return r != nil && r.attempt.Outcome != models.AttemptRunning
+}
+
+// finish records how the attempt ended. errorType and err are empty for successful attempts.
+func (r *attemptRecorder) finish(outcome models.CrawlAttemptOutcome, errorType string, err error) {
+	if r == nil {
+		return
+	}
+
+	now := time.Now()
+	durationMs := int(now.Sub(r.attempt.StartedAt).Milliseconds())
+	r.attempt.Outcome = outcome
+	r.attempt.FinishedAt = &now
+	r.attempt.DurationMs = &durationMs
+
+	if err != nil {
+		message := err.Error()
+		r.attempt.ErrorMessage = &message
+		r.attempt.ErrorType = optionalString(errorType)
+
+		// HTTP errors carry the status code the fetch never returned as a response
+		var crawlErr *CrawlError
+		if r.attempt.StatusCode == nil && errors.As(err, &crawlErr) && crawlErr.StatusCode != 0 {
+			statusCode := crawlErr.StatusCode
+			r.attempt.StatusCode = &statusCode
+		}
+	}
+
+	if r.dropped > 0 {
+		r.attempt.LogLines = append(r.attempt.LogLines, fmt.Sprintf("(%d more log lines omitted)", r.dropped))
+	}
+
+	if saveErr := database.DB.Save(r.attempt).Error; saveErr != nil {
+		log.Printf("Failed to save crawl attempt for URL ID=%d: %v", r.attempt.URLID, saveErr)
+		return
+	}
+
+	pruneAttempts(r.attempt.URLID)
+}
+
+// pruneAttempts deletes all but the newest maxAttemptsPerURL attempts of a URL
+func pruneAttempts(urlID uint) {
+	var cutoff []uint
+	err := database.DB.Model(&models.CrawlAttempt{}).
+		Where("url_id = ?", urlID).
+		Order("id DESC").
+		Offset(maxAttemptsPerURL).
+		Limit(1).
+		Pluck("id", &cutoff).Error
+	if err != nil {
+		log.Printf("Failed to find old crawl attempts for URL ID=%d: %v", urlID, err)
+		return
+	}
+	if len(cutoff) == 0 {
+		return
+	}
+
+	if err := database.DB.Where("url_id = ? AND id <= ?", urlID, cutoff[0]).Delete(&models.CrawlAttempt{}).Error; err != nil {
+		log.Printf("Failed to prune crawl attempts for URL ID=%d: %v", urlID, err)
+	}
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+
+	"gorm.io/gorm"
+)
+
+// MaxBatchURLs is the largest number of URLs a single batch crawl may match
+const MaxBatchURLs = 10000
+
+const (
+	batchChunkSize         = 100         // Items resolved or queued per checkpoint
+	defaultBatchQueueDelay = time.Second // Wait before retrying when the crawl queue is full
+)
+
+var (
+	// ErrBatchSelection is returned when a batch has neither or both of URL IDs and a filter
+	ErrBatchSelection = errors.New("either url_ids or a non-empty filter is required")
+	// ErrBatchURLsNotFound is returned when some requested URL IDs do not exist
+	ErrBatchURLsNotFound = errors.New("one or more URL IDs do not exist")
+	// ErrBatchEmpty is returned when no URL matches the batch
+	ErrBatchEmpty = errors.New("no URLs match the batch")
+	// ErrBatchTooLarge is returned when the batch matches more than MaxBatchURLs
+	ErrBatchTooLarge = errors.New("batch matches too many URLs")
+)
+
+// BatchRequest selects the URLs of a batch crawl, either by ID or by filter
+type BatchRequest struct {
+	URLIDs   []uint
+	Filter   *models.URLFilter
+	Priority models.CrawlPriority // Defaults to normal
+}
+
+// BatchCrawler queues large sets of URLs in the background. Unlike a bulk
+// crawl, a batch is not limited by the queue size: it feeds the queue as the
+// worker drains it and records the outcome of every URL.
+type BatchCrawler struct {
+	manager    *CrawlManager
+	queueDelay time.Duration
+
+	mu     sync.Mutex
+	active map[uint]bool // Batches being run by this process
+}
+
+// NewBatchCrawler creates a batch crawler that queues URLs on the given manager
+func NewBatchCrawler(manager *CrawlManager) *BatchCrawler {
+	return &BatchCrawler{
+		manager:    manager,
+		queueDelay: defaultBatchQueueDelay,
+		active:     make(map[uint]bool),
+	}
+}
+
+// StartBatch checks the selection, creates the batch and queues its URLs in the background
+func (b *BatchCrawler) StartBatch(req BatchRequest) (*models.CrawlBatch, error) {
+	byFilter := req.Filter != nil && !req.Filter.IsEmpty()
+	if (len(req.URLIDs) > 0) == byFilter {
+		return nil, ErrBatchSelection
+	}
+	if byFilter {
+		if err := req.Filter.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBatchSelection, err)
+		}
+	}
+	if req.Priority == "" {
+		req.Priority = models.PriorityNormal
+	}
+
+	batch := &models.CrawlBatch{
+		Status:   models.BatchPending,
+		Priority: req.Priority,
+	}
+	if byFilter {
+		batch.Filter = req.Filter
+	} else {
+		batch.URLIDs = uniqueIDs(req.URLIDs)
+	}
+
+	var matched int64
+	if err := b.selectURLs(database.DB, batch).Count(&matched).Error; err != nil {
+		return nil, fmt.Errorf("failed to count URLs: %w", err)
+	}
+	switch {
+	case !byFilter && int(matched) != len(batch.URLIDs):
+		return nil, ErrBatchURLsNotFound
+	case matched == 0:
+		return nil, ErrBatchEmpty
+	case matched > MaxBatchURLs:
+		return nil, fmt.Errorf("%w (%d, maximum %d)", ErrBatchTooLarge, matched, MaxBatchURLs)
+	}
+
+	if err := database.DB.Create(batch).Error; err != nil {
+		return nil, fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	// Return a copy; the background run keeps updating batch
+	created := *batch
+	b.launch(batch)
+	return &created, nil
+}
+
+// ResumeInterrupted continues batches left pending or running by a previous process.
+// It should be called once at startup.
+func (b *BatchCrawler) ResumeInterrupted() {
+	var batches []models.CrawlBatch
+	err := database.DB.Where("status IN ?", []models.CrawlBatchStatus{models.BatchPending, models.BatchRunning}).
+		Order("id").Find(&batches).Error
+	if err != nil {
+		log.Printf("Failed to load interrupted batches: %v", err)
+		return
+	}
+
+	for i := range batches {
+		log.Printf("Resuming interrupted batch ID=%d (%d/%d processed)", batches[i].ID, batches[i].Processed, batches[i].Total)
+		b.launch(&batches[i])
+	}
+}
+
+// launch runs a batch in the background unless this process is already running it
+func (b *BatchCrawler) launch(batch *models.CrawlBatch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.active[batch.ID] {
+		return
+	}
+	b.active[batch.ID] = true
+
+	go func() {
+		defer func() {
+			b.mu.Lock()
+			delete(b.active, batch.ID)
+			b.mu.Unlock()
+		}()
+		// A failed batch is not resumed at startup, so a panic cannot crash-loop the server
+		defer func() {
+			if value := recover(); value != nil {
+				excerpt := stackExcerpt(debug.Stack(), maxPanicStackLines)
+				b.fail(batch, fmt.Errorf("panic: %v\n%s", value, excerpt))
+			}
+		}()
+		b.run(batch)
+	}()
+}
+
+// selectURLs selects the URLs a batch covers
+func (b *BatchCrawler) selectURLs(db *gorm.DB, batch *models.CrawlBatch) *gorm.DB {
+	query := db.Model(&models.URL{})
+	if batch.Filter != nil {
+		return batch.Filter.Apply(query)
+	}
+	return query.Where("id IN ?", batch.URLIDs)
+}
+
+// run resolves the batch's URLs if that has not happened yet, then queues its pending items
+func (b *BatchCrawler) run(batch *models.CrawlBatch) {
+	if batch.Status == models.BatchPending {
+		if err := b.resolve(batch); err != nil {
+			b.fail(batch, err)
+			return
+		}
+		log.Printf("Batch ID=%d started: %d URLs, priority %s", batch.ID, batch.Total, batch.Priority)
+	} else if err := b.countItems(batch); err != nil {
+		b.fail(batch, err)
+		return
+	}
+
+	for {
+		var items []models.CrawlBatchItem
+		err := database.DB.Where("batch_id = ? AND outcome = ?", batch.ID, models.BatchItemPending).
+			Order("id").Limit(batchChunkSize).Find(&items).Error
+		if err != nil {
+			b.fail(batch, fmt.Errorf("failed to load batch items: %w", err))
+			return
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		for i := range items {
+			err := b.queueItem(batch, &items[i])
+			switch {
+			case errors.Is(err, ErrQueueClosed):
+				// The manager is shutting down; the batch resumes on the next start
+				b.checkpoint(batch)
+				log.Printf("Batch ID=%d interrupted at %d/%d: %v", batch.ID, batch.Processed, batch.Total, err)
+				return
+			case err != nil:
+				// An item that stays pending would be queued again on every pass
+				b.fail(batch, err)
+				return
+			}
+		}
+
+		b.checkpoint(batch)
+		log.Printf("Batch ID=%d progress: %d/%d", batch.ID, batch.Processed, batch.Total)
+	}
+
+	if err := b.countItems(batch); err != nil {
+		log.Printf("Failed to count batch ID=%d items: %v", batch.ID, err)
+	}
+	finished := time.Now()
+	batch.Status = models.BatchCompleted
+	batch.FinishedAt = &finished
+	if err := database.DB.Save(batch).Error; err != nil {
+		log.Printf("Failed to complete batch ID=%d: %v", batch.ID, err)
+		return
+	}
+
+	log.Printf("Batch ID=%d completed: %d queued, %d skipped, %d failed",
+		batch.ID, batch.Queued, batch.Skipped, batch.Failed)
+}
+
+// resolve records one pending item per matching URL and marks the batch running.
+// Matches are fixed here, so URLs that start matching the filter later are not added.
+func (b *BatchCrawler) resolve(batch *models.CrawlBatch) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// Drop items left by a resolve that did not commit its batch
+		if err := tx.Where("batch_id = ?", batch.ID).Delete(&models.CrawlBatchItem{}).Error; err != nil {
+			return fmt.Errorf("failed to clear batch items: %w", err)
+		}
+
+		var urls []models.URL
+		result := b.selectURLs(tx, batch).
+			Select("id", "url").
+			FindInBatches(&urls, batchChunkSize, func(chunk *gorm.DB, _ int) error {
+				items := make([]models.CrawlBatchItem, len(urls))
+				for i, url := range urls {
+					items[i] = models.CrawlBatchItem{BatchID: batch.ID, URLID: url.ID, URL: url.URL,
+						Outcome: models.BatchItemPending}
+				}
+				return tx.Create(&items).Error
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to resolve batch URLs: %w", result.Error)
+		}
+
+		now := time.Now()
+		batch.Status = models.BatchRunning
+		batch.Total = int(result.RowsAffected)
+		batch.StartedAt = &now
+		return tx.Save(batch).Error
+	})
+}
+
+// queueItem queues one URL of the batch and records the outcome. It waits while
+// the crawl queue is full, and returns an error once the queue is closed or if
+// the outcome cannot be saved.
+func (b *BatchCrawler) queueItem(batch *models.CrawlBatch, item *models.CrawlBatchItem) error {
+	var url models.URL
+	err := database.DB.First(&url, item.URLID).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return b.record(batch, item, models.BatchItemSkipped, "URL was deleted")
+	case err != nil:
+		return b.record(batch, item, models.BatchItemFailed, fmt.Sprintf("failed to load URL: %v", err))
+	case url.Status == models.StatusRunning:
+		return b.record(batch, item, models.BatchItemSkipped, "already in progress")
+	}
+
+	for {
+		// Mark the URL queued first, so a fast worker's final status is not overwritten
+		if url.Status != models.StatusQueued {
+			err := database.DB.Model(&models.URL{}).Where("id = ?", url.ID).Updates(map[string]interface{}{
+				"status":        models.StatusQueued,
+				"error_message": nil,
+			}).Error
+			if err != nil {
+				return b.record(batch, item, models.BatchItemFailed, fmt.Sprintf("failed to mark URL queued: %v", err))
+			}
+		}
+
+		err := b.manager.QueueURLWithPriority(url.ID, url.URL, batch.Priority)
+		if err == nil {
+			return b.record(batch, item, models.BatchItemQueued, "")
+		}
+
+		// Without its previous status the URL would look queued while it is not
+		if url.Status != models.StatusQueued {
+			restoreErr := database.DB.Model(&models.URL{}).Where("id = ?", url.ID).Updates(map[string]interface{}{
+				"status":        url.Status,
+				"error_message": url.ErrorMessage,
+			}).Error
+			if restoreErr != nil {
+				return b.record(batch, item, models.BatchItemFailed,
+					fmt.Sprintf("%v; failed to restore URL status: %v", err, restoreErr))
+			}
+		}
+
+		switch {
+		case errors.Is(err, ErrQueueFull):
+			time.Sleep(b.queueDelay)
+		case errors.Is(err, ErrQueueClosed):
+			return err
+		default:
+			return b.record(batch, item, models.BatchItemFailed, err.Error())
+		}
+	}
+}
+
+// record saves an item's outcome and counts it in the batch. An outcome that
+// cannot be saved is not counted.
+func (b *BatchCrawler) record(batch *models.CrawlBatch, item *models.CrawlBatchItem, outcome models.CrawlBatchItemOutcome, reason string) error {
+	item.Outcome = outcome
+	if reason != "" {
+		item.Reason = &reason
+	}
+	if outcome == models.BatchItemQueued {
+		now := time.Now()
+		item.QueuedAt = &now
+	}
+	if err := database.DB.Save(item).Error; err != nil {
+		return fmt.Errorf("failed to save outcome of URL ID=%d: %w", item.URLID, err)
+	}
+
+	switch outcome {
+	case models.BatchItemQueued:
+		batch.Queued++
+	case models.BatchItemSkipped:
+		batch.Skipped++
+	case models.BatchItemFailed:
+		batch.Failed++
+		errorMsg := fmt.Sprintf("URL ID=%d: %s", item.URLID, reason)
+		batch.LastError = &errorMsg
+	}
+	batch.Processed++
+	return nil
+}
+
+// checkpoint saves the batch counters
+func (b *BatchCrawler) checkpoint(batch *models.CrawlBatch) {
+	if err := b.countItems(batch); err != nil {
+		log.Printf("Failed to count batch ID=%d items: %v", batch.ID, err)
+	}
+	if err := database.DB.Save(batch).Error; err != nil {
+		log.Printf("Failed to save batch ID=%d progress: %v", batch.ID, err)
+	}
+}
+
+// countItems sets the batch counters from its items. Items are saved one by
+// one, so after a crash the counters saved at the last checkpoint can lag
+// behind items that are no longer pending and will not be processed again.
+func (b *BatchCrawler) countItems(batch *models.CrawlBatch) error {
+	var counts []struct {
+		Outcome models.CrawlBatchItemOutcome
+		Count   int
+	}
+	err := database.DB.Model(&models.CrawlBatchItem{}).
+		Select("outcome, COUNT(*) AS count").
+		Where("batch_id = ?", batch.ID).
+		Group("outcome").
+		Scan(&counts).Error
+	if err != nil {
+		return fmt.Errorf("failed to count batch items: %w", err)
+	}
+
+	batch.Queued, batch.Skipped, batch.Failed = 0, 0, 0
+	for _, count := range counts {
+		switch count.Outcome {
+		case models.BatchItemQueued:
+			batch.Queued = count.Count
+		case models.BatchItemSkipped:
+			batch.Skipped = count.Count
+		case models.BatchItemFailed:
+			batch.Failed = count.Count
+		}
+	}
+	batch.Processed = batch.Queued + batch.Skipped + batch.Failed
+	return nil
+}
+
+// fail marks the batch as failed
+func (b *BatchCrawler) fail(batch *models.CrawlBatch, err error) {
+	log.Printf("Batch ID=%d failed: %v", batch.ID, err)
+
+	finished := time.Now()
+	errorMsg := err.Error()
+	batch.Status = models.BatchFailed
+	batch.LastError = &errorMsg
+	batch.FinishedAt = &finished
+	if saveErr := database.DB.Save(batch).Error; saveErr != nil {
+		log.Printf("Failed to save batch ID=%d: %v", batch.ID, saveErr)
+	}
+}
+
+// uniqueIDs returns the IDs without duplicates, in their original order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
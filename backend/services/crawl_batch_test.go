@@ -0,0 +1,286 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+
+	"gorm.io/gorm"
+)
+
+// waitForBatch waits until a batch completes or fails
+func waitForBatch(t *testing.T, batchID uint) *models.CrawlBatch {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var batch models.CrawlBatch
+		database.DB.First(&batch, batchID)
+		if batch.Status == models.BatchCompleted || batch.Status == models.BatchFailed {
+			return &batch
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Batch ID=%d did not finish in time", batchID)
+	return nil
+}
+
+func TestBatchCrawler_QueuesFilteredURLs(t *testing.T) {
+	dir := t.TempDir()
+	page := `<html><head><title>Batch</title></head><body></body></html>`
+
+	for i := 0; i < 5; i++ {
+		writeFixture(t, dir, fmt.Sprintf("failed-%d", i),
+			fmt.Sprintf(`{"url": "https://example.com/failed-%d"}`, i), page)
+	}
+
+	// More failed URLs than the queue holds
+	manager := setupFixtureManagerWithConfig(t, dir, func(config *ManagerConfig) {
+		config.QueueSize = 2
+	})
+	errorType := "timeout"
+	var failed []models.URL
+	for i := 0; i < 5; i++ {
+		url := models.URL{URL: fmt.Sprintf("https://example.com/failed-%d", i), Status: models.StatusError,
+			ErrorType: &errorType}
+		database.DB.Create(&url)
+		failed = append(failed, url)
+	}
+	database.DB.Create(&models.URL{URL: "https://example.com/done", Status: models.StatusCompleted})
+
+	batches := NewBatchCrawler(manager)
+	batches.queueDelay = 5 * time.Millisecond
+
+	batch, err := batches.StartBatch(BatchRequest{
+		Filter:   &models.URLFilter{Status: "error", ErrorType: "timeout"},
+		Priority: models.PriorityLow,
+	})
+	if err != nil {
+		t.Fatalf("StartBatch failed: %v", err)
+	}
+
+	finished := waitForBatch(t, batch.ID)
+	if finished.Status != models.BatchCompleted || finished.Total != 5 || finished.Queued != 5 || finished.Progress() != 100 {
+		t.Fatalf("Expected all 5 failed URLs to be queued, got %+v", finished)
+	}
+	if finished.Filter == nil || finished.Filter.ErrorType != "timeout" || finished.Priority != models.PriorityLow {
+		t.Errorf("Expected the filter and priority to be stored, got %+v", finished)
+	}
+
+	var items []models.CrawlBatchItem
+	database.DB.Where("batch_id = ?", batch.ID).Order("id").Find(&items)
+	if len(items) != 5 {
+		t.Fatalf("Expected 5 batch items, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.Outcome != models.BatchItemQueued || item.QueuedAt == nil {
+			t.Errorf("URL ID=%d: expected queued, got %s", item.URLID, item.Outcome)
+		}
+	}
+
+	for _, url := range failed {
+		waitForStatus(t, url.ID, models.StatusCompleted)
+	}
+}
+
+func TestBatchCrawler_QueuesURLsByID(t *testing.T) {
+	manager := setupFixtureManager(t, t.TempDir())
+	manager.Pause()
+
+	queued := models.URL{URL: "https://example.com/a", Status: models.StatusCompleted}
+	database.DB.Create(&queued)
+	running := createRunningURL(t, "https://example.com/b", nil, time.Now())
+
+	batches := NewBatchCrawler(manager)
+	batch, err := batches.StartBatch(BatchRequest{URLIDs: []uint{queued.ID, running.ID, queued.ID}})
+	if err != nil {
+		t.Fatalf("StartBatch failed: %v", err)
+	}
+	if len(batch.URLIDs) != 2 || batch.Priority != models.PriorityNormal {
+		t.Errorf("Expected 2 unique IDs at normal priority, got %+v", batch)
+	}
+
+	finished := waitForBatch(t, batch.ID)
+	if finished.Queued != 1 || finished.Skipped != 1 || finished.Processed != 2 {
+		t.Errorf("Expected 1 queued and 1 skipped URL, got %+v", finished)
+	}
+
+	var skipped models.CrawlBatchItem
+	database.DB.Where("batch_id = ? AND url_id = ?", batch.ID, running.ID).First(&skipped)
+	if skipped.Outcome != models.BatchItemSkipped || skipped.Reason == nil || *skipped.Reason != "already in progress" {
+		t.Errorf("Expected the running URL to be skipped, got %s", skipped.Outcome)
+	}
+
+	if depth := manager.queue.depthByPriority()[models.PriorityNormal]; depth != 1 {
+		t.Errorf("Expected 1 normal priority job queued, got %d", depth)
+	}
+}
+
+func TestBatchCrawler_RejectsInvalidSelections(t *testing.T) {
+	manager := setupFixtureManager(t, t.TempDir())
+	batches := NewBatchCrawler(manager)
+
+	url := models.URL{URL: "https://example.com", Status: models.StatusCompleted}
+	database.DB.Create(&url)
+
+	tests := []struct {
+		name     string
+		req      BatchRequest
+		expected error
+	}{
+		{"nothing selected", BatchRequest{}, ErrBatchSelection},
+		{"empty filter", BatchRequest{Filter: &models.URLFilter{}}, ErrBatchSelection},
+		{"IDs and filter", BatchRequest{URLIDs: []uint{url.ID}, Filter: &models.URLFilter{Status: "error"}}, ErrBatchSelection},
+		{"invalid filter", BatchRequest{Filter: &models.URLFilter{Status: "broken"}}, ErrBatchSelection},
+		{"unknown ID", BatchRequest{URLIDs: []uint{url.ID, url.ID + 100}}, ErrBatchURLsNotFound},
+		{"no matches", BatchRequest{Filter: &models.URLFilter{Status: "error"}}, ErrBatchEmpty},
+	}
+
+	for _, tt := range tests {
+		if _, err := batches.StartBatch(tt.req); !errors.Is(err, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+	}
+
+	var count int64
+	database.DB.Model(&models.CrawlBatch{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected no batches to be created, got %d", count)
+	}
+}
+
+func TestBatchCrawler_FailsItemWhenURLCannotBeMarkedQueued(t *testing.T) {
+	manager := setupFixtureManager(t, t.TempDir())
+	manager.Pause()
+
+	url := models.URL{URL: "https://example.com/a", Status: models.StatusCompleted}
+	database.DB.Create(&url)
+	batch := models.CrawlBatch{Status: models.BatchRunning, Priority: models.PriorityNormal, Total: 1}
+	database.DB.Create(&batch)
+	item := models.CrawlBatchItem{BatchID: batch.ID, URLID: url.ID, URL: url.URL, Outcome: models.BatchItemPending}
+	database.DB.Create(&item)
+
+	database.DB.Callback().Update().Before("gorm:update").Register("test:fail_url_updates", func(db *gorm.DB) {
+		if db.Statement.Table == "urls" {
+			db.AddError(errors.New("database is read-only"))
+		}
+	})
+
+	batches := NewBatchCrawler(manager)
+	if err := batches.queueItem(&batch, &item); err != nil {
+		t.Fatalf("queueItem failed: %v", err)
+	}
+
+	if item.Outcome != models.BatchItemFailed || batch.Failed != 1 || batch.Queued != 0 {
+		t.Errorf("Expected the item to fail, got %s with batch %+v", item.Outcome, batch)
+	}
+	if depth := manager.queue.depthByPriority()[models.PriorityNormal]; depth != 0 {
+		t.Errorf("Expected nothing to be queued, got %d jobs", depth)
+	}
+
+	var unchanged models.URL
+	database.DB.First(&unchanged, url.ID)
+	if unchanged.Status != models.StatusCompleted {
+		t.Errorf("Expected the URL to stay completed, got %s", unchanged.Status)
+	}
+}
+
+func TestBatchCrawler_FailsWhenOutcomeCannotBeSaved(t *testing.T) {
+	manager := setupFixtureManager(t, t.TempDir())
+	manager.Pause()
+
+	url := models.URL{URL: "https://example.com/a", Status: models.StatusCompleted}
+	database.DB.Create(&url)
+
+	database.DB.Callback().Update().Before("gorm:update").Register("test:fail_item_updates", func(db *gorm.DB) {
+		if db.Statement.Table == "crawl_batch_items" {
+			db.AddError(errors.New("database is read-only"))
+		}
+	})
+
+	batches := NewBatchCrawler(manager)
+	batch, err := batches.StartBatch(BatchRequest{URLIDs: []uint{url.ID}})
+	if err != nil {
+		t.Fatalf("StartBatch failed: %v", err)
+	}
+
+	// The item stays pending, so the batch must stop rather than queue it again
+	finished := waitForBatch(t, batch.ID)
+	if finished.Status != models.BatchFailed || finished.Processed != 0 || finished.Queued != 0 {
+		t.Errorf("Expected a failed batch with nothing counted, got %+v", finished)
+	}
+	if finished.LastError == nil || !strings.Contains(*finished.LastError, "database is read-only") {
+		t.Errorf("Expected the save error, got %v", formatOptionalString(finished.LastError))
+	}
+	if depth := manager.queue.depthByPriority()[models.PriorityNormal]; depth != 1 {
+		t.Errorf("Expected the URL to be queued once, got %d jobs", depth)
+	}
+}
+
+func TestBatchCrawler_ResumeCountsItemsRecordedBeforeACrash(t *testing.T) {
+	manager := setupFixtureManager(t, t.TempDir())
+	manager.Pause()
+
+	var urls []models.URL
+	for i := 0; i < 3; i++ {
+		url := models.URL{URL: fmt.Sprintf("https://example.com/%d", i), Status: models.StatusCompleted}
+		database.DB.Create(&url)
+		urls = append(urls, url)
+	}
+
+	// The process died after recording the first item but before the checkpoint
+	now := time.Now()
+	batch := models.CrawlBatch{Status: models.BatchRunning, Priority: models.PriorityNormal, Total: 3, StartedAt: &now}
+	database.DB.Create(&batch)
+	for i, url := range urls {
+		item := models.CrawlBatchItem{BatchID: batch.ID, URLID: url.ID, URL: url.URL, Outcome: models.BatchItemPending}
+		if i == 0 {
+			item.Outcome = models.BatchItemQueued
+			item.QueuedAt = &now
+		}
+		database.DB.Create(&item)
+	}
+
+	NewBatchCrawler(manager).ResumeInterrupted()
+
+	finished := waitForBatch(t, batch.ID)
+	if finished.Status != models.BatchCompleted || finished.Processed != 3 || finished.Queued != 3 || finished.Progress() != 100 {
+		t.Errorf("Expected all 3 items to be counted, got %+v", finished)
+	}
+	if depth := manager.queue.depthByPriority()[models.PriorityNormal]; depth != 2 {
+		t.Errorf("Expected only the 2 pending items to be queued, got %d jobs", depth)
+	}
+}
+
+func TestBatchCrawler_RecoversFromPanics(t *testing.T) {
+	manager := setupFixtureManager(t, t.TempDir())
+	manager.Pause()
+
+	url := models.URL{URL: "https://example.com/a", Status: models.StatusCompleted}
+	database.DB.Create(&url)
+
+	database.DB.Callback().Query().Before("gorm:query").Register("test:panic_on_items", func(db *gorm.DB) {
+		if db.Statement.Table == "crawl_batch_items" {
+			panic("corrupt batch item")
+		}
+	})
+
+	batches := NewBatchCrawler(manager)
+	batch, err := batches.StartBatch(BatchRequest{URLIDs: []uint{url.ID}})
+	if err != nil {
+		t.Fatalf("StartBatch failed: %v", err)
+	}
+
+	// A failed batch is not resumed, so the panic does not repeat on the next start
+	finished := waitForBatch(t, batch.ID)
+	if finished.Status != models.BatchFailed || finished.LastError == nil ||
+		!strings.Contains(*finished.LastError, "panic: corrupt batch item") {
+		t.Errorf("Expected the batch to fail with the panic, got %+v", finished)
+	}
+}
@@ -2,16 +2,24 @@ package services
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strings"
+	"syscall"
 	"time"
 )
 
+// errTooManyRedirects stops the HTTP client after MaxRedirects redirects
+var errTooManyRedirects = errors.New("too many redirects")
+
 // CrawlerConfig holds configuration for the crawler service
 type CrawlerConfig struct {
 	MaxPageSize    int64         // Maximum page size in bytes (5MB)
@@ -32,11 +40,10 @@ func DefaultCrawlerConfig() *CrawlerConfig {
 	}
 }
 
-// CrawlerService handles web crawling operations
+// CrawlerService fetches pages over HTTP
 type CrawlerService struct {
 	config *CrawlerConfig
 	client *http.Client
-	parser *HTMLParser
 }
 
 // NewCrawlerService creates a new crawler service with the given configuration
@@ -51,7 +58,7 @@ func NewCrawlerService(config *CrawlerConfig) *CrawlerService {
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Prevent infinite redirect loops
 			if len(via) >= config.MaxRedirects {
-				return errors.New("too many redirects")
+				return errTooManyRedirects
 			}
 			return nil
 		},
@@ -64,7 +71,6 @@ func NewCrawlerService(config *CrawlerConfig) *CrawlerService {
 	return &CrawlerService{
 		config: config,
 		client: client,
-		parser: NewHTMLParser(),
 	}
 }
 
@@ -73,23 +79,47 @@ type CrawlResponse struct {
 	HTML         string        // Raw HTML content
 	StatusCode   int           // HTTP status code
 	ContentType  string        // Content-Type header
+	Headers      http.Header   // All response headers of the final response
 	ResponseSize int64         // Size of response in bytes
 	Duration     time.Duration // Time taken to fetch
+	Timing       FetchTiming   // Per-phase breakdown of Duration
+	TLS          *TLSInfo      // TLS connection details (nil for plain HTTP)
 	URL          string        // Final URL (after redirects)
+	NotModified  bool          // True if the server answered 304 to a conditional request
+	ETag         string        // ETag header, for the next conditional request
+	LastModified string        // Last-Modified header, for the next conditional request
+}
+
+// CacheValidators are sent with a request to make it conditional
+type CacheValidators struct {
+	ETag         string // Sent as If-None-Match
+	LastModified string // Sent as If-Modified-Since
+}
+
+// IsEmpty returns true if there is nothing to make the request conditional on
+func (v *CacheValidators) IsEmpty() bool {
+	return v == nil || (v.ETag == "" && v.LastModified == "")
 }
 
 // CrawlError represents a crawling error with context
 type CrawlError struct {
-	Type    string // Error type: "network", "timeout", "too_large", "invalid_url"
-	Message string // Human-readable error message
-	URL     string // URL that caused the error
-	Err     error  // Underlying error
+	Type       string        // Error type, one of models.CrawlErrorTypes
+	Message    string        // Human-readable error message
+	URL        string        // URL that caused the error
+	Err        error         // Underlying error
+	StatusCode int           // HTTP status code for "http_error" (0 otherwise)
+	RetryAfter time.Duration // Delay requested by a Retry-After header (0 if none)
 }
 
 func (e *CrawlError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error
+func (e *CrawlError) Unwrap() error {
+	return e.Err
+}
+
 // NewCrawlError creates a new crawl error with the given details
 func NewCrawlError(errorType, message, url string, err error) *CrawlError {
 	return &CrawlError{
@@ -100,8 +130,10 @@ func NewCrawlError(errorType, message, url string, err error) *CrawlError {
 	}
 }
 
-// FetchURL fetches and validates a URL with all safety measures
-func (c *CrawlerService) FetchURL(rawURL string) (*CrawlResponse, error) {
+// FetchURL fetches and validates a URL with all safety measures.
+// If validators are given, the request is conditional and a 304 response is
+// returned with NotModified set instead of a body.
+func (c *CrawlerService) FetchURL(rawURL string, validators *CacheValidators) (*CrawlResponse, error) {
 	startTime := time.Now()
 
 	log.Printf("DEBUG: Starting to fetch URL: %s", rawURL)
@@ -125,6 +157,21 @@ func (c *CrawlerService) FetchURL(rawURL string) (*CrawlResponse, error) {
 	req.Header.Set("DNT", "1")
 	req.Header.Set("Connection", "keep-alive")
 
+	// Make the request conditional on the previous crawl's validators
+	conditional := !validators.IsEmpty()
+	if conditional {
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+
+	// Trace connection phases so slow servers can be told apart from slow parsing
+	tracer := &fetchTracer{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), tracer.clientTrace()))
+
 	log.Printf("DEBUG: Making HTTP request to: %s", rawURL)
 
 	// Perform HTTP request
@@ -137,16 +184,33 @@ func (c *CrawlerService) FetchURL(rawURL string) (*CrawlResponse, error) {
 	log.Printf("DEBUG: Received response - Status: %d, Content-Type: %s, Content-Encoding: %s",
 		resp.StatusCode, resp.Header.Get("Content-Type"), resp.Header.Get("Content-Encoding"))
 
+	// Page unchanged since the previous crawl
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		timing := tracer.finish(startTime, time.Now())
+
+		log.Printf("DEBUG: Not modified since last crawl: %s (%v)", rawURL, timing.Total)
+
+		return &CrawlResponse{
+			StatusCode:   resp.StatusCode,
+			Headers:      resp.Header.Clone(),
+			Duration:     timing.Total,
+			Timing:       timing,
+			TLS:          inspectTLS(resp.TLS),
+			URL:          resp.Request.URL.String(),
+			NotModified:  true,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}, nil
+	}
+
 	// Check status code
-	if resp.StatusCode >= 400 {
-		return nil, NewCrawlError("http_error",
-			fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status),
-			rawURL, nil)
+	if err := checkResponseStatus(rawURL, resp.StatusCode, resp.Status, resp.Header); err != nil {
+		return nil, err
 	}
 
 	// Validate content type
 	contentType := resp.Header.Get("Content-Type")
-	if !c.isHTMLContent(contentType) {
+	if !isHTMLContentType(contentType) {
 		return nil, NewCrawlError("invalid_content",
 			fmt.Sprintf("Content-Type '%s' is not HTML", contentType),
 			rawURL, nil)
@@ -158,17 +222,25 @@ func (c *CrawlerService) FetchURL(rawURL string) (*CrawlResponse, error) {
 		return nil, err // Already wrapped in CrawlError
 	}
 
-	duration := time.Since(startTime)
+	timing := tracer.finish(startTime, time.Now())
+	duration := timing.Total
 
-	log.Printf("DEBUG: Successfully fetched %d bytes in %v", len(body), duration)
+	log.Printf("DEBUG: Successfully fetched %d bytes in %v (dns=%v connect=%v tls=%v ttfb=%v download=%v)",
+		len(body), duration, timing.DNSLookup, timing.TCPConnect, timing.TLSHandshake,
+		timing.TimeToFirstByte, timing.Download)
 
 	return &CrawlResponse{
 		HTML:         string(body),
 		StatusCode:   resp.StatusCode,
 		ContentType:  contentType,
+		Headers:      resp.Header.Clone(),
 		ResponseSize: int64(len(body)),
 		Duration:     duration,
+		Timing:       timing,
+		TLS:          inspectTLS(resp.TLS),
 		URL:          resp.Request.URL.String(), // Final URL after redirects
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
 	}, nil
 }
 
@@ -190,37 +262,82 @@ func (c *CrawlerService) validateURL(rawURL string) error {
 	return nil
 }
 
-// classifyNetworkError categorizes network errors for better error handling
-func (c *CrawlerService) classifyNetworkError(url string, err error) *CrawlError {
-	if errors.Is(err, context.DeadlineExceeded) {
-		return NewCrawlError("timeout", "Request timed out", url, err)
+// classifyNetworkError categorizes network errors by inspecting the error chain
+func (c *CrawlerService) classifyNetworkError(rawURL string, err error) *CrawlError {
+	if errors.Is(err, errTooManyRedirects) {
+		return NewCrawlError("redirect_error", "Too many redirects", rawURL, err)
+	}
+
+	if isTimeout(err) {
+		return NewCrawlError("timeout", "Request timed out", rawURL, err)
 	}
 
-	if strings.Contains(err.Error(), "no such host") {
-		return NewCrawlError("dns_error", "DNS lookup failed", url, err)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return NewCrawlError("dns_error", "DNS lookup failed: host not found", rawURL, err)
+		}
+		return NewCrawlError("dns_error", "DNS lookup failed", rawURL, err)
+	}
+
+	if message, ok := certificateErrorMessage(err); ok {
+		return NewCrawlError("tls_error", message, rawURL, err)
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return NewCrawlError("connection_error", "Connection refused", rawURL, err)
 	}
 
-	if strings.Contains(err.Error(), "connection refused") {
-		return NewCrawlError("connection_error", "Connection refused", url, err)
+	if errors.Is(err, syscall.ECONNRESET) {
+		return NewCrawlError("connection_error", "Connection reset by peer", rawURL, err)
 	}
 
-	if strings.Contains(err.Error(), "too many redirects") {
-		return NewCrawlError("redirect_error", "Too many redirects", url, err)
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return NewCrawlError("connection_error", "Failed to connect", rawURL, err)
 	}
 
 	// Generic network error
-	return NewCrawlError("network", "Network error occurred", url, err)
+	return NewCrawlError("network", "Network error occurred", rawURL, err)
+}
+
+// isTimeout reports whether an error chain contains a deadline or network timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Covers *url.Error and *net.OpError, which report timeouts through net.Error
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
 }
 
-// isHTMLContent checks if the content type indicates HTML content
-func (c *CrawlerService) isHTMLContent(contentType string) bool {
-	if contentType == "" {
-		return true // Assume HTML if no content type
+// certificateErrorMessage describes a TLS certificate verification failure, if err is one
+func certificateErrorMessage(err error) (string, bool) {
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &hostnameErr) {
+		return "TLS certificate is not valid for this host", true
 	}
 
-	contentType = strings.ToLower(contentType)
-	return strings.Contains(contentType, "text/html") ||
-		strings.Contains(contentType, "application/xhtml+xml")
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &invalidErr) {
+		if invalidErr.Reason == x509.Expired {
+			return "TLS certificate has expired or is not yet valid", true
+		}
+		return "TLS certificate is invalid", true
+	}
+
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthorityErr) {
+		return "TLS certificate is signed by an unknown authority", true
+	}
+
+	var verificationErr *tls.CertificateVerificationError
+	if errors.As(err, &verificationErr) {
+		return "TLS certificate verification failed", true
+	}
+
+	return "", false
 }
 
 // readResponseBody reads the response body with size limits
@@ -230,6 +347,9 @@ func (c *CrawlerService) readResponseBody(resp *http.Response, url string) ([]by
 
 	body, err := io.ReadAll(limitedReader)
 	if err != nil {
+		if isTimeout(err) {
+			return nil, NewCrawlError("timeout", "Timed out reading response body", url, err)
+		}
 		return nil, NewCrawlError("read_error", "Failed to read response body", url, err)
 	}
 
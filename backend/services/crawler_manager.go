@@ -1,10 +1,18 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"runtime/debug"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"web-crawler/database"
 	"web-crawler/models"
@@ -17,28 +25,179 @@ type CrawlJob struct {
 	URLID    uint      `json:"url_id"`
 	URL      string    `json:"url"`
 	QueuedAt time.Time `json:"queued_at"`
+	Attempt  int       `json:"attempt"` // 1 for the first attempt, incremented on each retry
+
+	Priority models.CrawlPriority `json:"priority"`
+
+	attempt *attemptRecorder // Log of the attempt while it is being processed
+	host    string           // Host the job fetches from, for per-host pausing
+}
+
+// pendingRetry is a failed job waiting for its backoff delay to pass
+type pendingRetry struct {
+	job   *CrawlJob
+	timer *time.Timer
+}
+
+// ManagerConfig holds configuration for the crawl manager
+type ManagerConfig struct {
+	QueueSize     int            // Maximum number of queued jobs (100)
+	Crawler       *CrawlerConfig // HTTP fetch configuration (nil = defaults)
+	Fetcher       Fetcher        // Page source (nil = HTTP fetching with the Crawler config)
+	Parser        PageParser     // HTML parser (nil = HTMLParser)
+	SnapshotStore *SnapshotStore // Raw HTML snapshot storage (nil = snapshots disabled)
+	RetryPolicy   *RetryPolicy   // Retries for transient failures (nil = defaults, empty policy = no retries)
+	MaxLinks      int            // Found links saved per page, 0 = unlimited (200)
+
+	HeartbeatInterval time.Duration // How often the worker reports it is alive (15s)
+}
+
+// DefaultManagerConfig returns the default crawl manager configuration
+func DefaultManagerConfig() *ManagerConfig {
+	return &ManagerConfig{
+		QueueSize:         100, // Reasonable queue size for demo
+		MaxLinks:          200,
+		HeartbeatInterval: 15 * time.Second,
+	}
 }
 
 // CrawlManager handles background crawling operations
 type CrawlManager struct {
-	crawler   *CrawlerService
-	queue     chan *CrawlJob
+	fetcher   Fetcher
+	parser    PageParser
+	snapshots *SnapshotStore
+	queue     *jobQueue
 	isRunning bool
 	queueSize int
+	rateLimit time.Duration
+	maxLinks  int // Found links saved per page, 0 = unlimited
+
+	retryPolicy *RetryPolicy
+	retryMu     sync.Mutex
+	retries     map[uint]*pendingRetry // Keyed by URL ID
+
+	workerID          string // Recorded on URLs while this manager crawls them
+	heartbeatInterval time.Duration
+	heartbeatStop     chan struct{}
+	heartbeatOnce     sync.Once
+
+	panics      atomic.Int64 // Jobs that panicked since start
+	lastPanicAt atomic.Pointer[time.Time]
 }
 
-// NewCrawlManager creates a new crawl manager instance
+// NewCrawlManager creates a new crawl manager instance with the default configuration
 func NewCrawlManager() *CrawlManager {
-	queueSize := 100 // Reasonable queue size for demo
+	return NewCrawlManagerWithConfig(nil)
+}
+
+// NewCrawlManagerWithConfig creates a new crawl manager with the given configuration
+func NewCrawlManagerWithConfig(config *ManagerConfig) *CrawlManager {
+	if config == nil {
+		config = DefaultManagerConfig()
+	}
+
+	crawlerConfig := config.Crawler
+	if crawlerConfig == nil {
+		crawlerConfig = DefaultCrawlerConfig()
+	}
+
+	fetcher := config.Fetcher
+	if fetcher == nil {
+		fetcher = NewCrawlerService(crawlerConfig)
+	}
+
+	parser := config.Parser
+	if parser == nil {
+		parser = NewHTMLParser()
+	}
+
+	retryPolicy := config.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = DefaultRetryPolicy()
+	}
 
-	return &CrawlManager{
-		crawler:   NewCrawlerService(nil), // Use default config
-		queue:     make(chan *CrawlJob, queueSize),
+	heartbeatInterval := config.HeartbeatInterval
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = DefaultManagerConfig().HeartbeatInterval
+	}
+
+	cm := &CrawlManager{
+		fetcher:   fetcher,
+		parser:    parser,
+		snapshots: config.SnapshotStore,
+		queue:     newJobQueue(config.QueueSize),
 		isRunning: false,
-		queueSize: queueSize,
+		queueSize: config.QueueSize,
+		maxLinks:  config.MaxLinks,
+		rateLimit: crawlerConfig.RateLimit,
+
+		retryPolicy: retryPolicy,
+		retries:     make(map[uint]*pendingRetry),
+
+		workerID:          newWorkerID(),
+		heartbeatInterval: heartbeatInterval,
+		heartbeatStop:     make(chan struct{}),
+	}
+	cm.restorePauses()
+	return cm
+}
+
+// restorePauses pauses the queue and the hosts that were paused before a restart
+func (cm *CrawlManager) restorePauses() {
+	if database.DB == nil {
+		return
+	}
+
+	var pauses []models.QueuePause
+	if err := database.DB.Find(&pauses).Error; err != nil {
+		log.Printf("Failed to load queue pauses: %v", err)
+		return
+	}
+	cm.queue.restorePauses(pauses)
+	if len(pauses) > 0 {
+		log.Printf("Restored %d queue pauses from before the restart", len(pauses))
 	}
 }
 
+// savePause records a pause of the whole queue (models.AllHosts) or of one host.
+// A failure is logged; the pause still applies until the process restarts.
+func (cm *CrawlManager) savePause(host string) {
+	pause := models.QueuePause{Host: host, PausedAt: time.Now()}
+	if err := database.DB.Save(&pause).Error; err != nil {
+		log.Printf("Failed to save pause of %s: %v", host, err)
+	}
+}
+
+// deletePause removes a saved pause of the whole queue or of one host
+func (cm *CrawlManager) deletePause(host string) {
+	if err := database.DB.Delete(&models.QueuePause{Host: host}).Error; err != nil {
+		log.Printf("Failed to delete pause of %s: %v", host, err)
+	}
+}
+
+// newWorkerID returns an identifier unique to this manager instance
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// WorkerID returns the identifier recorded on URLs this manager is crawling
+func (cm *CrawlManager) WorkerID() string {
+	return cm.workerID
+}
+
+// SnapshotStore returns the raw HTML snapshot store, or nil if snapshots are disabled
+func (cm *CrawlManager) SnapshotStore() *SnapshotStore {
+	return cm.snapshots
+}
+
 // Start begins processing crawl jobs in the background
 func (cm *CrawlManager) Start() {
 	if cm.isRunning {
@@ -47,8 +206,10 @@ func (cm *CrawlManager) Start() {
 	}
 
 	cm.isRunning = true
-	log.Println("Starting CrawlManager background processor")
+	log.Printf("Starting CrawlManager background processor (worker %s)", cm.workerID)
 
+	cm.registerWorker()
+	go cm.heartbeat()
 	go cm.processQueue()
 }
 
@@ -60,54 +221,202 @@ func (cm *CrawlManager) Stop() {
 
 	log.Println("Stopping CrawlManager...")
 	cm.isRunning = false
-	close(cm.queue)
+	cm.stopHeartbeat()
+
+	// Pending retries are dropped like queued jobs
+	cm.retryMu.Lock()
+	for urlID, retry := range cm.retries {
+		retry.timer.Stop()
+		delete(cm.retries, urlID)
+	}
+	cm.retryMu.Unlock()
+
+	cm.queue.close()
+
+	// Deregister so the reaper recovers anything left running without waiting for the worker timeout
+	if err := database.DB.Delete(&models.CrawlWorker{ID: cm.workerID}).Error; err != nil {
+		log.Printf("Failed to deregister worker %s: %v", cm.workerID, err)
+	}
 }
 
-// QueueURL adds a URL to the crawling queue
+// registerWorker records this manager as a live worker
+func (cm *CrawlManager) registerWorker() {
+	hostname, _ := os.Hostname()
+	now := time.Now()
+	worker := models.CrawlWorker{
+		ID:              cm.workerID,
+		Hostname:        hostname,
+		StartedAt:       now,
+		LastHeartbeatAt: now,
+	}
+
+	if err := database.DB.Create(&worker).Error; err != nil {
+		log.Printf("Failed to register worker %s: %v", cm.workerID, err)
+	}
+}
+
+// heartbeat periodically marks the worker as alive until the manager stops
+func (cm *CrawlManager) heartbeat() {
+	ticker := time.NewTicker(cm.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cm.heartbeatStop:
+			return
+		case <-ticker.C:
+			err := database.DB.Model(&models.CrawlWorker{}).
+				Where("id = ?", cm.workerID).
+				Update("last_heartbeat_at", time.Now()).Error
+			if err != nil {
+				log.Printf("Failed to record heartbeat for worker %s: %v", cm.workerID, err)
+			}
+		}
+	}
+}
+
+// stopHeartbeat stops reporting the worker as alive, so its running crawls become reapable
+func (cm *CrawlManager) stopHeartbeat() {
+	cm.heartbeatOnce.Do(func() {
+		close(cm.heartbeatStop)
+	})
+}
+
+// QueueURL adds a URL to the crawling queue with normal priority
 func (cm *CrawlManager) QueueURL(urlID uint, url string) error {
+	return cm.QueueURLWithPriority(urlID, url, models.PriorityNormal)
+}
+
+// QueueURLWithPriority adds a URL to the crawling queue. Higher priorities are
+// served first without starving lower ones; system-initiated crawls use low.
+func (cm *CrawlManager) QueueURLWithPriority(urlID uint, url string, priority models.CrawlPriority) error {
 	if !cm.isRunning {
 		return fmt.Errorf("crawl manager is not running")
 	}
 
+	// A manual crawl replaces any retry still waiting for its backoff
+	cm.cancelRetry(urlID)
+
 	job := &CrawlJob{
 		URLID:    urlID,
 		URL:      url,
 		QueuedAt: time.Now(),
+		Attempt:  1,
+		Priority: priority,
 	}
 
-	// Non-blocking queue insertion
-	select {
-	case cm.queue <- job:
-		log.Printf("Queued URL for crawling: ID=%d, URL=%s", urlID, url)
-		log.Printf("Queue status after queuing: length=%d, size=%d", len(cm.queue), cm.queueSize)
-		return nil
-	default:
-		log.Printf("Queue is full, cannot add URL ID=%d", urlID)
-		return fmt.Errorf("crawl queue is full (size: %d)", cm.queueSize)
+	return cm.enqueue(job)
+}
+
+// enqueue adds a job to the queue without blocking. Jobs are accepted while
+// the queue is paused and wait until it is resumed.
+func (cm *CrawlManager) enqueue(job *CrawlJob) error {
+	if err := cm.queue.push(job); err != nil {
+		log.Printf("Cannot queue URL ID=%d: %v", job.URLID, err)
+		return err
+	}
+
+	log.Printf("Queued URL for crawling: ID=%d, URL=%s, attempt=%d, priority=%s", job.URLID, job.URL, job.Attempt, job.Priority)
+	log.Printf("Queue status after queuing: length=%d, size=%d", cm.queue.len(), cm.queueSize)
+	return nil
+}
+
+// Pause stops the worker from starting new jobs. The job in flight finishes,
+// and URLs can still be queued. The pause is saved and restored after a restart.
+// It returns false if the queue was already paused.
+func (cm *CrawlManager) Pause() bool {
+	paused := cm.queue.pause()
+	if paused {
+		cm.savePause(models.AllHosts)
+		log.Printf("Crawl queue paused on worker %s (%d jobs queued)", cm.workerID, cm.queue.len())
+	}
+	return paused
+}
+
+// Resume lets the worker take jobs again. It returns false if the queue was not paused.
+func (cm *CrawlManager) Resume() bool {
+	resumed := cm.queue.resume()
+	if resumed {
+		cm.deletePause(models.AllHosts)
+		log.Printf("Crawl queue resumed on worker %s (%d jobs queued)", cm.workerID, cm.queue.len())
+	}
+	return resumed
+}
+
+// PauseHost holds back jobs for one host while other hosts keep being crawled.
+// The host may be given as a host name or URL; the normalized host is returned.
+// Like Pause, it is saved and restored after a restart.
+func (cm *CrawlManager) PauseHost(host string) (string, bool, error) {
+	host, err := NormalizeHost(host)
+	if err != nil {
+		return "", false, err
+	}
+
+	paused := cm.queue.pauseHost(host)
+	if paused {
+		cm.savePause(host)
+		log.Printf("Crawling paused for host %s on worker %s", host, cm.workerID)
+	}
+	return host, paused, nil
+}
+
+// ResumeHost releases the jobs held back for a host
+func (cm *CrawlManager) ResumeHost(host string) (string, bool, error) {
+	host, err := NormalizeHost(host)
+	if err != nil {
+		return "", false, err
 	}
+
+	resumed := cm.queue.resumeHost(host)
+	if resumed {
+		cm.deletePause(host)
+		log.Printf("Crawling resumed for host %s on worker %s", host, cm.workerID)
+	}
+	return host, resumed, nil
 }
 
 // GetQueueStatus returns information about the current queue state
 func (cm *CrawlManager) GetQueueStatus() map[string]interface{} {
-	return map[string]interface{}{
-		"is_running":   cm.isRunning,
-		"queue_length": len(cm.queue),
-		"queue_size":   cm.queueSize,
+	pausedAt, pausedHosts := cm.queue.pauseState()
+
+	status := map[string]interface{}{
+		"is_running":        cm.isRunning,
+		"worker_id":         cm.workerID,
+		"queue_length":      cm.queue.len(),
+		"queue_by_priority": cm.queue.depthByPriority(),
+		"queue_size":        cm.queueSize,
+		"pending_retries":   cm.pendingRetryCount(),
+		"panics":            cm.panics.Load(),
+		"paused":            pausedAt != nil,
+		"paused_hosts":      pausedHosts,
 	}
+	if pausedAt != nil {
+		status["paused_at"] = *pausedAt
+	}
+	if lastPanicAt := cm.lastPanicAt.Load(); lastPanicAt != nil {
+		status["last_panic_at"] = *lastPanicAt
+	}
+	return status
 }
 
 // processQueue continuously processes jobs from the queue
 func (cm *CrawlManager) processQueue() {
+	// Panics in a job are recovered per job; this only guards the loop itself
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("CrawlManager panic recovered: %v", r)
 			cm.isRunning = false
+			cm.stopHeartbeat()
 		}
 	}()
 
 	log.Println("CrawlManager processor started")
 
-	for job := range cm.queue {
+	for {
+		job, ok := cm.queue.pop()
+		if !ok {
+			break
+		}
 		log.Printf("Received job from queue: ID=%d, URL=%s", job.URLID, job.URL)
 
 		if !cm.isRunning {
@@ -116,23 +425,94 @@ func (cm *CrawlManager) processQueue() {
 		}
 
 		log.Printf("About to process job: ID=%d", job.URLID)
-		cm.processSingleJob(job)
+		cm.runJob(job)
 		log.Printf("Finished processing job: ID=%d", job.URLID)
 
 		// Rate limiting: wait between jobs
-		time.Sleep(cm.crawler.config.RateLimit)
+		time.Sleep(cm.rateLimit)
 	}
 
 	log.Println("CrawlManager processor stopped")
 }
 
+// runJob processes a job, recovering from a panic so one bad page cannot stop the worker
+func (cm *CrawlManager) runJob(job *CrawlJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			cm.handleJobPanic(job, r, debug.Stack())
+		}
+	}()
+
+	cm.processSingleJob(job)
+}
+
+// handleJobPanic fails the job that panicked, keeping an excerpt of the stack for
+// debugging. A job whose attempt already finished is left as it was.
+func (cm *CrawlManager) handleJobPanic(job *CrawlJob, value interface{}, stack []byte) {
+	cm.panics.Add(1)
+	now := time.Now()
+	cm.lastPanicAt.Store(&now)
+
+	excerpt := stackExcerpt(stack, maxPanicStackLines)
+	log.Printf("Crawl job panicked for URL ID=%d: %v\n%s", job.URLID, value, excerpt)
+
+	// A panic after the attempt ended, e.g. in snapshot retention after the
+	// success commit, must not turn a completed crawl into a failed one
+	if job.attempt.finished() {
+		return
+	}
+
+	err := fmt.Errorf("panic: %v\n%s", value, excerpt)
+	job.attempt.logf("Crawl job panicked for URL ID=%d: %v", job.URLID, value)
+	cm.failJob(job, "panic", err)
+}
+
+// maxPanicStackLines bounds the stack excerpt stored with a panic
+const maxPanicStackLines = 20
+
+// stackExcerpt returns the frames of a debug.Stack() trace starting at the
+// code that panicked, skipping the runtime and recovery frames
+func stackExcerpt(stack []byte, maxLines int) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+
+	start := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			start = i + 2 // The panic call and its file:line
+			break
+		}
+	}
+	if start >= len(lines) {
+		start = 0
+	}
+
+	lines = lines[start:]
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+	}
+	return strings.Join(lines, "\n")
+}
+
 // processSingleJob handles the crawling of a single URL
 func (cm *CrawlManager) processSingleJob(job *CrawlJob) {
-	log.Printf("Processing crawl job: ID=%d, URL=%s", job.URLID, job.URL)
+	job.attempt = startAttempt(job, cm.workerID)
+	job.attempt.logf("Processing crawl job: ID=%d, URL=%s, attempt=%d", job.URLID, job.URL, job.Attempt)
 
-	// Update URL status to "running"
-	if err := cm.updateURLStatus(job.URLID, models.StatusRunning, nil); err != nil {
-		log.Printf("Failed to update URL status to running: %v", err)
+	// Update URL status to "running", taking the lease, and record which attempt this is
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":           models.StatusRunning,
+		"error_message":    nil,
+		"error_type":       nil,
+		"retry_count":      job.Attempt - 1,
+		"next_retry_at":    nil,
+		"worker_id":        cm.workerID,
+		"crawl_started_at": now,
+		"updated_at":       now,
+	}
+	if err := database.DB.Model(&models.URL{}).Where("id = ?", job.URLID).Updates(updates).Error; err != nil {
+		job.attempt.logf("Failed to update URL status to running: %v", err)
+		job.attempt.finish(models.AttemptFailed, "database_error", err)
 		return
 	}
 
@@ -151,26 +531,88 @@ func (cm *CrawlManager) processSingleJob(job *CrawlJob) {
 	cm.handleCrawlSuccess(job, result, duration)
 }
 
+// crawlOutput bundles everything produced by a successful crawl
+type crawlOutput struct {
+	Response      *CrawlResponse       // Fetch result, including timing breakdown
+	Data          *ParsedData          // Extracted page data (nil if the page was not modified)
+	ParseDuration time.Duration        // Time spent in the HTML parser
+	Snapshot      *models.PageSnapshot // Stored raw body (nil if snapshots are disabled)
+}
+
 // performCrawl executes the actual crawling and parsing
-func (cm *CrawlManager) performCrawl(job *CrawlJob) (*ParsedData, error) {
-	// Fetch the URL
-	response, err := cm.crawler.FetchURL(job.URL)
+func (cm *CrawlManager) performCrawl(job *CrawlJob) (*crawlOutput, error) {
+	// Fetch the URL, conditional on the previous crawl's validators
+	response, err := cm.fetcher.FetchURL(job.URL, cm.loadCacheValidators(job.URLID))
 	if err != nil {
 		return nil, err
 	}
+	job.attempt.recordResponse(response)
+
+	// Nothing to parse if the page has not changed
+	if response.NotModified {
+		job.attempt.logf("Fetched %s: HTTP %d, not modified", response.URL, response.StatusCode)
+		return &crawlOutput{Response: response}, nil
+	}
+	job.attempt.logf("Fetched %s: HTTP %d, %d bytes in %v",
+		response.URL, response.StatusCode, response.ResponseSize, response.Duration.Round(time.Millisecond))
 
 	// Parse the HTML content
-	parsedData, err := cm.crawler.parser.Parse(response.HTML, job.URL)
+	parseStart := time.Now()
+	parsedData, err := cm.parser.Parse(response.HTML, job.URL)
 	if err != nil {
-		return nil, err
+		return nil, NewCrawlError("parse_error", fmt.Sprintf("Failed to parse HTML: %v", err), job.URL, err)
 	}
+	parseDuration := time.Since(parseStart)
+	job.attempt.logf("Parsed page: title=%s, internal_links=%d, external_links=%d in %v",
+		formatOptionalString(parsedData.PageTitle), len(parsedData.InternalLinks), len(parsedData.ExternalLinks),
+		parseDuration.Round(time.Millisecond))
+
+	return &crawlOutput{
+		Response:      response,
+		Data:          parsedData,
+		ParseDuration: parseDuration,
+	}, nil
+}
 
-	return parsedData, nil
+// loadCacheValidators returns the ETag/Last-Modified stored by the URL's previous crawl, if any
+func (cm *CrawlManager) loadCacheValidators(urlID uint) *CacheValidators {
+	var previous models.CrawlResult
+	if err := database.DB.Where("url_id = ?", urlID).First(&previous).Error; err != nil {
+		return nil
+	}
+
+	validators := &CacheValidators{}
+	if previous.ETag != nil {
+		validators.ETag = *previous.ETag
+	}
+	if previous.LastModified != nil {
+		validators.LastModified = *previous.LastModified
+	}
+
+	if validators.IsEmpty() {
+		return nil
+	}
+	return validators
 }
 
 // handleCrawlSuccess processes successful crawl results
-func (cm *CrawlManager) handleCrawlSuccess(job *CrawlJob, data *ParsedData, duration time.Duration) {
-	log.Printf("Crawl successful for URL ID=%d, duration=%v", job.URLID, duration)
+func (cm *CrawlManager) handleCrawlSuccess(job *CrawlJob, output *crawlOutput, duration time.Duration) {
+	if output.Response.NotModified {
+		cm.handleNotModified(job, output, duration)
+		return
+	}
+
+	job.attempt.logf("Crawl successful for URL ID=%d, duration=%v", job.URLID, duration)
+
+	// Store the raw body before opening the transaction (blob writes are idempotent)
+	if cm.snapshots != nil {
+		snapshot, err := cm.snapshots.StoreBody(job.URLID, output.Response)
+		if err != nil {
+			// Snapshots are an archive; losing one should not fail the crawl
+			job.attempt.logf("Failed to store snapshot for URL ID=%d: %v", job.URLID, err)
+		}
+		output.Snapshot = snapshot
+	}
 
 	// Start database transaction
 	tx := database.DB.Begin()
@@ -178,78 +620,396 @@ func (cm *CrawlManager) handleCrawlSuccess(job *CrawlJob, data *ParsedData, dura
 		if r := recover(); r != nil {
 			tx.Rollback()
 			log.Printf("Transaction panic for URL ID=%d: %v", job.URLID, r)
+			panic(r) // Let runJob fail the job
 		}
 	}()
 
+	// Save the snapshot record
+	if output.Snapshot != nil {
+		if err := tx.Create(output.Snapshot).Error; err != nil {
+			tx.Rollback()
+			job.attempt.logf("Failed to save page snapshot for URL ID=%d: %v", job.URLID, err)
+			cm.failJob(job, "database_error", err)
+			return
+		}
+	}
+
 	// Save crawl results
-	if err := cm.saveCrawlResults(tx, job.URLID, data, duration); err != nil {
+	if err := cm.saveCrawlResults(tx, job.URLID, output, duration); err != nil {
 		tx.Rollback()
-		log.Printf("Failed to save crawl results for URL ID=%d: %v", job.URLID, err)
-		errorMsg := err.Error()
-		cm.updateURLStatus(job.URLID, models.StatusError, &errorMsg)
+		job.attempt.logf("Failed to save crawl results for URL ID=%d: %v", job.URLID, err)
+		cm.failJob(job, "database_error", err)
 		return
 	}
 
-	// Save found links
-	if err := cm.saveFoundLinks(tx, job.URLID, data); err != nil {
+	// Save found links and images
+	if err := cm.savePageDetails(tx, job.URLID, output.Data); err != nil {
 		tx.Rollback()
-		log.Printf("Failed to save found links for URL ID=%d: %v", job.URLID, err)
-		errorMsg := err.Error()
-		cm.updateURLStatus(job.URLID, models.StatusError, &errorMsg)
+		job.attempt.logf("Failed to save page details for URL ID=%d: %v", job.URLID, err)
+		cm.failJob(job, "database_error", err)
 		return
 	}
+	job.attempt.logf("Saved crawl result, found links and images for URL ID=%d (%d internal, %d external, %d images)",
+		job.URLID, len(output.Data.InternalLinks), len(output.Data.ExternalLinks), len(output.Data.Images))
 
 	// Update URL status to completed
 	if err := cm.updateURLStatusTx(tx, job.URLID, models.StatusCompleted, nil); err != nil {
 		tx.Rollback()
-		log.Printf("Failed to update URL status to completed for ID=%d: %v", job.URLID, err)
+		cm.handleStatusUpdateError(job, err)
 		return
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		log.Printf("Failed to commit transaction for URL ID=%d: %v", job.URLID, err)
-		errorMsg := err.Error()
-		cm.updateURLStatus(job.URLID, models.StatusError, &errorMsg)
+		job.attempt.logf("Failed to commit transaction for URL ID=%d: %v", job.URLID, err)
+		cm.failJob(job, "database_error", err)
 		return
 	}
 
-	log.Printf("Crawl completed successfully for URL ID=%d", job.URLID)
+	job.attempt.logf("Crawl completed successfully for URL ID=%d", job.URLID)
+	job.attempt.finish(models.AttemptCompleted, "", nil)
+
+	// Now that the snapshot is committed, make sure its blob survived any URL
+	// deletion since StoreBody, and prune old snapshots
+	if output.Snapshot != nil {
+		if err := cm.snapshots.RestoreBody(output.Snapshot, output.Response); err != nil {
+			log.Printf("Failed to restore snapshot blob for URL ID=%d: %v", job.URLID, err)
+		}
+		if err := cm.snapshots.ApplyRetention(job.URLID); err != nil {
+			log.Printf("Failed to apply snapshot retention for URL ID=%d: %v", job.URLID, err)
+		}
+	}
 }
 
-// handleCrawlFailure processes failed crawl attempts
+// handleNotModified records a cheap check for a page that answered 304 Not Modified.
+// The previous crawl result and found links stay in place.
+func (cm *CrawlManager) handleNotModified(job *CrawlJob, output *crawlOutput, duration time.Duration) {
+	job.attempt.logf("URL ID=%d not modified since last crawl, duration=%v", job.URLID, duration)
+
+	tx := database.DB.Begin()
+
+	var crawlResult models.CrawlResult
+	if err := tx.Where("url_id = ?", job.URLID).First(&crawlResult).Error; err != nil {
+		tx.Rollback()
+		job.attempt.logf("Failed to load previous crawl result for URL ID=%d: %v", job.URLID, err)
+		cm.failJob(job, "database_error",
+			fmt.Errorf("page not modified but previous crawl result is missing: %w", err))
+		return
+	}
+
+	now := time.Now()
+	crawlResult.LastCheckedAt = &now
+	crawlResult.NotModifiedChecks++
+	crawlResult.ChangeStatus = models.ChangeUnchanged
+
+	// Certificates can be renewed without the page changing
+	applyTLSDetails(&crawlResult, output.Response.TLS)
+
+	// A 304 may carry updated validators (RFC 9110 section 15.4.5)
+	if output.Response.ETag != "" {
+		crawlResult.ETag = cacheValidator(output.Response.ETag, maxETagLength)
+	}
+	if output.Response.LastModified != "" {
+		crawlResult.LastModified = cacheValidator(output.Response.LastModified, maxLastModifiedLength)
+	}
+
+	if err := tx.Save(&crawlResult).Error; err != nil {
+		tx.Rollback()
+		job.attempt.logf("Failed to record not-modified check for URL ID=%d: %v", job.URLID, err)
+		cm.failJob(job, "database_error", err)
+		return
+	}
+
+	if err := cm.updateURLStatusTx(tx, job.URLID, models.StatusCompleted, nil); err != nil {
+		tx.Rollback()
+		cm.handleStatusUpdateError(job, err)
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		job.attempt.logf("Failed to commit transaction for URL ID=%d: %v", job.URLID, err)
+		cm.failJob(job, "database_error", err)
+		return
+	}
+
+	job.attempt.logf("Recorded not-modified check for URL ID=%d", job.URLID)
+	job.attempt.finish(models.AttemptNotModified, "", nil)
+}
+
+// handleCrawlFailure processes failed crawl attempts. Transient failures are
+// retried according to the retry policy; the URL only moves to "error" once
+// the retries are exhausted.
 func (cm *CrawlManager) handleCrawlFailure(job *CrawlJob, err error, duration time.Duration) {
-	log.Printf("Crawl failed for URL ID=%d: %v (duration=%v)", job.URLID, err, duration)
+	job.attempt.logf("Crawl failed for URL ID=%d (attempt %d): %v (duration=%v)", job.URLID, job.Attempt, err, duration)
 
-	errorMsg := err.Error()
-	if updateErr := cm.updateURLStatus(job.URLID, models.StatusError, &errorMsg); updateErr != nil {
-		log.Printf("Failed to update URL status to error for ID=%d: %v", job.URLID, updateErr)
+	if delay, retry := cm.retryPolicy.NextRetry(err, job.Attempt); retry {
+		if !cm.scheduleRetry(job, err, delay) {
+			job.attempt.finish(models.AttemptAbandoned, "lease_lost", errLeaseLost)
+			return
+		}
+		job.attempt.finish(models.AttemptRetrying, crawlErrorType(err), err)
+		return
 	}
+
+	cm.failJob(job, crawlErrorType(err), err)
 }
 
-// updateURLStatus updates the status of a URL in the database
-func (cm *CrawlManager) updateURLStatus(urlID uint, status models.URLStatus, errorMsg *string) error {
-	return cm.updateURLStatusTx(database.DB, urlID, status, errorMsg)
+// scheduleRetry requeues a failed job once its backoff delay has passed.
+// The URL stays "queued" with the last error and the retry time recorded.
+// It returns false if the lease was lost, as the reaper then owns the retry.
+func (cm *CrawlManager) scheduleRetry(job *CrawlJob, err error, delay time.Duration) bool {
+	nextRetryAt := time.Now().Add(delay)
+	updates := map[string]interface{}{
+		"status":           models.StatusQueued,
+		"error_message":    err.Error(),
+		"error_type":       crawlErrorType(err),
+		"retry_count":      job.Attempt,
+		"next_retry_at":    nextRetryAt,
+		"worker_id":        nil,
+		"crawl_started_at": nil,
+		"updated_at":       time.Now(),
+	}
+	result := cm.leasedURL(database.DB, job.URLID).Updates(updates)
+	if result.Error != nil {
+		job.attempt.logf("Failed to record retry for URL ID=%d: %v", job.URLID, result.Error)
+	} else if result.RowsAffected == 0 {
+		// The reaper already requeued or failed the URL
+		job.attempt.logf("Not retrying URL ID=%d: %v", job.URLID, errLeaseLost)
+		return false
+	}
+
+	retryJob := &CrawlJob{
+		URLID:    job.URLID,
+		URL:      job.URL,
+		Attempt:  job.Attempt + 1,
+		Priority: job.Priority,
+	}
+
+	cm.retryMu.Lock()
+	if previous, exists := cm.retries[job.URLID]; exists {
+		previous.timer.Stop()
+	}
+	cm.retries[job.URLID] = &pendingRetry{
+		job:   retryJob,
+		timer: time.AfterFunc(delay, func() { cm.runRetry(retryJob) }),
+	}
+	cm.retryMu.Unlock()
+
+	job.attempt.logf("Retrying URL ID=%d in %v (attempt %d)", job.URLID, delay.Round(time.Millisecond), retryJob.Attempt)
+	return true
+}
+
+// runRetry moves a retry whose delay has passed onto the queue
+func (cm *CrawlManager) runRetry(job *CrawlJob) {
+	cm.retryMu.Lock()
+	retry, exists := cm.retries[job.URLID]
+	if !exists || retry.job != job {
+		// Cancelled, or replaced by a newer crawl of the same URL
+		cm.retryMu.Unlock()
+		return
+	}
+	delete(cm.retries, job.URLID)
+	cm.retryMu.Unlock()
+
+	if !cm.isRunning {
+		return
+	}
+
+	claimed, err := claimRetry(job.URLID)
+	if err != nil {
+		log.Printf("Failed to claim retry of URL ID=%d: %v", job.URLID, err)
+	} else if !claimed {
+		// A sweep already queued it
+		return
+	}
+
+	job.QueuedAt = time.Now()
+	if err := cm.enqueue(job); err != nil {
+		cm.markURLFailed(job.URLID, "queue_error", fmt.Errorf("retry could not be queued: %w", err))
+	}
 }
 
-// updateURLStatusTx updates URL status within a transaction
+// RequeueDueRetries queues URLs whose retry is due but that no timer will pick
+// up, such as retries scheduled by a process that has since restarted.
+// Requeued retries keep their attempt number and run at low priority.
+// It returns the number of URLs queued.
+func (cm *CrawlManager) RequeueDueRetries() (int, error) {
+	if !cm.isRunning {
+		return 0, nil
+	}
+
+	var due []models.URL
+	err := database.DB.Select("id", "url", "retry_count").
+		Where("status = ? AND next_retry_at <= ?", models.StatusQueued, time.Now()).
+		Order("next_retry_at").
+		Find(&due).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due retries: %w", err)
+	}
+
+	queued := 0
+	for _, url := range due {
+		claimed, err := claimRetry(url.ID)
+		if err != nil {
+			return queued, err
+		}
+		if !claimed {
+			continue
+		}
+		cm.cancelRetry(url.ID)
+
+		job := &CrawlJob{
+			URLID:    url.ID,
+			URL:      url.URL,
+			QueuedAt: time.Now(),
+			Attempt:  url.RetryCount + 1,
+			Priority: models.PriorityLow,
+		}
+		if err := cm.enqueue(job); err != nil {
+			cm.markURLFailed(url.ID, "queue_error", fmt.Errorf("retry could not be queued: %w", err))
+			continue
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// claimRetry clears a queued URL's retry time so a due retry is queued once,
+// whether its timer fires or a sweep finds it first. It returns false if the
+// retry was already claimed.
+func claimRetry(urlID uint) (bool, error) {
+	result := database.DB.Model(&models.URL{}).
+		Where("id = ? AND status = ? AND next_retry_at IS NOT NULL", urlID, models.StatusQueued).
+		Update("next_retry_at", nil)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim retry: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// cancelRetry drops a URL's pending retry, if any
+func (cm *CrawlManager) cancelRetry(urlID uint) {
+	cm.retryMu.Lock()
+	defer cm.retryMu.Unlock()
+
+	if retry, exists := cm.retries[urlID]; exists {
+		retry.timer.Stop()
+		delete(cm.retries, urlID)
+	}
+}
+
+// pendingRetryCount returns the number of failed jobs waiting to be retried
+func (cm *CrawlManager) pendingRetryCount() int {
+	cm.retryMu.Lock()
+	defer cm.retryMu.Unlock()
+	return len(cm.retries)
+}
+
+// errLeaseLost means the stale crawl reaper took a URL away from this worker mid-crawl
+var errLeaseLost = errors.New("lease lost: the crawl was recovered as stale")
+
+// leasedURL selects a URL only while this worker holds its lease, so a slow
+// worker cannot overwrite the state of a URL the reaper requeued or failed
+func (cm *CrawlManager) leasedURL(db *gorm.DB, urlID uint) *gorm.DB {
+	return db.Model(&models.URL{}).
+		Where("id = ? AND status = ? AND worker_id = ?", urlID, models.StatusRunning, cm.workerID)
+}
+
+// updateURLStatusTx updates the status of a URL leased by this worker within a
+// transaction. It returns errLeaseLost if the lease was taken away.
 func (cm *CrawlManager) updateURLStatusTx(tx *gorm.DB, urlID uint, status models.URLStatus, errorMsg *string) error {
 	updates := map[string]interface{}{
-		"status":     status,
-		"updated_at": time.Now(),
+		"status":           status,
+		"next_retry_at":    nil,
+		"worker_id":        nil,
+		"crawl_started_at": nil,
+		"updated_at":       time.Now(),
 	}
 
 	if errorMsg != nil {
 		updates["error_message"] = *errorMsg
 	} else {
 		updates["error_message"] = nil
+		updates["error_type"] = nil
+	}
+
+	result := cm.leasedURL(tx, urlID).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errLeaseLost
+	}
+	return nil
+}
+
+// handleStatusUpdateError ends a job whose final status could not be written.
+// After a lost lease the result is dropped; the URL belongs to a newer crawl.
+func (cm *CrawlManager) handleStatusUpdateError(job *CrawlJob, err error) {
+	if errors.Is(err, errLeaseLost) {
+		job.attempt.logf("Discarding crawl result for URL ID=%d: %v", job.URLID, err)
+		job.attempt.finish(models.AttemptAbandoned, "lease_lost", err)
+		return
 	}
 
-	return tx.Model(&models.URL{}).Where("id = ?", urlID).Updates(updates).Error
+	job.attempt.logf("Failed to update URL status to completed for ID=%d: %v", job.URLID, err)
+	cm.failJob(job, "database_error", err)
+}
+
+// failedURLUpdates moves a URL to "error", recording the message and error type
+func failedURLUpdates(errorType string, err error) map[string]interface{} {
+	return map[string]interface{}{
+		"status":           models.StatusError,
+		"error_message":    err.Error(),
+		"error_type":       errorType,
+		"next_retry_at":    nil,
+		"worker_id":        nil,
+		"crawl_started_at": nil,
+		"updated_at":       time.Now(),
+	}
+}
+
+// markURLFailed moves a URL that no worker holds, such as a retry that could not be queued, to "error"
+func (cm *CrawlManager) markURLFailed(urlID uint, errorType string, err error) {
+	if updateErr := database.DB.Model(&models.URL{}).Where("id = ?", urlID).Updates(failedURLUpdates(errorType, err)).Error; updateErr != nil {
+		log.Printf("Failed to update URL status to error for ID=%d: %v", urlID, updateErr)
+	}
+}
+
+// failJob closes the attempt of a job and marks its URL as failed, unless the
+// lease was lost. The attempt is saved first so it is complete once the URL shows the error.
+func (cm *CrawlManager) failJob(job *CrawlJob, errorType string, err error) {
+	job.attempt.finish(models.AttemptFailed, errorType, err)
+
+	result := cm.leasedURL(database.DB, job.URLID).Updates(failedURLUpdates(errorType, err))
+	switch {
+	case result.Error != nil:
+		log.Printf("Failed to update URL status to error for ID=%d: %v", job.URLID, result.Error)
+	case result.RowsAffected == 0:
+		log.Printf("Not marking URL ID=%d failed: %v", job.URLID, errLeaseLost)
+	}
+}
+
+// crawlErrorType returns the classification of a crawl failure
+func crawlErrorType(err error) string {
+	var crawlErr *CrawlError
+	if errors.As(err, &crawlErr) {
+		return crawlErr.Type
+	}
+	return "network"
 }
 
 // saveCrawlResults saves the parsed HTML data to the crawl_results table
-func (cm *CrawlManager) saveCrawlResults(tx *gorm.DB, urlID uint, data *ParsedData, duration time.Duration) error {
+func (cm *CrawlManager) saveCrawlResults(tx *gorm.DB, urlID uint, output *crawlOutput, duration time.Duration) error {
+	data := output.Data
+
+	// Load the previous crawl result to detect content changes
+	var previous *models.CrawlResult
+	var existing models.CrawlResult
+	if err := tx.Where("url_id = ?", urlID).First(&existing).Error; err == nil {
+		previous = &existing
+	}
+
 	// First, delete any existing crawl result for this URL (re-crawl scenario)
 	if err := tx.Where("url_id = ?", urlID).Delete(&models.CrawlResult{}).Error; err != nil {
 		return fmt.Errorf("failed to delete existing crawl results: %w", err)
@@ -259,40 +1019,55 @@ func (cm *CrawlManager) saveCrawlResults(tx *gorm.DB, urlID uint, data *ParsedDa
 	durationMs := int(duration.Milliseconds())
 	crawlResult := models.CrawlResult{
 		URLID:           urlID,
-		HTMLVersion:     data.HTMLVersion,
-		PageTitle:       data.PageTitle,
-		HasLoginForm:    data.HasLoginForm,
 		CrawledAt:       time.Now(),
 		CrawlDurationMs: &durationMs,
 	}
 
-	// Set heading counts
-	if h1Count, exists := data.HeadingCounts["h1"]; exists {
-		crawlResult.H1Count = h1Count
-	}
-	if h2Count, exists := data.HeadingCounts["h2"]; exists {
-		crawlResult.H2Count = h2Count
-	}
-	if h3Count, exists := data.HeadingCounts["h3"]; exists {
-		crawlResult.H3Count = h3Count
-	}
-	if h4Count, exists := data.HeadingCounts["h4"]; exists {
-		crawlResult.H4Count = h4Count
-	}
-	if h5Count, exists := data.HeadingCounts["h5"]; exists {
-		crawlResult.H5Count = h5Count
+	// Set timing breakdown
+	timing := output.Response.Timing
+	crawlResult.DNSLookupMs = optionalMs(timing.DNSLookup)
+	crawlResult.TCPConnectMs = optionalMs(timing.TCPConnect)
+	crawlResult.TLSHandshakeMs = optionalMs(timing.TLSHandshake)
+	crawlResult.TimeToFirstByteMs = optionalMs(timing.TimeToFirstByte)
+	crawlResult.DownloadMs = optionalMs(timing.Download)
+	crawlResult.FetchDurationMs = optionalMs(output.Response.Duration)
+	parseMs := int(output.ParseDuration.Milliseconds())
+	crawlResult.ParseDurationMs = &parseMs
+
+	// Set TLS certificate details for HTTPS pages
+	applyTLSDetails(&crawlResult, output.Response.TLS)
+
+	// Keep validators for the next conditional re-crawl
+	crawlResult.ETag = cacheValidator(output.Response.ETag, maxETagLength)
+	crawlResult.LastModified = cacheValidator(output.Response.LastModified, maxLastModifiedLength)
+	crawlResult.LastCheckedAt = &crawlResult.CrawledAt
+
+	if output.Snapshot != nil {
+		crawlResult.SnapshotID = &output.Snapshot.ID
 	}
-	if h6Count, exists := data.HeadingCounts["h6"]; exists {
-		crawlResult.H6Count = h6Count
+
+	// Fingerprint the content and compare it with the previous crawl
+	hashes := HashContent(output.Response.HTML, data.TextContent)
+	crawlResult.ContentHash = &hashes.BodyHash
+	crawlResult.TextHash = &hashes.TextHash
+	crawlResult.ChangeStatus = DetectChange(previous, hashes)
+	switch {
+	case crawlResult.ChangeStatus == models.ChangeNew || crawlResult.ChangeStatus == models.ChangeChanged:
+		crawlResult.ContentChangedAt = &crawlResult.CrawledAt
+	case previous != nil:
+		crawlResult.ContentChangedAt = previous.ContentChangedAt
 	}
 
-	// Set link counts
-	crawlResult.InternalLinksCount = len(data.InternalLinks)
-	crawlResult.ExternalLinksCount = len(data.ExternalLinks)
+	// Store response headers and grade their security headers
+	crawlResult.ResponseHeaders = storedResponseHeaders(output.Response.Headers)
+	report := AuditSecurityHeaders(output.Response.Headers, output.Response.TLS != nil)
+	crawlResult.SecurityHeadersScore = &report.Score
+	crawlResult.SecurityHeadersGrade = &report.Grade
+	crawlResult.SecurityHeaderFindings = report.Findings
 
-	// For now, set inaccessible links count to 0
-	// We'll implement link checking as a future enhancement
-	crawlResult.InaccessibleLinksCount = 0
+	// Set parser-derived fields
+	applyParsedData(&crawlResult, data)
+	cm.applyLinkLimit(&crawlResult, data)
 
 	// Save the crawl result
 	if err := tx.Create(&crawlResult).Error; err != nil {
@@ -308,80 +1083,246 @@ func (cm *CrawlManager) saveCrawlResults(tx *gorm.DB, urlID uint, data *ParsedDa
 	return nil
 }
 
-// saveFoundLinks saves all discovered links to the found_links table
+// linkInsertBatchSize is the number of found links written per INSERT
+const linkInsertBatchSize = 100
+
+// linkLimit returns how many of a page's links are saved, and whether some are dropped
+func (cm *CrawlManager) linkLimit(data *ParsedData) (int, bool) {
+	total := len(data.InternalLinks) + len(data.ExternalLinks)
+	if cm.maxLinks > 0 && total > cm.maxLinks {
+		return cm.maxLinks, true
+	}
+	return total, false
+}
+
+// applyLinkLimit records on a crawl result how many links saveFoundLinks stores
+func (cm *CrawlManager) applyLinkLimit(crawlResult *models.CrawlResult, data *ParsedData) {
+	crawlResult.SavedLinksCount, crawlResult.LinksTruncated = cm.linkLimit(data)
+}
+
+// saveFoundLinks saves the discovered links to the found_links table, internal links
+// first, up to the configured per-page limit. Links are inserted in batches as they
+// are built rather than collected up front.
 func (cm *CrawlManager) saveFoundLinks(tx *gorm.DB, urlID uint, data *ParsedData) error {
 	// First, delete any existing found links for this URL (re-crawl scenario)
 	if err := tx.Where("url_id = ?", urlID).Delete(&models.FoundLink{}).Error; err != nil {
 		return fmt.Errorf("failed to delete existing found links: %w", err)
 	}
 
-	// Combine internal and external links
-	allLinks := make([]models.FoundLink, 0, len(data.InternalLinks)+len(data.ExternalLinks))
+	limit, truncated := cm.linkLimit(data)
+	if truncated {
+		log.Printf("Limiting found links from %d to %d for URL ID=%d",
+			len(data.InternalLinks)+len(data.ExternalLinks), limit, urlID)
+	}
 
-	// Process internal links
-	for _, link := range data.InternalLinks {
-		foundLink := models.FoundLink{
-			URLID:      urlID,
-			LinkURL:    cm.normalizeURL(link.URL),
-			LinkText:   cm.normalizeText(link.Text),
-			IsInternal: true,
-			CreatedAt:  time.Now(),
+	saved := 0
+	batch := make([]models.FoundLink, 0, min(limit, linkInsertBatchSize))
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
 		}
+		if err := tx.CreateInBatches(&batch, linkInsertBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create found links %d-%d: %w", saved, saved+len(batch)-1, err)
+		}
+		saved += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	groups := []struct {
+		links      []LinkInfo
+		isInternal bool
+	}{
+		{data.InternalLinks, true},
+		{data.ExternalLinks, false},
+	}
+	for _, group := range groups {
+		for _, link := range group.links {
+			if saved+len(batch) >= limit {
+				break
+			}
+
+			// Accessibility is not checked yet, so IsAccessible, StatusCode and ErrorMessage stay nil
+			batch = append(batch, models.FoundLink{
+				URLID:       urlID,
+				LinkURL:     cm.normalizeURL(link.URL),
+				LinkText:    cm.normalizeText(link.Text),
+				IsInternal:  group.isInternal,
+				IsImage:     link.IsImage,
+				Occurrences: max(link.Occurrences, 1),
+				AnchorTexts: cm.normalizeTexts(link.Texts),
+				Region:      optionalString(link.Region),
+				Rel:         optionalString(strings.Join(link.Rel, " ")),
+				Target:      optionalString(truncateString(link.Target, 50)),
+				Title:       cm.normalizeText(link.Title),
+				CreatedAt:   time.Now(),
+			})
+			if len(batch) == linkInsertBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
 
-		// For now, we don't check accessibility - mark as nil (unchecked)
-		// This will be implemented as a future enhancement
-		foundLink.IsAccessible = nil
-		foundLink.StatusCode = nil
-		foundLink.ErrorMessage = nil
+	log.Printf("Saved %d found links for URL ID=%d (%d internal, %d external)",
+		saved, urlID, len(data.InternalLinks), len(data.ExternalLinks))
+
+	return nil
+}
+
+// maxSavedImages bounds the page_images rows stored per page; the summary on
+// the crawl result still counts every image
+const maxSavedImages = 500
 
-		allLinks = append(allLinks, foundLink)
+// saveImages saves the page's image inventory to the page_images table
+func (cm *CrawlManager) saveImages(tx *gorm.DB, urlID uint, data *ParsedData) error {
+	// First, delete any existing images for this URL (re-crawl scenario)
+	if err := tx.Where("url_id = ?", urlID).Delete(&models.PageImage{}).Error; err != nil {
+		return fmt.Errorf("failed to delete existing page images: %w", err)
 	}
 
-	// Process external links
-	for _, link := range data.ExternalLinks {
-		foundLink := models.FoundLink{
-			URLID:      urlID,
-			LinkURL:    cm.normalizeURL(link.URL),
-			LinkText:   cm.normalizeText(link.Text),
-			IsInternal: false,
-			CreatedAt:  time.Now(),
+	count := min(len(data.Images), maxSavedImages)
+	if count == 0 {
+		return nil
+	}
+	if count < len(data.Images) {
+		log.Printf("Limiting page images from %d to %d for URL ID=%d", len(data.Images), count, urlID)
+	}
+
+	images := make([]models.PageImage, count)
+	for i, image := range data.Images[:count] {
+		srcset := make([]models.ImageCandidate, len(image.Srcset))
+		for j, candidate := range image.Srcset {
+			srcset[j] = models.ImageCandidate{URL: cm.normalizeURL(candidate.URL), Descriptor: candidate.Descriptor}
 		}
 
-		// For now, we don't check accessibility - mark as nil (unchecked)
-		foundLink.IsAccessible = nil
-		foundLink.StatusCode = nil
-		foundLink.ErrorMessage = nil
+		images[i] = models.PageImage{
+			URLID:     urlID,
+			Position:  i,
+			Src:       optionalString(cm.normalizeURL(image.Src)),
+			Srcset:    srcset,
+			InPicture: image.InPicture,
+			AltStatus: image.AltStatus,
+			Width:     image.Width,
+			Height:    image.Height,
+			Lazy:      image.Lazy,
+			CreatedAt: time.Now(),
+		}
+		if image.HasAlt {
+			// An empty alt is meaningful, so it is stored as "" rather than NULL
+			alt := truncateString(image.Alt, 500)
+			images[i].Alt = &alt
+		}
+	}
 
-		allLinks = append(allLinks, foundLink)
+	if err := tx.CreateInBatches(&images, linkInsertBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to create page images: %w", err)
 	}
+	return nil
+}
 
-	// Batch insert links (limit to prevent huge inserts)
-	const maxLinksToSave = 200 // Reasonable limit for demo
-	if len(allLinks) > maxLinksToSave {
-		log.Printf("Limiting found links from %d to %d for URL ID=%d", len(allLinks), maxLinksToSave, urlID)
-		allLinks = allLinks[:maxLinksToSave]
+// saveStructuredData saves the page's JSON-LD, microdata and RDFa items to the structured_data table
+func (cm *CrawlManager) saveStructuredData(tx *gorm.DB, urlID uint, data *ParsedData) error {
+	// First, delete any existing items for this URL (re-crawl scenario)
+	if err := tx.Where("url_id = ?", urlID).Delete(&models.StructuredData{}).Error; err != nil {
+		return fmt.Errorf("failed to delete existing structured data: %w", err)
 	}
 
-	// Save links in batches to avoid large transaction
-	const batchSize = 50
-	for i := 0; i < len(allLinks); i += batchSize {
-		end := i + batchSize
-		if end > len(allLinks) {
-			end = len(allLinks)
-		}
+	if len(data.StructuredData) == 0 {
+		return nil
+	}
 
-		batch := allLinks[i:end]
-		if err := tx.Create(&batch).Error; err != nil {
-			return fmt.Errorf("failed to create found links batch %d-%d: %w", i, end-1, err)
+	items := make([]models.StructuredData, len(data.StructuredData))
+	for i, item := range data.StructuredData {
+		items[i] = models.StructuredData{
+			URLID:     urlID,
+			Position:  i,
+			Format:    item.Format,
+			Types:     item.Types,
+			Payload:   item.Payload,
+			Valid:     item.Error == "",
+			Truncated: item.Truncated,
+			Error:     optionalString(item.Error),
+			CreatedAt: time.Now(),
 		}
 	}
 
-	log.Printf("Saved %d found links for URL ID=%d (%d internal, %d external)",
-		len(allLinks), urlID, len(data.InternalLinks), len(data.ExternalLinks))
+	// Payloads are bounded per page by maxStructuredDataTotal, but insert a few
+	// at a time to keep each statement well under max_allowed_packet
+	if err := tx.CreateInBatches(&items, 10).Error; err != nil {
+		return fmt.Errorf("failed to create structured data: %w", err)
+	}
+	return nil
+}
+
+// saveForms saves the page's forms inventory to the page_forms table
+func (cm *CrawlManager) saveForms(tx *gorm.DB, urlID uint, data *ParsedData) error {
+	// First, delete any existing forms for this URL (re-crawl scenario)
+	if err := tx.Where("url_id = ?", urlID).Delete(&models.PageForm{}).Error; err != nil {
+		return fmt.Errorf("failed to delete existing page forms: %w", err)
+	}
+
+	if len(data.Forms) == 0 {
+		return nil
+	}
+
+	forms := make([]models.PageForm, len(data.Forms))
+	for i, form := range data.Forms {
+		forms[i] = models.PageForm{
+			URLID:           urlID,
+			Position:        i,
+			Action:          optionalString(form.Action),
+			Method:          optionalString(form.Method),
+			Standalone:      form.Standalone,
+			Fields:          form.Fields,
+			HasCSRFToken:    form.HasCSRFToken,
+			InsecureAction:  form.InsecureAction,
+			LoginConfidence: form.LoginConfidence,
+			LoginSignals:    form.LoginSignals,
+			CreatedAt:       time.Now(),
+		}
+	}
 
+	if err := tx.CreateInBatches(&forms, linkInsertBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to create page forms: %w", err)
+	}
 	return nil
 }
 
+// savePageDetails replaces the rows derived from a page's parsed data: found links,
+// images, structured data and forms. It is shared by live crawls and snapshot re-parsing.
+func (cm *CrawlManager) savePageDetails(tx *gorm.DB, urlID uint, data *ParsedData) error {
+	if err := cm.saveFoundLinks(tx, urlID, data); err != nil {
+		return err
+	}
+	if err := cm.saveImages(tx, urlID, data); err != nil {
+		return err
+	}
+	if err := cm.saveStructuredData(tx, urlID, data); err != nil {
+		return err
+	}
+	return cm.saveForms(tx, urlID, data)
+}
+
+// normalizeTexts cleans anchor texts, dropping empty ones and duplicates
+func (cm *CrawlManager) normalizeTexts(texts []string) []string {
+	cleaned := make([]string, 0, len(texts))
+	seen := make(map[string]bool, len(texts))
+	for _, text := range texts {
+		normalized := cm.normalizeText(text)
+		if normalized == nil || seen[*normalized] {
+			continue
+		}
+		seen[*normalized] = true
+		cleaned = append(cleaned, *normalized)
+	}
+	return cleaned
+}
+
 // normalizeURL cleans and validates a URL string
 func (cm *CrawlManager) normalizeURL(rawURL string) string {
 	// Trim whitespace
@@ -389,11 +1330,7 @@ func (cm *CrawlManager) normalizeURL(rawURL string) string {
 
 	// Limit URL length to prevent database issues
 	const maxURLLength = 2000
-	if len(url) > maxURLLength {
-		url = url[:maxURLLength]
-	}
-
-	return url
+	return truncateString(url, maxURLLength)
 }
 
 // normalizeText cleans and validates link text
@@ -413,9 +1350,7 @@ func (cm *CrawlManager) normalizeText(text string) *string {
 
 	// Limit text length
 	const maxTextLength = 500
-	if len(cleaned) > maxTextLength {
-		cleaned = cleaned[:maxTextLength]
-	}
+	cleaned = truncateString(cleaned, maxTextLength)
 
 	// Return nil for empty text
 	if cleaned == "" {
@@ -425,6 +1360,153 @@ func (cm *CrawlManager) normalizeText(text string) *string {
 	return &cleaned
 }
 
+// parsedDataColumns lists the crawl_results columns written by applyParsedData
+var parsedDataColumns = []string{
+	"html_version", "page_title",
+	"meta_description", "meta_robots", "robots_noindex", "robots_nofollow",
+	"canonical_url", "viewport", "theme_color", "open_graph", "twitter_tags",
+	"h1_count", "h2_count", "h3_count", "h4_count", "h5_count", "h6_count", "headings", "heading_issues_count",
+	"internal_links_count", "external_links_count", "inaccessible_links_count",
+	"structured_data_count", "structured_data_error_count", "schema_types",
+	"forms_count", "insecure_forms_count", "login_confidence", "login_signals",
+	"images_count", "images_missing_alt_count", "images_empty_alt_count", "images_poor_alt_count",
+	"images_missing_dimensions_count", "images_lazy_count",
+}
+
+// applyParsedData copies the fields derived from parsing the HTML onto a crawl result.
+// It is shared by live crawls and snapshot re-parsing, so it must not touch fetch data.
+// Keep parsedDataColumns in sync when adding fields here.
+func applyParsedData(crawlResult *models.CrawlResult, data *ParsedData) {
+	crawlResult.HTMLVersion = data.HTMLVersion
+	crawlResult.PageTitle = data.PageTitle
+
+	// Set meta tags (truncated to their column sizes)
+	meta := data.Meta
+	crawlResult.MetaDescription = optionalString(meta.Description)
+	crawlResult.MetaRobots = optionalString(truncateString(meta.Robots, 255))
+	crawlResult.RobotsNoindex = meta.NoIndex()
+	crawlResult.RobotsNofollow = meta.NoFollow()
+	crawlResult.CanonicalURL = optionalString(truncateString(meta.Canonical, 2000))
+	crawlResult.Viewport = optionalString(truncateString(meta.Viewport, 255))
+	crawlResult.ThemeColor = optionalString(truncateString(meta.ThemeColor, 50))
+	crawlResult.OpenGraph = meta.OpenGraph
+	crawlResult.TwitterTags = meta.Twitter
+
+	// Set heading counts (missing levels reset to zero when re-parsing)
+	crawlResult.H1Count = data.HeadingCounts["h1"]
+	crawlResult.H2Count = data.HeadingCounts["h2"]
+	crawlResult.H3Count = data.HeadingCounts["h3"]
+	crawlResult.H4Count = data.HeadingCounts["h4"]
+	crawlResult.H5Count = data.HeadingCounts["h5"]
+	crawlResult.H6Count = data.HeadingCounts["h6"]
+	crawlResult.Headings = data.Headings
+	crawlResult.HeadingIssuesCount = countHeadingIssues(data.Headings)
+
+	// Set link counts
+	crawlResult.InternalLinksCount = len(data.InternalLinks)
+	crawlResult.ExternalLinksCount = len(data.ExternalLinks)
+
+	// For now, set inaccessible links count to 0
+	// We'll implement link checking as a future enhancement
+	crawlResult.InaccessibleLinksCount = 0
+
+	// Set structured data summary
+	invalid := 0
+	for _, item := range data.StructuredData {
+		if item.Error != "" {
+			invalid++
+		}
+	}
+	crawlResult.StructuredDataCount = len(data.StructuredData)
+	crawlResult.StructuredDataErrorCount = invalid
+	crawlResult.SchemaTypes = data.SchemaTypes()
+
+	// Set forms inventory summary
+	insecure := 0
+	for _, form := range data.Forms {
+		if form.InsecureAction {
+			insecure++
+		}
+	}
+	crawlResult.FormsCount = len(data.Forms)
+	crawlResult.InsecureFormsCount = insecure
+	crawlResult.LoginConfidence = data.LoginConfidence
+	crawlResult.LoginSignals = data.LoginSignals
+
+	// Set image inventory summary
+	images := summarizeImages(data.Images)
+	crawlResult.ImagesCount = images.Total
+	crawlResult.ImagesMissingAltCount = images.MissingAlt
+	crawlResult.ImagesEmptyAltCount = images.EmptyAlt
+	crawlResult.ImagesPoorAltCount = images.PoorAlt
+	crawlResult.ImagesMissingDimensionsCount = images.MissingDimensions
+	crawlResult.ImagesLazyCount = images.Lazy
+}
+
+// applyTLSDetails copies TLS connection and certificate details onto a crawl result
+func applyTLSDetails(crawlResult *models.CrawlResult, tlsInfo *TLSInfo) {
+	if tlsInfo == nil {
+		return
+	}
+
+	crawlResult.TLSVersion = &tlsInfo.Version
+	crawlResult.TLSCipherSuite = &tlsInfo.CipherSuite
+	crawlResult.CertChain = tlsInfo.Certificates
+	if leaf := tlsInfo.Leaf(); leaf != nil {
+		crawlResult.CertSubject = &leaf.Subject
+		crawlResult.CertIssuer = &leaf.Issuer
+		crawlResult.CertNotBefore = &leaf.NotBefore
+		crawlResult.CertNotAfter = &leaf.NotAfter
+	}
+}
+
+// Column sizes of the stored cache validators
+const (
+	maxETagLength         = 255
+	maxLastModifiedLength = 100
+)
+
+// cacheValidator returns a validator to store for the next conditional re-crawl.
+// One too long for its column is dropped rather than truncated: a truncated
+// ETag would never match, and the page is simply fetched in full next time.
+func cacheValidator(value string, maxLength int) *string {
+	if len(value) > maxLength {
+		return nil
+	}
+	return optionalString(value)
+}
+
+// optionalString returns nil for empty strings
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// truncateString cuts s to at most maxLength bytes without splitting a
+// multi-byte character, which MySQL would reject as an invalid utf8mb4 string
+func truncateString(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+
+	cut := maxLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+// optionalMs converts a duration to milliseconds, returning nil for phases that did not happen
+func optionalMs(d time.Duration) *int {
+	if d <= 0 {
+		return nil
+	}
+	ms := int(d.Milliseconds())
+	return &ms
+}
+
 // formatOptionalString formats a string pointer for logging
 func formatOptionalString(s *string) string {
 	if s == nil {
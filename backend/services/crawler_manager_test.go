@@ -0,0 +1,625 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"web-crawler/database"
+	"web-crawler/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testDBCount names each test's in-memory database
+var testDBCount atomic.Int64
+
+// setupTestDB points database.DB at a fresh in-memory database with the given
+// models migrated, and closes it when the test ends
+func setupTestDB(t *testing.T, tables ...interface{}) {
+	t.Helper()
+
+	// Workers and the test poll the database concurrently; a named shared-cache
+	// database with a single connection gives them the same data and avoids
+	// "database table is locked" errors
+	dsn := fmt.Sprintf("file:testdb%d?mode=memory&cache=shared", testDBCount.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to access test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+// setupFixtureManager starts a crawl manager that serves pages from the given fixture directory
+func setupFixtureManager(t *testing.T, dir string) *CrawlManager {
+	t.Helper()
+	return setupFixtureManagerWithConfig(t, dir, nil)
+}
+
+// setupFixtureManagerWithConfig is setupFixtureManager with a hook to adjust the manager configuration
+func setupFixtureManagerWithConfig(t *testing.T, dir string, configure func(*ManagerConfig)) *CrawlManager {
+	t.Helper()
+
+	setupTestDB(t, &models.URL{}, &models.CrawlResult{}, &models.FoundLink{}, &models.PageImage{}, &models.StructuredData{}, &models.PageForm{}, &models.PageSnapshot{},
+		&models.CrawlAttempt{}, &models.CrawlWorker{}, &models.CrawlRecovery{}, &models.CrawlBatch{}, &models.CrawlBatchItem{},
+		&models.QueuePause{})
+
+	fetcher, err := NewFixtureFetcher(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	config := DefaultManagerConfig()
+	config.Crawler = &CrawlerConfig{RateLimit: 0}
+	config.Fetcher = fetcher
+	config.RetryPolicy = &RetryPolicy{
+		Rules: map[string]RetryRule{
+			"timeout":    {MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+			"http_error": {MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		},
+	}
+	if configure != nil {
+		configure(config)
+	}
+
+	manager := NewCrawlManagerWithConfig(config)
+	manager.Start()
+	t.Cleanup(manager.Stop)
+	return manager
+}
+
+// crawlAndWait queues a URL and waits for the crawl to finish
+func crawlAndWait(t *testing.T, manager *CrawlManager, url *models.URL) *models.URL {
+	t.Helper()
+
+	// Like the crawl handler, mark the URL queued so a previous result is not mistaken for this one
+	database.DB.Model(&models.URL{}).Where("id = ?", url.ID).Update("status", models.StatusQueued)
+	if err := manager.QueueURL(url.ID, url.URL); err != nil {
+		t.Fatalf("Failed to queue URL: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var current models.URL
+		database.DB.First(&current, url.ID)
+		if current.Status == models.StatusCompleted || current.Status == models.StatusError {
+			return &current
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Crawl of %s did not finish in time", url.URL)
+	return nil
+}
+
+func TestCrawlManager_CrawlsFromFixtures(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "home", `{
+		"url": "https://example.com",
+		"headers": {"Content-Type": "text/html; charset=utf-8", "ETag": "\"v1\""}
+	}`, `<!DOCTYPE html><html><head><title>Fixture Home</title>
+		<meta name="description" content="The fixture home page"><meta name="robots" content="noindex">
+		<meta property="og:image" content="/cover.png">
+		<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Organization", "name": "Fixture"}</script></head><body>
+		<h1>Welcome</h1>
+		<img src="/hero.jpg" width="800" height="400" loading="lazy">
+		<a href="/about">About</a>
+		<footer><a href="https://other.example.org/" rel="nofollow ugc"><img src="/other.png" alt="Elsewhere"></a></footer>
+		<a href="/about#team">Our team</a>
+		<form action="/login" method="post"><input name="user"><input type="password" name="pass"></form>
+	</body></html>`)
+	writeFixture(t, dir, "gone", `{"url": "https://example.com/gone", "status": 410}`, "")
+
+	manager := setupFixtureManager(t, dir)
+
+	url := models.URL{URL: "https://example.com", Status: models.StatusQueued}
+	database.DB.Create(&url)
+
+	crawled := crawlAndWait(t, manager, &url)
+	if crawled.Status != models.StatusCompleted {
+		t.Fatalf("Expected crawl to complete, got %s (%v)", crawled.Status, formatOptionalString(crawled.ErrorMessage))
+	}
+
+	var result models.CrawlResult
+	if err := database.DB.Where("url_id = ?", url.ID).First(&result).Error; err != nil {
+		t.Fatalf("Expected a crawl result: %v", err)
+	}
+	if result.PageTitle == nil || *result.PageTitle != "Fixture Home" {
+		t.Errorf("Expected title 'Fixture Home', got %v", formatOptionalString(result.PageTitle))
+	}
+	preview := result.GetSocialPreview()
+	if !result.RobotsNoindex || preview.Title == nil || *preview.Title != "Fixture Home" ||
+		preview.Image == nil || *preview.Image != "https://example.com/cover.png" {
+		t.Errorf("Unexpected meta tags: noindex=%v preview=%+v", result.RobotsNoindex, preview)
+	}
+	if result.StructuredDataCount != 1 || len(result.SchemaTypes) != 1 || result.SchemaTypes[0] != "Organization" {
+		t.Errorf("Expected one Organization item, got %d %v", result.StructuredDataCount, result.SchemaTypes)
+	}
+	for schema, expected := range map[string]int64{"Organization": 1, "Organizatio": 0, "Product": 0} {
+		var matched int64
+		models.URLFilter{Schema: schema}.Apply(database.DB.Model(&models.URL{})).Count(&matched)
+		if matched != expected {
+			t.Errorf("Schema filter %q: expected %d URLs, got %d", schema, expected, matched)
+		}
+	}
+	if result.H1Count != 1 || result.InternalLinksCount != 1 || result.ExternalLinksCount != 1 {
+		t.Errorf("Unexpected counts: h1=%d internal=%d external=%d",
+			result.H1Count, result.InternalLinksCount, result.ExternalLinksCount)
+	}
+	if len(result.Headings) != 1 || result.Headings[0].Text != "Welcome" || result.HeadingIssuesCount != 0 {
+		t.Errorf("Expected the stored outline to hold the Welcome h1, got %+v (%d issues)", result.Headings, result.HeadingIssuesCount)
+	}
+
+	var links int64
+	database.DB.Model(&models.FoundLink{}).Where("url_id = ?", url.ID).Count(&links)
+	if links != 2 {
+		t.Errorf("Expected 2 found links, got %d", links)
+	}
+
+	// Both links to the about page are stored as one row
+	var about models.FoundLink
+	database.DB.Where("url_id = ? AND link_url = ?", url.ID, "https://example.com/about").First(&about)
+	if about.Occurrences != 2 || len(about.AnchorTexts) != 2 || about.AnchorTexts[1] != "Our team" {
+		t.Errorf("Expected 2 occurrences with 2 anchor texts, got %d %v", about.Occurrences, about.AnchorTexts)
+	}
+
+	var elsewhere models.FoundLink
+	database.DB.Where("url_id = ? AND region = ? AND rel LIKE ?", url.ID, "footer", "%ugc%").First(&elsewhere)
+	if elsewhere.LinkURL != "https://other.example.org/" || len(elsewhere.RelValues()) != 2 {
+		t.Errorf("Expected the footer link with rel nofollow ugc, got %q %v", elsewhere.LinkURL, elsewhere.RelValues())
+	}
+	if !elsewhere.IsImage || elsewhere.LinkText == nil || *elsewhere.LinkText != "Elsewhere" {
+		t.Errorf("Expected an image link named by its alt text, got %v %v", elsewhere.IsImage, formatOptionalString(elsewhere.LinkText))
+	}
+	if about.IsImage {
+		t.Error("Expected the about link not to be an image link")
+	}
+
+	// Both images are inventoried in document order
+	if result.ImagesCount != 2 || result.ImagesMissingAltCount != 1 || result.ImagesMissingDimensionsCount != 1 || result.ImagesLazyCount != 1 {
+		t.Errorf("Unexpected image summary: total=%d missing_alt=%d missing_dimensions=%d lazy=%d",
+			result.ImagesCount, result.ImagesMissingAltCount, result.ImagesMissingDimensionsCount, result.ImagesLazyCount)
+	}
+	var images []models.PageImage
+	database.DB.Where("url_id = ?", url.ID).Order("position").Find(&images)
+	if len(images) != 2 || images[0].Src == nil || *images[0].Src != "https://example.com/hero.jpg" ||
+		images[0].AltStatus != models.AltMissing || !images[0].HasDimensions() || images[1].AltStatus != models.AltOK {
+		t.Errorf("Unexpected page images: %+v", images)
+	}
+	var forms []models.PageForm
+	database.DB.Where("url_id = ?", url.ID).Find(&forms)
+	if len(forms) != 1 || len(forms[0].Fields) != 2 || !forms[0].IsLogin() || !result.HasLoginForm() || result.FormsCount != 1 {
+		t.Errorf("Expected the login form to be stored, got %+v (confidence %d)", forms, result.LoginConfidence)
+	}
+
+	// The re-crawl sends the stored ETag and the fixture answers 304
+	crawlAndWait(t, manager, &url)
+	database.DB.Where("url_id = ?", url.ID).First(&result)
+	if result.NotModifiedChecks != 1 || result.ChangeStatus != models.ChangeUnchanged {
+		t.Errorf("Expected a not-modified check, got checks=%d status=%q", result.NotModifiedChecks, result.ChangeStatus)
+	}
+
+	// HTTP errors are recorded on the URL
+	gone := models.URL{URL: "https://example.com/gone", Status: models.StatusQueued}
+	database.DB.Create(&gone)
+
+	crawled = crawlAndWait(t, manager, &gone)
+	if crawled.Status != models.StatusError {
+		t.Errorf("Expected crawl to fail, got %s", crawled.Status)
+	}
+	if crawled.ErrorMessage == nil || *crawled.ErrorMessage != "HTTP 410: 410 Gone" {
+		t.Errorf("Unexpected error message: %v", formatOptionalString(crawled.ErrorMessage))
+	}
+}
+
+func TestCrawlManager_SkipsOversizedValidators(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "home", fmt.Sprintf(`{
+		"url": "https://example.com",
+		"headers": {"Content-Type": "text/html", "ETag": "\"%s\"", "Last-Modified": "%s"}
+	}`, strings.Repeat("e", 300), strings.Repeat("m", 120)), `<html><body><h1>Home</h1></body></html>`)
+
+	manager := setupFixtureManager(t, dir)
+
+	url := models.URL{URL: "https://example.com", Status: models.StatusQueued}
+	database.DB.Create(&url)
+
+	// The page is saved without validators rather than failing on the column sizes
+	crawled := crawlAndWait(t, manager, &url)
+	if crawled.Status != models.StatusCompleted {
+		t.Fatalf("Expected crawl to complete, got %s (%v)", crawled.Status, formatOptionalString(crawled.ErrorMessage))
+	}
+	var result models.CrawlResult
+	database.DB.Where("url_id = ?", url.ID).First(&result)
+	if result.ETag != nil || result.LastModified != nil {
+		t.Errorf("Expected oversized validators to be dropped, got etag=%v last_modified=%v",
+			formatOptionalString(result.ETag), formatOptionalString(result.LastModified))
+	}
+
+	// Without validators the re-crawl is a full fetch
+	crawlAndWait(t, manager, &url)
+	var recrawled models.CrawlResult
+	if err := database.DB.Where("url_id = ?", url.ID).First(&recrawled).Error; err != nil {
+		t.Fatalf("Expected a crawl result: %v", err)
+	}
+	if recrawled.NotModifiedChecks != 0 || recrawled.ID == result.ID {
+		t.Errorf("Expected a full re-crawl, got %d not-modified checks", recrawled.NotModifiedChecks)
+	}
+}
+
+// revalidatingFetcher answers 304s with a new ETag, as a server may after revalidating
+type revalidatingFetcher struct {
+	Fetcher
+}
+
+func (f *revalidatingFetcher) FetchURL(rawURL string, validators *CacheValidators) (*CrawlResponse, error) {
+	response, err := f.Fetcher.FetchURL(rawURL, validators)
+	if err == nil && response.NotModified {
+		response.ETag = `"v2"`
+	}
+	return response, err
+}
+
+func TestCrawlManager_NotModifiedUpdatesValidators(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "home", `{
+		"url": "https://example.com",
+		"headers": {"Content-Type": "text/html", "ETag": "\"v1\"", "Last-Modified": "Mon, 05 Oct 2026 10:00:00 GMT"}
+	}`, `<html><body><h1>Home</h1></body></html>`)
+
+	manager := setupFixtureManagerWithConfig(t, dir, func(config *ManagerConfig) {
+		config.Fetcher = &revalidatingFetcher{config.Fetcher}
+	})
+
+	url := models.URL{URL: "https://example.com", Status: models.StatusQueued}
+	database.DB.Create(&url)
+	crawlAndWait(t, manager, &url)
+	crawlAndWait(t, manager, &url)
+
+	var result models.CrawlResult
+	database.DB.Where("url_id = ?", url.ID).First(&result)
+	if result.NotModifiedChecks != 1 {
+		t.Fatalf("Expected a not-modified check, got %d", result.NotModifiedChecks)
+	}
+	if result.ETag == nil || *result.ETag != `"v2"` {
+		t.Errorf("Expected the ETag from the 304, got %v", formatOptionalString(result.ETag))
+	}
+	if result.LastModified == nil || *result.LastModified != "Mon, 05 Oct 2026 10:00:00 GMT" {
+		t.Errorf("Expected Last-Modified to be kept, got %v", formatOptionalString(result.LastModified))
+	}
+}
+
+func TestCrawlManager_LimitsSavedLinks(t *testing.T) {
+	// 150 internal and 100 external links, more than one insert batch
+	var body strings.Builder
+	body.WriteString("<html><head><title>Links</title></head><body>")
+	for i := 0; i < 150; i++ {
+		fmt.Fprintf(&body, `<a href="/page-%d">Page %d</a>`, i, i)
+	}
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&body, `<a href="https://other-%d.example.org/">Other %d</a>`, i, i)
+	}
+	body.WriteString("</body></html>")
+
+	tests := []struct {
+		name      string
+		maxLinks  int
+		saved     int
+		internal  int64
+		truncated bool
+	}{
+		{"default limit", 200, 200, 150, true},
+		{"unlimited", 0, 250, 150, false},
+		{"internal links first", 120, 120, 120, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFixture(t, dir, "links", `{"url": "https://example.com"}`, body.String())
+
+			manager := setupFixtureManagerWithConfig(t, dir, func(config *ManagerConfig) {
+				config.MaxLinks = tt.maxLinks
+			})
+
+			url := models.URL{URL: "https://example.com", Status: models.StatusQueued}
+			database.DB.Create(&url)
+			if crawled := crawlAndWait(t, manager, &url); crawled.Status != models.StatusCompleted {
+				t.Fatalf("Expected crawl to complete, got %s", crawled.Status)
+			}
+
+			var result models.CrawlResult
+			database.DB.Where("url_id = ?", url.ID).First(&result)
+			if result.InternalLinksCount != 150 || result.ExternalLinksCount != 100 {
+				t.Errorf("Expected full link counts, got %d internal and %d external",
+					result.InternalLinksCount, result.ExternalLinksCount)
+			}
+			if result.SavedLinksCount != tt.saved || result.LinksTruncated != tt.truncated {
+				t.Errorf("Expected %d saved links (truncated=%v), got %d (truncated=%v)",
+					tt.saved, tt.truncated, result.SavedLinksCount, result.LinksTruncated)
+			}
+
+			var saved, internal int64
+			database.DB.Model(&models.FoundLink{}).Where("url_id = ?", url.ID).Count(&saved)
+			database.DB.Model(&models.FoundLink{}).Where("url_id = ? AND is_internal = ?", url.ID, true).Count(&internal)
+			if saved != int64(tt.saved) || internal != tt.internal {
+				t.Errorf("Expected %d found links (%d internal), got %d (%d internal)", tt.saved, tt.internal, saved, internal)
+			}
+		})
+	}
+}
+
+func TestCrawlManager_RetriesTransientFailures(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "slow", `{
+		"url": "https://slow.example.com",
+		"error": {"type": "timeout", "message": "Request timed out"}
+	}`, "")
+	writeFixture(t, dir, "missing", `{"url": "https://example.com/missing", "status": 404}`, "")
+
+	manager := setupFixtureManager(t, dir)
+
+	// Timeouts are retried until the attempts run out
+	slow := models.URL{URL: "https://slow.example.com", Status: models.StatusQueued}
+	database.DB.Create(&slow)
+
+	crawled := crawlAndWait(t, manager, &slow)
+	if crawled.Status != models.StatusError {
+		t.Fatalf("Expected crawl to fail after retries, got %s", crawled.Status)
+	}
+	if crawled.RetryCount != 2 {
+		t.Errorf("Expected 2 retries (3 attempts), got %d", crawled.RetryCount)
+	}
+	if crawled.NextRetryAt != nil {
+		t.Error("Exhausted retries should not leave a retry scheduled")
+	}
+
+	// Client errors are permanent and fail on the first attempt
+	missing := models.URL{URL: "https://example.com/missing", Status: models.StatusQueued}
+	database.DB.Create(&missing)
+
+	crawled = crawlAndWait(t, manager, &missing)
+	if crawled.Status != models.StatusError || crawled.RetryCount != 0 {
+		t.Errorf("Expected immediate failure without retries, got %s with %d retries", crawled.Status, crawled.RetryCount)
+	}
+}
+
+// waitForAttempts waits until a URL has the given number of finished attempts and returns them oldest first
+func waitForAttempts(t *testing.T, urlID uint, count int) []models.CrawlAttempt {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var attempts []models.CrawlAttempt
+		database.DB.Where("url_id = ? AND outcome <> ?", urlID, models.AttemptRunning).Order("id").Find(&attempts)
+		if len(attempts) >= count {
+			return attempts
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("URL ID=%d did not record %d finished attempts in time", urlID, count)
+	return nil
+}
+
+func TestCrawlManager_RecordsAttempts(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "home", `{
+		"url": "https://example.com",
+		"headers": {"Content-Type": "text/html", "ETag": "\"v1\""}
+	}`, `<html><head><title>Home</title></head><body><a href="/about">About</a></body></html>`)
+	writeFixture(t, dir, "unavailable", `{"url": "https://example.com/unavailable", "status": 503}`, "")
+
+	manager := setupFixtureManager(t, dir)
+
+	url := models.URL{URL: "https://example.com", Status: models.StatusQueued}
+	database.DB.Create(&url)
+	crawlAndWait(t, manager, &url)
+	crawlAndWait(t, manager, &url)
+
+	attempts := waitForAttempts(t, url.ID, 2)
+	first := attempts[0]
+	if first.Outcome != models.AttemptCompleted || first.Attempt != 1 {
+		t.Errorf("Expected a completed first attempt, got %s (attempt %d)", first.Outcome, first.Attempt)
+	}
+	if first.StatusCode == nil || *first.StatusCode != 200 || first.Bytes == nil || *first.Bytes == 0 {
+		t.Errorf("Expected status 200 and a byte count, got %v and %v", first.StatusCode, first.Bytes)
+	}
+	if first.FinalURL == nil || *first.FinalURL != "https://example.com" {
+		t.Errorf("Unexpected final URL: %v", formatOptionalString(first.FinalURL))
+	}
+	if first.FinishedAt == nil || first.DurationMs == nil {
+		t.Error("Expected finish time and duration to be recorded")
+	}
+	if len(first.LogLines) < 4 {
+		t.Errorf("Expected fetch, parse and save log lines, got %q", first.LogLines)
+	}
+	if attempts[1].Outcome != models.AttemptNotModified {
+		t.Errorf("Expected the re-crawl to be not modified, got %s", attempts[1].Outcome)
+	}
+
+	// A failure is retried once by the test policy; both attempts are kept
+	unavailable := models.URL{URL: "https://example.com/unavailable", Status: models.StatusQueued}
+	database.DB.Create(&unavailable)
+	crawlAndWait(t, manager, &unavailable)
+
+	attempts = waitForAttempts(t, unavailable.ID, 2)
+	for i, expected := range []models.CrawlAttemptOutcome{models.AttemptRetrying, models.AttemptFailed} {
+		attempt := attempts[i]
+		if attempt.Outcome != expected || attempt.Attempt != i+1 {
+			t.Errorf("Attempt %d: expected %s, got %s (attempt %d)", i+1, expected, attempt.Outcome, attempt.Attempt)
+		}
+		if attempt.ErrorType == nil || *attempt.ErrorType != "http_error" {
+			t.Errorf("Attempt %d: expected error type http_error, got %v", i+1, formatOptionalString(attempt.ErrorType))
+		}
+		if attempt.StatusCode == nil || *attempt.StatusCode != 503 {
+			t.Errorf("Attempt %d: expected status 503, got %v", i+1, attempt.StatusCode)
+		}
+	}
+}
+
+func TestAttemptRecorder_BoundsLogLines(t *testing.T) {
+	recorder := &attemptRecorder{attempt: &models.CrawlAttempt{}}
+
+	recorder.logf("%s", strings.Repeat("x", 2*maxAttemptLogLineLength))
+	if line := recorder.attempt.LogLines[0]; len(line) > maxAttemptLogLineLength+20 {
+		t.Errorf("Expected a long line to be truncated, got %d characters", len(line))
+	}
+
+	for i := 0; i < maxAttemptLogLines+5; i++ {
+		recorder.logf("line %d", i)
+	}
+	if len(recorder.attempt.LogLines) != maxAttemptLogLines || recorder.dropped != 6 {
+		t.Errorf("Expected %d lines and 6 dropped, got %d and %d",
+			maxAttemptLogLines, len(recorder.attempt.LogLines), recorder.dropped)
+	}
+
+	// A nil recorder only logs
+	var nilRecorder *attemptRecorder
+	nilRecorder.logf("ignored")
+	nilRecorder.finish(models.AttemptFailed, "network", nil)
+}
+
+// panickingParser panics on pages containing a marker, like a parser bug triggered by malformed input
+type panickingParser struct {
+	HTMLParser
+}
+
+func (p *panickingParser) Parse(htmlContent string, baseURL string) (*ParsedData, error) {
+	if strings.Contains(htmlContent, "<!-- boom -->") {
+		var headings map[string]int
+		headings["h1"]++ // nil map write
+	}
+	return p.HTMLParser.Parse(htmlContent, baseURL)
+}
+
+func TestCrawlManager_RecoversFromJobPanics(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "malformed", `{"url": "https://example.com/malformed"}`,
+		`<html><body><!-- boom --></body></html>`)
+	writeFixture(t, dir, "fine", `{"url": "https://example.com/fine"}`,
+		`<html><head><title>Fine</title></head><body></body></html>`)
+
+	manager := setupFixtureManagerWithConfig(t, dir, func(config *ManagerConfig) {
+		config.Parser = &panickingParser{}
+	})
+
+	malformed := models.URL{URL: "https://example.com/malformed", Status: models.StatusQueued}
+	database.DB.Create(&malformed)
+
+	crawled := crawlAndWait(t, manager, &malformed)
+	if crawled.Status != models.StatusError {
+		t.Fatalf("Expected the panicking job to fail, got %s", crawled.Status)
+	}
+	if crawled.ErrorType == nil || *crawled.ErrorType != "panic" {
+		t.Errorf("Expected error type panic, got %v", formatOptionalString(crawled.ErrorType))
+	}
+	if crawled.ErrorMessage == nil ||
+		!strings.Contains(*crawled.ErrorMessage, "assignment to entry in nil map") ||
+		!strings.Contains(*crawled.ErrorMessage, "panickingParser") {
+		t.Errorf("Expected the panic value and a stack excerpt, got %v", formatOptionalString(crawled.ErrorMessage))
+	}
+	if crawled.WorkerID != nil {
+		t.Error("Expected the lease to be released after a panic")
+	}
+
+	// The worker keeps going
+	fine := models.URL{URL: "https://example.com/fine", Status: models.StatusQueued}
+	database.DB.Create(&fine)
+
+	crawled = crawlAndWait(t, manager, &fine)
+	if crawled.Status != models.StatusCompleted {
+		t.Fatalf("Expected the next job to complete, got %s", crawled.Status)
+	}
+
+	status := manager.GetQueueStatus()
+	if status["is_running"] != true || status["panics"] != int64(1) {
+		t.Errorf("Expected a running manager with 1 panic, got %+v", status)
+	}
+	if _, ok := status["last_panic_at"]; !ok {
+		t.Error("Expected last_panic_at in queue status")
+	}
+
+	attempts := waitForAttempts(t, malformed.ID, 1)
+	if attempts[0].Outcome != models.AttemptFailed || attempts[0].ErrorType == nil || *attempts[0].ErrorType != "panic" {
+		t.Errorf("Expected a failed attempt with error type panic, got %s", attempts[0].Outcome)
+	}
+}
+
+func TestCrawlManager_PanicAfterCommitKeepsCompletedCrawl(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "home", `{"url": "https://example.com"}`,
+		`<html><head><title>Home</title></head><body></body></html>`)
+
+	manager := setupFixtureManager(t, dir)
+
+	// Pruning old attempts runs after the success commit and the attempt is finished
+	var armed atomic.Bool
+	armed.Store(true)
+	database.DB.Callback().Query().Before("gorm:query").Register("test:panic_on_prune", func(db *gorm.DB) {
+		if db.Statement.Table == "crawl_attempts" && armed.CompareAndSwap(true, false) {
+			panic("prune failed")
+		}
+	})
+
+	url := models.URL{URL: "https://example.com", Status: models.StatusQueued}
+	database.DB.Create(&url)
+	crawlAndWait(t, manager, &url)
+
+	// The panic follows the completion; wait for the worker to recover from it
+	deadline := time.Now().Add(5 * time.Second)
+	for manager.GetQueueStatus()["panics"] != int64(1) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the panic to be recovered and counted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var crawled models.URL
+	database.DB.First(&crawled, url.ID)
+	if crawled.Status != models.StatusCompleted || crawled.ErrorType != nil {
+		t.Errorf("Expected the crawl to stay completed, got %s (%v)", crawled.Status, formatOptionalString(crawled.ErrorType))
+	}
+	attempts := waitForAttempts(t, url.ID, 1)
+	if len(attempts) != 1 || attempts[0].Outcome != models.AttemptCompleted || attempts[0].ErrorType != nil {
+		t.Errorf("Expected one completed attempt, got %+v", attempts)
+	}
+}
+
+func TestTruncateString_KeepsWholeCharacters(t *testing.T) {
+	tests := []struct {
+		input     string
+		maxLength int
+		expected  string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"ascii text", 5, "ascii"},
+		{"café", 4, "caf"}, // é is 2 bytes
+		{"日本語", 7, "日本"},   // 3 bytes per character
+		{"日本語", 2, ""},     // No whole character fits
+		{"a😀b", 4, "a"},    // 4-byte emoji
+		{"a😀b", 5, "a😀"},
+	}
+
+	for _, tt := range tests {
+		got := truncateString(tt.input, tt.maxLength)
+		if got != tt.expected || !utf8.ValidString(got) {
+			t.Errorf("truncateString(%q, %d) = %q, expected %q", tt.input, tt.maxLength, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCrawlerService_FetchURLTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		time.Sleep(20 * time.Millisecond) // Simulate a slow server
+		w.Write([]byte("<!DOCTYPE html><html><head><title>Timing</title></head></html>"))
+	}))
+	defer server.Close()
+
+	crawler := NewCrawlerService(nil)
+
+	response, err := crawler.FetchURL(server.URL, nil)
+	if err != nil {
+		t.Fatalf("FetchURL failed: %v", err)
+	}
+
+	timing := response.Timing
+
+	if timing.TimeToFirstByte < 20*time.Millisecond {
+		t.Errorf("Expected time to first byte to include server delay, got %v", timing.TimeToFirstByte)
+	}
+
+	if timing.TLSHandshake != 0 {
+		t.Errorf("Expected no TLS handshake for plain HTTP, got %v", timing.TLSHandshake)
+	}
+
+	if timing.Total < timing.TimeToFirstByte {
+		t.Errorf("Total duration %v should not be shorter than time to first byte %v", timing.Total, timing.TimeToFirstByte)
+	}
+
+	if response.Duration != timing.Total {
+		t.Errorf("Expected Duration to match timing total, got %v and %v", response.Duration, timing.Total)
+	}
+
+	t.Logf("Timing breakdown: %+v", timing)
+}
+
+func TestCrawlerService_FetchURLCapturesTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><title>Secure</title></head></html>"))
+	}))
+	defer server.Close()
+
+	crawler := NewCrawlerService(nil)
+	crawler.client = server.Client() // Trust the test server's self-signed certificate
+
+	response, err := crawler.FetchURL(server.URL, nil)
+	if err != nil {
+		t.Fatalf("FetchURL failed: %v", err)
+	}
+
+	if response.TLS == nil {
+		t.Fatal("Expected TLS details for HTTPS response")
+	}
+
+	if response.TLS.Version == "" || response.TLS.CipherSuite == "" {
+		t.Errorf("Expected TLS version and cipher suite, got %+v", response.TLS)
+	}
+
+	leaf := response.TLS.Leaf()
+	if leaf == nil {
+		t.Fatal("Expected a leaf certificate")
+	}
+
+	if leaf.NotAfter.Before(leaf.NotBefore) {
+		t.Errorf("Certificate validity is inverted: %v - %v", leaf.NotBefore, leaf.NotAfter)
+	}
+
+	if len(leaf.DNSNames) == 0 {
+		t.Error("Expected certificate to carry subject alternative names")
+	}
+
+	if response.Timing.TLSHandshake == 0 {
+		t.Error("Expected TLS handshake time to be recorded")
+	}
+}
+
+func TestCrawlerService_FetchURLConditional(t *testing.T) {
+	const etag = `"v1"`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 02 Jun 2025 10:00:00 GMT")
+		w.Write([]byte("<html><head><title>Cached</title></head></html>"))
+	}))
+	defer server.Close()
+
+	crawler := NewCrawlerService(nil)
+
+	// First crawl downloads the page and returns its validators
+	first, err := crawler.FetchURL(server.URL, nil)
+	if err != nil {
+		t.Fatalf("FetchURL failed: %v", err)
+	}
+
+	if first.NotModified {
+		t.Error("Unconditional request should not be reported as not modified")
+	}
+
+	if first.ETag != etag || first.LastModified == "" {
+		t.Errorf("Expected validators to be captured, got ETag=%q Last-Modified=%q", first.ETag, first.LastModified)
+	}
+
+	// Re-crawl with the validators gets a cheap 304
+	second, err := crawler.FetchURL(server.URL, &CacheValidators{ETag: first.ETag, LastModified: first.LastModified})
+	if err != nil {
+		t.Fatalf("Conditional FetchURL failed: %v", err)
+	}
+
+	if !second.NotModified {
+		t.Errorf("Expected 304 to be reported as not modified, got status %d", second.StatusCode)
+	}
+
+	if second.HTML != "" {
+		t.Error("Not-modified response should carry no HTML")
+	}
+}
+
+func TestCrawlerService_FetchURLRejectsUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified) // 304 without a conditional request
+	}))
+	defer server.Close()
+
+	crawler := NewCrawlerService(nil)
+
+	_, err := crawler.FetchURL(server.URL, nil)
+	if err == nil {
+		t.Fatal("Expected an error for an unsolicited 304")
+	}
+
+	crawlErr, ok := err.(*CrawlError)
+	if !ok || crawlErr.Type != "http_error" {
+		t.Errorf("Expected http_error CrawlError, got %v", err)
+	}
+}
+
+func TestCrawlerService_ClassifyNetworkError(t *testing.T) {
+	crawler := NewCrawlerService(nil)
+
+	// Errors as the HTTP client wraps them
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://example.com", Err: err}
+	}
+
+	tests := []struct {
+		name      string
+		err       error
+		errorType string
+	}{
+		{"redirects", wrap(errTooManyRedirects), "redirect_error"},
+		{"deadline", wrap(context.DeadlineExceeded), "timeout"},
+		{"dial timeout", wrap(&net.OpError{Op: "dial", Err: &timeoutError{}}), "timeout"},
+		{"unknown host", wrap(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "nope.invalid", IsNotFound: true}}), "dns_error"},
+		{"unknown authority", wrap(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}), "tls_error"},
+		{"wrong host", wrap(&tls.CertificateVerificationError{Err: x509.HostnameError{Host: "example.com"}}), "tls_error"},
+		{"refused", wrap(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), "connection_error"},
+		{"reset", wrap(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), "connection_error"},
+		{"other", wrap(errors.New("unexpected EOF")), "network"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawlErr := crawler.classifyNetworkError("https://example.com", tt.err)
+			if crawlErr.Type != tt.errorType {
+				t.Errorf("Expected %q, got %q (%s)", tt.errorType, crawlErr.Type, crawlErr.Message)
+			}
+			if !errors.Is(crawlErr, tt.err) {
+				t.Error("CrawlError should unwrap to the original error")
+			}
+		})
+	}
+}
+
+func TestCrawlerService_FetchURLClassifiesRealFailures(t *testing.T) {
+	crawler := NewCrawlerService(nil)
+
+	// Self-signed certificate that the default client does not trust
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	_, err := crawler.FetchURL(tlsServer.URL, nil)
+	tlsServer.Close()
+	if crawlErr, ok := err.(*CrawlError); !ok || crawlErr.Type != "tls_error" {
+		t.Errorf("Expected tls_error, got %v", err)
+	}
+
+	// Nothing listening on a closed server's port
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+	_, err = crawler.FetchURL(closed.URL, nil)
+	if crawlErr, ok := err.(*CrawlError); !ok || crawlErr.Type != "connection_error" {
+		t.Errorf("Expected connection_error, got %v", err)
+	}
+}
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (e *timeoutError) Error() string   { return "i/o timeout" }
+func (e *timeoutError) Timeout() bool   { return true }
+func (e *timeoutError) Temporary() bool { return true }
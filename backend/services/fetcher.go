@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fetcher retrieves a page for the crawl manager.
+// CrawlerService fetches over HTTP; FixtureFetcher replays recorded responses.
+type Fetcher interface {
+	// FetchURL fetches a URL. If validators are given, the request is
+	// conditional and an unchanged page is returned with NotModified set.
+	// Failures are returned as *CrawlError.
+	FetchURL(rawURL string, validators *CacheValidators) (*CrawlResponse, error)
+}
+
+// Compile-time check that both fetchers satisfy the interface
+var (
+	_ Fetcher = (*CrawlerService)(nil)
+	_ Fetcher = (*FixtureFetcher)(nil)
+)
+
+// checkResponseStatus returns the CrawlError for a status code that has no page to parse
+func checkResponseStatus(rawURL string, statusCode int, status string, header http.Header) error {
+	if statusCode >= 400 {
+		crawlErr := NewCrawlError("http_error",
+			fmt.Sprintf("HTTP %d: %s", statusCode, status),
+			rawURL, nil)
+		crawlErr.StatusCode = statusCode
+		crawlErr.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+		return crawlErr
+	}
+
+	// Anything other than 2xx at this point (1xx, unfollowed 3xx) has no page to parse
+	if statusCode < 200 || statusCode >= 300 {
+		crawlErr := NewCrawlError("http_error",
+			fmt.Sprintf("Unexpected HTTP %d: %s", statusCode, status),
+			rawURL, nil)
+		crawlErr.StatusCode = statusCode
+		return crawlErr
+	}
+
+	return nil
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// isHTMLContentType checks if the content type indicates HTML content
+func isHTMLContentType(contentType string) bool {
+	if contentType == "" {
+		return true // Assume HTML if no content type
+	}
+
+	contentType = strings.ToLower(contentType)
+	return strings.Contains(contentType, "text/html") ||
+		strings.Contains(contentType, "application/xhtml+xml")
+}
+
+// statusText formats a status code the way http.Response.Status does
+func statusText(statusCode int) string {
+	return fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Fixture is a recorded response, stored as <name>.json with the body in <name>.html:
+//
+//	{
+//	  "url": "https://example.com/",
+//	  "status": 200,
+//	  "headers": {"Content-Type": "text/html; charset=utf-8", "ETag": "\"v1\""}
+//	}
+type Fixture struct {
+	URL      string            `json:"url"`                 // Requested URL the fixture answers
+	Status   int               `json:"status"`              // Status code (default: 200)
+	Headers  map[string]string `json:"headers"`             // Response headers
+	FinalURL string            `json:"final_url,omitempty"` // URL after redirects (default: URL)
+	BodyFile string            `json:"body_file,omitempty"` // Body file relative to the fixture (default: <name>.html)
+	Error    *FixtureError     `json:"error,omitempty"`     // Simulate a failed fetch instead of a response
+
+	body string
+}
+
+// FixtureError describes a simulated fetch failure, such as a timeout or DNS error
+type FixtureError struct {
+	Type    string `json:"type"`    // CrawlError type, e.g. "timeout" or "dns_error"
+	Message string `json:"message"` // Human-readable error message
+}
+
+// FixtureFetcher serves recorded responses from a directory instead of the
+// network, so crawls can be tested offline and bug reports replayed
+type FixtureFetcher struct {
+	config   *CrawlerConfig
+	fixtures map[string]*Fixture
+}
+
+// NewFixtureFetcher loads every fixture in dir. The crawler config's page size
+// limit is applied to bodies the same way as for live fetches (nil = defaults).
+func NewFixtureFetcher(dir string, config *CrawlerConfig) (*FixtureFetcher, error) {
+	if config == nil {
+		config = DefaultCrawlerConfig()
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+
+	fetcher := &FixtureFetcher{
+		config:   config,
+		fixtures: make(map[string]*Fixture, len(paths)),
+	}
+
+	for _, path := range paths {
+		fixture, err := loadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := fetcher.fixtures[fixture.URL]; exists {
+			return nil, fmt.Errorf("duplicate fixture for %s in %s", fixture.URL, path)
+		}
+		fetcher.fixtures[fixture.URL] = fixture
+	}
+
+	log.Printf("Loaded %d fetch fixtures from %s", len(fetcher.fixtures), dir)
+	return fetcher, nil
+}
+
+// loadFixture reads a fixture file and its body
+func loadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	if fixture.URL == "" {
+		return nil, fmt.Errorf("fixture %s has no url", path)
+	}
+	if fixture.Status == 0 {
+		fixture.Status = http.StatusOK
+	}
+	if fixture.Error != nil {
+		return &fixture, nil
+	}
+
+	bodyFile := fixture.BodyFile
+	if bodyFile == "" {
+		bodyFile = strings.TrimSuffix(filepath.Base(path), ".json") + ".html"
+	}
+
+	body, err := os.ReadFile(filepath.Join(filepath.Dir(path), bodyFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read fixture body for %s: %w", path, err)
+	}
+	fixture.body = string(body) // A missing body file means an empty body
+
+	return &fixture, nil
+}
+
+// FetchURL returns the recorded response for a URL, applying the same status,
+// content type and size checks as a live fetch
+func (f *FixtureFetcher) FetchURL(rawURL string, validators *CacheValidators) (*CrawlResponse, error) {
+	fixture := f.lookup(rawURL)
+	if fixture == nil {
+		return nil, NewCrawlError("network", "No fixture recorded for URL", rawURL,
+			fmt.Errorf("no fixture for %s", rawURL))
+	}
+
+	if fixture.Error != nil {
+		return nil, NewCrawlError(fixture.Error.Type, fixture.Error.Message, rawURL, nil)
+	}
+
+	headers := make(http.Header, len(fixture.Headers))
+	for name, value := range fixture.Headers {
+		headers.Set(name, value)
+	}
+
+	finalURL := fixture.FinalURL
+	if finalURL == "" {
+		finalURL = rawURL
+	}
+
+	// Answer a conditional request with 304 if the validators still match
+	if f.notModified(validators, headers) {
+		return &CrawlResponse{
+			StatusCode:   http.StatusNotModified,
+			Headers:      headers,
+			URL:          finalURL,
+			NotModified:  true,
+			ETag:         headers.Get("ETag"),
+			LastModified: headers.Get("Last-Modified"),
+		}, nil
+	}
+
+	if err := checkResponseStatus(rawURL, fixture.Status, statusText(fixture.Status), headers); err != nil {
+		return nil, err
+	}
+
+	contentType := headers.Get("Content-Type")
+	if !isHTMLContentType(contentType) {
+		return nil, NewCrawlError("invalid_content",
+			fmt.Sprintf("Content-Type '%s' is not HTML", contentType),
+			rawURL, nil)
+	}
+
+	if int64(len(fixture.body)) > f.config.MaxPageSize {
+		return nil, NewCrawlError("too_large",
+			fmt.Sprintf("Response size exceeds limit of %d bytes", f.config.MaxPageSize),
+			rawURL, nil)
+	}
+
+	// Fixtures are served instantly; report a nominal duration so timings are non-zero
+	duration := time.Millisecond
+	return &CrawlResponse{
+		HTML:         fixture.body,
+		StatusCode:   fixture.Status,
+		ContentType:  contentType,
+		Headers:      headers,
+		ResponseSize: int64(len(fixture.body)),
+		Duration:     duration,
+		Timing:       FetchTiming{Total: duration},
+		URL:          finalURL,
+		ETag:         headers.Get("ETag"),
+		LastModified: headers.Get("Last-Modified"),
+	}, nil
+}
+
+// lookup finds the fixture for a URL, ignoring a trailing slash
+func (f *FixtureFetcher) lookup(rawURL string) *Fixture {
+	if fixture, ok := f.fixtures[rawURL]; ok {
+		return fixture
+	}
+	if strings.HasSuffix(rawURL, "/") {
+		return f.fixtures[strings.TrimSuffix(rawURL, "/")]
+	}
+	return f.fixtures[rawURL+"/"]
+}
+
+// notModified reports whether a conditional request matches the recorded validators
+func (f *FixtureFetcher) notModified(validators *CacheValidators, headers http.Header) bool {
+	if validators.IsEmpty() {
+		return false
+	}
+	if validators.ETag != "" {
+		return validators.ETag == headers.Get("ETag")
+	}
+	return validators.LastModified == headers.Get("Last-Modified")
+}
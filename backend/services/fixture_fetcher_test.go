@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFixture writes a fixture definition and, if body is not empty, its body file
+func writeFixture(t *testing.T, dir, name, definition, body string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(definition), 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	if body != "" {
+		if err := os.WriteFile(filepath.Join(dir, name+".html"), []byte(body), 0o644); err != nil {
+			t.Fatalf("Failed to write fixture body: %v", err)
+		}
+	}
+}
+
+func TestFixtureFetcher_ServesRecordedResponses(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "home", `{
+		"url": "https://example.com/",
+		"headers": {"Content-Type": "text/html", "ETag": "\"v1\""}
+	}`, "<html><head><title>Home</title></head></html>")
+	writeFixture(t, dir, "missing", `{"url": "https://example.com/missing", "status": 404}`, "")
+	writeFixture(t, dir, "slow", `{
+		"url": "https://slow.example.com",
+		"error": {"type": "timeout", "message": "Request timed out"}
+	}`, "")
+
+	fetcher, err := NewFixtureFetcher(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	// Trailing slashes are ignored when matching
+	response, err := fetcher.FetchURL("https://example.com", nil)
+	if err != nil {
+		t.Fatalf("Expected recorded response, got %v", err)
+	}
+	if response.StatusCode != 200 || response.HTML != "<html><head><title>Home</title></head></html>" {
+		t.Errorf("Unexpected response: status=%d body=%q", response.StatusCode, response.HTML)
+	}
+	if response.ETag != `"v1"` {
+		t.Errorf("Expected ETag from fixture headers, got %q", response.ETag)
+	}
+
+	// A matching conditional request gets a 304
+	response, err = fetcher.FetchURL("https://example.com/", &CacheValidators{ETag: `"v1"`})
+	if err != nil {
+		t.Fatalf("Expected not-modified response, got %v", err)
+	}
+	if !response.NotModified {
+		t.Error("Expected matching ETag to produce a not-modified response")
+	}
+
+	tests := []struct {
+		url       string
+		errorType string
+	}{
+		{"https://example.com/missing", "http_error"},
+		{"https://slow.example.com", "timeout"},
+		{"https://unrecorded.example.com", "network"},
+	}
+
+	for _, tt := range tests {
+		_, err := fetcher.FetchURL(tt.url, nil)
+		var crawlErr *CrawlError
+		if !errors.As(err, &crawlErr) {
+			t.Errorf("%s: expected CrawlError, got %v", tt.url, err)
+			continue
+		}
+		if crawlErr.Type != tt.errorType {
+			t.Errorf("%s: expected error type %q, got %q", tt.url, tt.errorType, crawlErr.Type)
+		}
+	}
+}
+
+func TestNewFixtureFetcher_RejectsDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "a", `{"url": "https://example.com"}`, "<p>a</p>")
+	writeFixture(t, dir, "b", `{"url": "https://example.com"}`, "<p>b</p>")
+
+	if _, err := NewFixtureFetcher(dir, nil); err == nil {
+		t.Error("Expected duplicate fixtures for the same URL to be rejected")
+	}
+}
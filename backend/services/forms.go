@@ -0,0 +1,321 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"web-crawler/models"
+
+	"golang.org/x/net/html"
+)
+
+// FormInfo describes a form on a parsed page
+type FormInfo struct {
+	Action          string               `json:"action"`           // Resolved action, the page URL if missing; "" for standalone controls
+	Method          string               `json:"method"`           // "get", "post" or "dialog"; "" for standalone controls
+	Standalone      bool                 `json:"standalone"`       // Controls outside any <form>
+	Fields          []models.FormField   `json:"fields"`           // Named controls in document order (up to maxFormFields)
+	HasCSRFToken    bool                 `json:"has_csrf_token"`   // Hidden field named like a CSRF token
+	InsecureAction  bool                 `json:"insecure_action"`  // Submits over plain HTTP from an HTTPS page
+	LoginConfidence int                  `json:"login_confidence"` // 0-100
+	LoginSignals    []models.LoginSignal `json:"login_signals"`    // Evidence behind LoginConfidence
+
+	labels []string // Submit button texts and the form's id, class, name and aria-label
+}
+
+// Limits that keep a page from storing an unbounded forms inventory
+const (
+	maxForms      = 50  // Forms kept per page, including the standalone controls
+	maxFormFields = 100 // Fields kept per form
+)
+
+// loginSignalWeights are the points each signal adds to a login confidence
+var loginSignalWeights = map[models.LoginSignal]int{
+	models.LoginPasswordField:   50,
+	models.LoginIdentifierField: 35,
+	models.LoginText:            25,
+	models.LoginAction:          15,
+	models.LoginOAuthButton:     50,
+	models.LoginRegistration:    -40,
+}
+
+var (
+	// "Log in", "login", "logon", "Sign in", "signin" and "sign-in", also at the
+	// start of camelCase or snake_case names such as "loginForm", but not "signing"
+	loginTextPattern        = regexp.MustCompile(`\b(?i:log[\s_-]?[io]n|sign[\s_-]?in)(?:\b|[A-Z_])`)
+	registrationTextPattern = regexp.MustCompile(`\b(?i:sign[\s_-]?up|register|create[\s_-]?(?:an[\s_-]?)?account)(?:\b|[A-Z_])`)
+	loginActionPattern      = regexp.MustCompile(`(?i)(log[_-]?[io]n|sign[_-]?in|/sessions?(/|$)|/auth(/|$)|/sso(/|$))`)
+	identifierFieldPattern  = regexp.MustCompile(`(?i)(e-?mail|user|login|account|identifier|phone)`)
+	csrfFieldPattern        = regexp.MustCompile(`(?i)(csrf|xsrf|authenticity_token|requestverificationtoken|antiforgery|^_?_token$|nonce)`)
+)
+
+// oauthButtonPattern matches "Sign in with Google" or "Continue with GitHub", but
+// not "Continue with checkout" or "Continue with PayPal": only known identity
+// providers and single sign-on count
+var oauthButtonPattern = regexp.MustCompile(`(?i)\b(?:(?:sign|log)[\s-]?(?:in|on)|continue)\s+(?:with|using|via)\s+(?:your\s+)?` +
+	`(?:google|github|gitlab|bitbucket|microsoft|azure|apple|facebook|meta|twitter|x|linkedin|okta|auth0|discord|slack|amazon|yahoo|sso|saml|single[\s-]sign[\s-]on)\b`)
+
+// extractForms inventories the page's forms. A control belongs to the form
+// named by its form attribute, or else to the enclosing <form>; controls with
+// neither are grouped into one standalone form after the others.
+func (p *HTMLParser) extractForms(doc *html.Node, baseURL string) []FormInfo {
+	var forms []*FormInfo
+	formsByID := make(map[string]*FormInfo)
+	standalone := &FormInfo{Standalone: true, Fields: []models.FormField{}}
+
+	// Owners named by a form attribute may come later in the document
+	type ownedControl struct {
+		node  *html.Node
+		owner string
+	}
+	var owned []ownedControl
+
+	var walk func(n *html.Node, form *FormInfo)
+	walk = func(n *html.Node, form *FormInfo) {
+		if n.Type == html.ElementNode {
+			switch strings.ToLower(n.Data) {
+			case "template":
+				// Template content is inert until scripts clone it
+				return
+			case "form":
+				// The HTML parser drops nested <form> tags, but be safe
+				if form == nil {
+					form = p.newFormInfo(n, baseURL)
+					forms = append(forms, form)
+					if id := strings.TrimSpace(getAttr(n, "id")); id != "" && formsByID[id] == nil {
+						formsByID[id] = form
+					}
+				}
+			case "input", "select", "textarea", "button":
+				if owner, ok := lookupAttr(n, "form"); ok {
+					owned = append(owned, ownedControl{n, strings.TrimSpace(owner)})
+				} else if form != nil {
+					p.addFormControl(form, n)
+				} else {
+					p.addFormControl(standalone, n)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, form)
+		}
+	}
+	walk(doc, nil)
+
+	for _, control := range owned {
+		if form := formsByID[control.owner]; form != nil {
+			p.addFormControl(form, control.node)
+		} else {
+			p.addFormControl(standalone, control.node)
+		}
+	}
+	if len(standalone.Fields) > 0 {
+		forms = append(forms, standalone)
+	}
+
+	inventory := make([]FormInfo, 0, min(len(forms), maxForms))
+	for _, form := range forms[:min(len(forms), maxForms)] {
+		scoreLoginForm(form)
+		inventory = append(inventory, *form)
+	}
+	return inventory
+}
+
+// newFormInfo reads the attributes of a <form> element
+func (p *HTMLParser) newFormInfo(n *html.Node, baseURL string) *FormInfo {
+	form := &FormInfo{Method: "get", Fields: []models.FormField{}}
+
+	switch method := strings.ToLower(strings.TrimSpace(getAttr(n, "method"))); method {
+	case "post", "dialog":
+		form.Method = method
+	}
+
+	// A missing or empty action submits to the page itself
+	form.Action = baseURL
+	if action := strings.TrimSpace(getAttr(n, "action")); action != "" {
+		form.Action = p.resolveURL(action, baseURL)
+	}
+	form.Action = truncateString(form.Action, 2000)
+
+	if form.Method != "dialog" {
+		page, pageErr := url.Parse(baseURL)
+		action, actionErr := url.Parse(form.Action)
+		form.InsecureAction = pageErr == nil && actionErr == nil &&
+			strings.EqualFold(page.Scheme, "https") && strings.EqualFold(action.Scheme, "http")
+	}
+
+	for _, key := range []string{"id", "class", "name", "aria-label"} {
+		form.labels = append(form.labels, getAttr(n, key))
+	}
+	return form
+}
+
+// addFormControl records a control of a form. Buttons are not fields, but
+// their text and the value of submit inputs feed the login detection.
+func (p *HTMLParser) addFormControl(form *FormInfo, n *html.Node) {
+	var fieldType string
+	switch tag := strings.ToLower(n.Data); tag {
+	case "button":
+		buttonType := strings.ToLower(strings.TrimSpace(getAttr(n, "type")))
+		if buttonType == "" || buttonType == "submit" || form.Standalone {
+			form.labels = append(form.labels, p.extractVisibleText(n), getAttr(n, "aria-label"))
+		}
+		return
+	case "select", "textarea":
+		fieldType = tag
+	default:
+		fieldType = strings.ToLower(strings.TrimSpace(getAttr(n, "type")))
+		if fieldType == "" {
+			fieldType = "text"
+		}
+		if fieldType == "submit" || fieldType == "image" {
+			form.labels = append(form.labels, getAttr(n, "value"), getAttr(n, "alt"))
+		}
+	}
+
+	name := strings.TrimSpace(getAttr(n, "name"))
+	if name == "" {
+		name = strings.TrimSpace(getAttr(n, "id"))
+	}
+	if name == "" {
+		switch fieldType {
+		case "submit", "image", "button", "reset":
+			// Unnamed buttons submit nothing
+			return
+		}
+	}
+
+	if fieldType == "hidden" && csrfFieldPattern.MatchString(name) {
+		form.HasCSRFToken = true
+	}
+	if len(form.Fields) < maxFormFields {
+		form.Fields = append(form.Fields, models.FormField{
+			Name:         truncateString(name, 255),
+			Type:         fieldType,
+			Autocomplete: strings.ToLower(strings.Join(strings.Fields(getAttr(n, "autocomplete")), " ")),
+		})
+	}
+}
+
+// scoreLoginForm collects the login signals of a form and its confidence.
+// An email or username field alone marks the first step of a two-step login,
+// as long as the form is short and has no free text, unlike a contact form.
+func scoreLoginForm(form *FormInfo) {
+	passwords, visible := 0, 0
+	identifier, freeText, newPassword := false, false, false
+
+	for _, field := range form.Fields {
+		switch field.Type {
+		case "hidden", "submit", "image", "button", "reset", "checkbox", "radio":
+			continue
+		}
+		visible++
+
+		autocomplete := strings.Fields(field.Autocomplete)
+		switch {
+		case field.Type == "password":
+			passwords++
+			newPassword = newPassword || containsString(autocomplete, "new-password")
+		case field.Type == "textarea":
+			freeText = true
+		case field.Type == "email", containsString(autocomplete, "username"), containsString(autocomplete, "email"):
+			identifier = true
+		case field.Type == "text" || field.Type == "tel":
+			identifier = identifier || identifierFieldPattern.MatchString(field.Name)
+		}
+	}
+
+	signals := make([]models.LoginSignal, 0)
+	if passwords > 0 {
+		signals = append(signals, models.LoginPasswordField)
+	}
+	if identifier && !freeText && visible <= 3 {
+		signals = append(signals, models.LoginIdentifierField)
+	}
+	if matchesAny(loginTextPattern, form.labels) {
+		signals = append(signals, models.LoginText)
+	}
+	if action, err := url.Parse(form.Action); err == nil && !form.Standalone && loginActionPattern.MatchString(action.Path) {
+		signals = append(signals, models.LoginAction)
+	}
+	if newPassword || passwords > 1 || matchesAny(registrationTextPattern, form.labels) {
+		signals = append(signals, models.LoginRegistration)
+	}
+
+	form.LoginSignals = signals
+	form.LoginConfidence = loginConfidence(signals)
+}
+
+// pageLogin combines the strongest login form with the page's sign-in buttons,
+// so a page offering only "Sign in with Google" still counts as a login page
+func pageLogin(forms []FormInfo, oauthButton bool) (int, []models.LoginSignal) {
+	signals := make([]models.LoginSignal, 0)
+
+	best := -1
+	for i, form := range forms {
+		if form.LoginConfidence > 0 && (best < 0 || form.LoginConfidence > forms[best].LoginConfidence) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		signals = append(signals, forms[best].LoginSignals...)
+	}
+	if oauthButton {
+		signals = append(signals, models.LoginOAuthButton)
+	}
+
+	return loginConfidence(signals), signals
+}
+
+// loginConfidence adds up the weights of the signals, clamped to 0-100
+func loginConfidence(signals []models.LoginSignal) int {
+	confidence := 0
+	for _, signal := range signals {
+		confidence += loginSignalWeights[signal]
+	}
+	return max(0, min(confidence, 100))
+}
+
+// hasOAuthButton reports whether a link or button on the page signs in with
+// another provider, e.g. "Sign in with Google" or "Continue with GitHub"
+func (p *HTMLParser) hasOAuthButton(n *html.Node) bool {
+	if n.Type == html.ElementNode {
+		tag := strings.ToLower(n.Data)
+		if tag == "a" || tag == "button" || strings.EqualFold(strings.TrimSpace(getAttr(n, "role")), "button") {
+			if matchesAny(oauthButtonPattern, []string{p.extractVisibleText(n), getAttr(n, "aria-label"), getAttr(n, "title")}) {
+				return true
+			}
+		}
+		if tag == "input" && oauthButtonPattern.MatchString(getAttr(n, "value")) {
+			return true
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if p.hasOAuthButton(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether the pattern matches one of the texts
+func matchesAny(pattern *regexp.Regexp, texts []string) bool {
+	for _, text := range texts {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether a slice contains a value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"web-crawler/models"
+)
+
+func TestHTMLParser_Forms(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><body>
+		<form action="/session" method="POST" class="login-form">
+			<input type="hidden" name="authenticity_token" value="abc">
+			<input type="email" name="email" autocomplete="username">
+			<input type="password" name="password" autocomplete="current-password">
+			<input type="checkbox" name="remember">
+			<button>Sign in</button>
+		</form>
+		<form action="http://auth.example.com/u/login/identifier" method="post">
+			<input name="identifier" autocomplete="username email">
+			<button type="submit">Continue</button>
+		</form>
+		<form id="signup" action="/users">
+			<input type="email" name="email">
+			<input type="password" name="password" autocomplete="new-password">
+			<input type="submit" value="Create account">
+		</form>
+		<form action="/contact" method="post">
+			<input name="name"><input type="email" name="email"><textarea name="message"></textarea>
+			<button>Send</button>
+		</form>
+		<form id="search" role="search" action="/search"></form>
+		<input type="search" name="q" form="search">
+		<div class="spa-login">
+			<input type="email" id="login-email">
+			<input type="password" id="login-password">
+			<button type="button">Log in</button>
+		</div>
+		<template><form action="/ignored"><input name="x"></form></template>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com/login")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(result.Forms) != 6 {
+		t.Fatalf("Expected 5 forms and the standalone controls, got %d: %+v", len(result.Forms), result.Forms)
+	}
+
+	type expected struct {
+		action     string
+		method     string
+		fields     int
+		csrf       bool
+		insecure   bool
+		confidence int
+		signals    []models.LoginSignal
+	}
+	want := []expected{
+		{"https://example.com/session", "post", 4, true, false, 100, []models.LoginSignal{
+			models.LoginPasswordField, models.LoginIdentifierField, models.LoginText, models.LoginAction}},
+		// The first step of a two-step login, posted over plain HTTP
+		{"http://auth.example.com/u/login/identifier", "post", 1, false, true, 50, []models.LoginSignal{
+			models.LoginIdentifierField, models.LoginAction}},
+		{"https://example.com/users", "get", 2, false, false, 45, []models.LoginSignal{
+			models.LoginPasswordField, models.LoginIdentifierField, models.LoginRegistration}},
+		{"https://example.com/contact", "post", 3, false, false, 0, []models.LoginSignal{}},
+		// The search input joins its form through the form attribute
+		{"https://example.com/search", "get", 1, false, false, 0, []models.LoginSignal{}},
+		{"", "", 2, false, false, 100, []models.LoginSignal{
+			models.LoginPasswordField, models.LoginIdentifierField, models.LoginText}},
+	}
+
+	for i, form := range result.Forms {
+		w := want[i]
+		if form.Action != w.action || form.Method != w.method || len(form.Fields) != w.fields {
+			t.Errorf("Form %d: expected %s %q with %d fields, got %s %q with %+v", i, w.method, w.action, w.fields, form.Method, form.Action, form.Fields)
+		}
+		if form.HasCSRFToken != w.csrf || form.InsecureAction != w.insecure {
+			t.Errorf("Form %d: expected csrf=%v insecure=%v, got csrf=%v insecure=%v", i, w.csrf, w.insecure, form.HasCSRFToken, form.InsecureAction)
+		}
+		if form.LoginConfidence != w.confidence || !reflect.DeepEqual(form.LoginSignals, w.signals) {
+			t.Errorf("Form %d: expected confidence %d from %v, got %d from %v", i, w.confidence, w.signals, form.LoginConfidence, form.LoginSignals)
+		}
+	}
+
+	if !result.Forms[5].Standalone || result.Forms[5].Fields[1] != (models.FormField{Name: "login-password", Type: "password"}) {
+		t.Errorf("Expected the controls outside any form to be grouped, got %+v", result.Forms[5])
+	}
+	if result.LoginConfidence != 100 || !result.HasLoginForm() {
+		t.Errorf("Expected the page to have a login form, got confidence %d", result.LoginConfidence)
+	}
+}
+
+func TestHTMLParser_OAuthLogin(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><body>
+		<h1>Welcome back</h1>
+		<a href="/auth/google" class="btn">Sign in with <span>Google</span></a>
+		<form action="/newsletter"><input type="email" name="email"><button>Subscribe</button></form>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com/")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// The newsletter form is the strongest form, and the OAuth link adds to it
+	if result.Forms[0].LoginConfidence != 35 {
+		t.Errorf("Expected the newsletter form alone not to count as a login, got %d", result.Forms[0].LoginConfidence)
+	}
+	want := []models.LoginSignal{models.LoginIdentifierField, models.LoginOAuthButton}
+	if result.LoginConfidence != 85 || !reflect.DeepEqual(result.LoginSignals, want) {
+		t.Errorf("Expected confidence 85 from %v, got %d from %v", want, result.LoginConfidence, result.LoginSignals)
+	}
+
+	// Checkout and payment buttons are not identity providers
+	result, err = parser.Parse(`<html><body>
+		<button>Continue with checkout</button>
+		<a href="/pay" role="button">Continue with PayPal</a>
+		<input type="submit" value="Continue with shipping">
+	</body></html>`, "https://example.com/cart")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if result.LoginConfidence != 0 || result.HasLoginForm() {
+		t.Errorf("Expected checkout buttons not to count as a login, got confidence %d from %v", result.LoginConfidence, result.LoginSignals)
+	}
+
+	result, err = parser.Parse(`<html><body><button aria-label="Log in using your SSO"></button></body></html>`, "https://example.com/")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if result.LoginConfidence != 50 || !result.HasLoginForm() {
+		t.Errorf("Expected a single sign-on button to count as a login, got confidence %d from %v", result.LoginConfidence, result.LoginSignals)
+	}
+
+	result, err = parser.Parse(`<html><body><p>Signing up is free. <a href="/register">Register</a></p></body></html>`, "https://example.com/")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if result.LoginConfidence != 0 || result.HasLoginForm() || len(result.Forms) != 0 {
+		t.Errorf("Expected no login, got confidence %d from %v", result.LoginConfidence, result.LoginSignals)
+	}
+}
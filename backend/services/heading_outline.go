@@ -0,0 +1,105 @@
+package services
+
+import (
+	"strings"
+
+	"web-crawler/models"
+
+	"golang.org/x/net/html"
+)
+
+// Limits that keep a page from storing an unbounded outline
+const (
+	maxHeadings          = 500 // Headings kept per page; HeadingCounts still counts all of them
+	maxHeadingTextLength = 500 // Bytes kept per heading text
+)
+
+// addHeading records a heading, up to maxHeadings per page
+func (d *ParsedData) addHeading(heading models.Heading) {
+	if len(d.Headings) < maxHeadings {
+		d.Headings = append(d.Headings, heading)
+	}
+}
+
+// extractHeading reads an h1-h6 element. A heading without text is named like
+// an image link: by the image inside it, or by its aria-label.
+func (p *HTMLParser) extractHeading(n *html.Node) models.Heading {
+	heading := models.Heading{
+		Level:  int(strings.ToLower(n.Data)[1] - '0'),
+		Text:   p.extractVisibleText(n),
+		Hidden: isHiddenElement(n),
+		Issues: []models.HeadingIssue{},
+	}
+
+	if heading.Text == "" {
+		if image := findLinkImage(n); image != nil {
+			heading.Text = imageLinkText(n, image)
+		} else {
+			heading.Text = strings.Join(strings.Fields(getAttr(n, "aria-label")), " ")
+		}
+	}
+	heading.Text = truncateString(heading.Text, maxHeadingTextLength)
+
+	return heading
+}
+
+// isHiddenElement reports whether an element or one of its ancestors has the
+// hidden attribute or an inline display:none style
+func isHiddenElement(n *html.Node) bool {
+	for node := n; node != nil; node = node.Parent {
+		if node.Type != html.ElementNode {
+			continue
+		}
+		if _, hidden := lookupAttr(node, "hidden"); hidden {
+			return true
+		}
+		style := strings.Join(strings.Fields(strings.ToLower(getAttr(node, "style"))), "")
+		for _, declaration := range strings.Split(style, ";") {
+			if strings.HasPrefix(declaration, "display:none") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagHeadingIssues checks the outline once the whole page is parsed. Hidden
+// headings are not part of the visible outline, so they do not count as the
+// page's h1 or as the level the next heading is compared with.
+func flagHeadingIssues(headings []models.Heading) {
+	previousLevel := 0
+	seenH1 := false
+
+	for i := range headings {
+		heading := &headings[i]
+		if heading.Text == "" {
+			heading.Issues = append(heading.Issues, models.HeadingEmpty)
+		}
+		if heading.Hidden {
+			heading.Issues = append(heading.Issues, models.HeadingHidden)
+			continue
+		}
+
+		if heading.Level == 1 {
+			if seenH1 {
+				heading.Issues = append(heading.Issues, models.HeadingMultipleH1)
+			}
+			seenH1 = true
+		}
+		if previousLevel > 0 && heading.Level > previousLevel+1 {
+			heading.Issues = append(heading.Issues, models.HeadingSkippedLevel)
+		}
+		previousLevel = heading.Level
+	}
+}
+
+// countHeadingIssues returns the number of headings with at least one issue
+func countHeadingIssues(headings []models.Heading) int {
+	count := 0
+	for _, heading := range headings {
+		if len(heading.Issues) > 0 {
+			count++
+		}
+	}
+	return count
+}
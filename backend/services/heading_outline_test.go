@@ -0,0 +1,67 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"web-crawler/models"
+)
+
+func TestHTMLParser_HeadingOutline(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><body>
+		<h1>Guide to
+			crawling</h1>
+		<h2>Fetching</h2>
+		<h4>Timeouts <script>track()</script></h4>
+		<div hidden><h2>Old section</h2></div>
+		<h3>Retries</h3>
+		<section style="color: red; DISPLAY : none"><h1>Mobile title</h1></section>
+		<h2><img src="/parsing.png" alt="Parsing"></h2>
+		<h3 aria-label="Links"></h3>
+		<h3>  </h3>
+		<h1>Appendix</h1>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com/guide")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	type expected struct {
+		level  int
+		text   string
+		hidden bool
+		issues []models.HeadingIssue
+	}
+	want := []expected{
+		{1, "Guide to crawling", false, []models.HeadingIssue{}},
+		{2, "Fetching", false, []models.HeadingIssue{}},
+		{4, "Timeouts", false, []models.HeadingIssue{models.HeadingSkippedLevel}},
+		{2, "Old section", true, []models.HeadingIssue{models.HeadingHidden}},
+		// Compared with the visible h4, not the hidden h2
+		{3, "Retries", false, []models.HeadingIssue{}},
+		{1, "Mobile title", true, []models.HeadingIssue{models.HeadingHidden}},
+		{2, "Parsing", false, []models.HeadingIssue{}},
+		{3, "Links", false, []models.HeadingIssue{}},
+		{3, "", false, []models.HeadingIssue{models.HeadingEmpty}},
+		{1, "Appendix", false, []models.HeadingIssue{models.HeadingMultipleH1}},
+	}
+
+	if len(result.Headings) != len(want) {
+		t.Fatalf("Expected %d headings, got %d: %+v", len(want), len(result.Headings), result.Headings)
+	}
+	for i, heading := range result.Headings {
+		w := want[i]
+		if heading.Level != w.level || heading.Text != w.text || heading.Hidden != w.hidden || !reflect.DeepEqual(heading.Issues, w.issues) {
+			t.Errorf("Heading %d: expected %+v, got %+v", i, w, heading)
+		}
+	}
+	if got := countHeadingIssues(result.Headings); got != 5 {
+		t.Errorf("Expected 5 headings with issues, got %d", got)
+	}
+	if result.HeadingCounts["h1"] != 3 || result.HeadingCounts["h3"] != 3 {
+		t.Errorf("Expected the counts to include hidden and empty headings, got %v", result.HeadingCounts)
+	}
+}
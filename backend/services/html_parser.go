@@ -6,9 +6,20 @@ import (
 	"regexp"
 	"strings"
 
+	"web-crawler/models"
+
 	"golang.org/x/net/html"
 )
 
+// PageParser extracts page data from fetched HTML.
+// HTMLParser is the implementation; tests can substitute their own.
+type PageParser interface {
+	Parse(htmlContent string, baseURL string) (*ParsedData, error)
+}
+
+// Compile-time check that HTMLParser satisfies the interface
+var _ PageParser = (*HTMLParser)(nil)
+
 // HTMLParser handles HTML analysis and data extraction
 type HTMLParser struct{}
 
@@ -19,21 +30,87 @@ func NewHTMLParser() *HTMLParser {
 
 // ParsedData contains all extracted information from an HTML page
 type ParsedData struct {
-	HTMLVersion   *string        `json:"html_version"`   // DOCTYPE analysis
-	PageTitle     *string        `json:"page_title"`     // <title> tag content
-	HeadingCounts map[string]int `json:"heading_counts"` // h1-h6 counts
-	InternalLinks []LinkInfo     `json:"internal_links"` // same domain links
-	ExternalLinks []LinkInfo     `json:"external_links"` // external domain links
-	HasLoginForm  bool           `json:"has_login_form"` // form with password input
-	ParseErrors   []string       `json:"parse_errors"`   // non-fatal parse issues
+	HTMLVersion     *string              `json:"html_version"`     // DOCTYPE analysis
+	PageTitle       *string              `json:"page_title"`       // <title> tag content
+	Meta            PageMeta             `json:"meta"`             // description, robots, canonical, social tags
+	HeadingCounts   map[string]int       `json:"heading_counts"`   // h1-h6 counts
+	Headings        []models.Heading     `json:"headings"`         // h1-h6 in document order, with outline issues
+	InternalLinks   []LinkInfo           `json:"internal_links"`   // same domain links
+	ExternalLinks   []LinkInfo           `json:"external_links"`   // external domain links
+	Images          []ImageInfo          `json:"images"`           // <img> elements in document order
+	StructuredData  []StructuredDataInfo `json:"structured_data"`  // JSON-LD, microdata and RDFa items in document order
+	Forms           []FormInfo           `json:"forms"`            // forms in document order, then controls outside any form
+	LoginConfidence int                  `json:"login_confidence"` // 0-100 likelihood that the page has a login
+	LoginSignals    []models.LoginSignal `json:"login_signals"`    // evidence behind LoginConfidence
+	TextContent     string               `json:"text_content"`     // visible text, whitespace-collapsed
+	ParseErrors     []string             `json:"parse_errors"`     // non-fatal parse issues
+
+	linkIndex map[string]int // Normalized URL -> index in InternalLinks or ExternalLinks
 }
 
-// LinkInfo contains information about a discovered link
+// HasLoginForm reports whether the page likely has a login form
+func (d *ParsedData) HasLoginForm() bool {
+	return d.LoginConfidence >= models.LoginFormThreshold
+}
+
+// maxAnchorTexts bounds the distinct anchor texts kept for one link
+const maxAnchorTexts = 10
+
+// LinkInfo contains information about a discovered link. Repeated links to
+// the same normalized URL are merged into one LinkInfo.
 type LinkInfo struct {
-	URL        string `json:"url"`         // The href URL
-	Text       string `json:"text"`        // Link anchor text or alt text
-	IsImage    bool   `json:"is_image"`    // True if this is an image link
-	IsInternal bool   `json:"is_internal"` // True if internal to domain
+	URL         string   `json:"url"`         // The normalized href URL
+	Text        string   `json:"text"`        // First anchor or image alt text, or the href if the link has none
+	Texts       []string `json:"texts"`       // Distinct anchor texts in document order (up to maxAnchorTexts)
+	Occurrences int      `json:"occurrences"` // Number of <a> elements pointing at URL
+	IsImage     bool     `json:"is_image"`    // True if an occurrence wraps an <img>, <picture> or <svg>
+	IsInternal  bool     `json:"is_internal"` // True if internal to domain
+	Region      string   `json:"region"`      // Enclosing landmark (models.LinkRegions), "" if none
+	Rel         []string `json:"rel"`         // Recognized rel keywords (models.LinkRelValues)
+	Target      string   `json:"target"`      // target attribute
+	Title       string   `json:"title"`       // title attribute
+}
+
+// addLink records a link, merging it into an earlier link to the same URL.
+// The merged link keeps the region and attributes of its first occurrence,
+// and is an image link if any occurrence is.
+func (d *ParsedData) addLink(link LinkInfo) {
+	if d.linkIndex == nil {
+		d.linkIndex = make(map[string]int)
+	}
+
+	// A URL is always on the same side, so one index covers both lists
+	links := &d.ExternalLinks
+	if link.IsInternal {
+		links = &d.InternalLinks
+	}
+
+	if i, seen := d.linkIndex[link.URL]; seen {
+		existing := &(*links)[i]
+		existing.Occurrences++
+		existing.IsImage = existing.IsImage || link.IsImage
+		for _, text := range link.Texts {
+			existing.addText(text)
+		}
+		return
+	}
+
+	link.Occurrences = 1
+	d.linkIndex[link.URL] = len(*links)
+	*links = append(*links, link)
+}
+
+// addText adds an anchor text if it is new and the limit is not reached
+func (l *LinkInfo) addText(text string) {
+	if len(l.Texts) >= maxAnchorTexts {
+		return
+	}
+	for _, existing := range l.Texts {
+		if existing == text {
+			return
+		}
+	}
+	l.Texts = append(l.Texts, text)
 }
 
 // Parse analyzes HTML content and extracts all required data
@@ -43,11 +120,15 @@ func (p *HTMLParser) Parse(htmlContent string, baseURL string) (*ParsedData, err
 		len(htmlContent), htmlContent[:min(200, len(htmlContent))])
 
 	data := &ParsedData{
-		HeadingCounts: make(map[string]int),
-		InternalLinks: make([]LinkInfo, 0),
-		ExternalLinks: make([]LinkInfo, 0),
-		ParseErrors:   make([]string, 0),
-		HasLoginForm:  false,
+		HeadingCounts:  make(map[string]int),
+		Headings:       make([]models.Heading, 0),
+		InternalLinks:  make([]LinkInfo, 0),
+		ExternalLinks:  make([]LinkInfo, 0),
+		Images:         make([]ImageInfo, 0),
+		StructuredData: make([]StructuredDataInfo, 0),
+		ParseErrors:    make([]string, 0),
+		Forms:          make([]FormInfo, 0),
+		LoginSignals:   make([]models.LoginSignal, 0),
 	}
 
 	// Parse base URL for link categorization
@@ -71,45 +152,120 @@ func (p *HTMLParser) Parse(htmlContent string, baseURL string) (*ParsedData, err
 	}
 
 	// Extract data by traversing the DOM tree
-	p.traverseNode(doc, data, baseDomain, baseURL)
+	p.traverseNode(doc, data, baseDomain, baseURL, "")
+	gradeImageAlts(data.Images)
+	flagHeadingIssues(data.Headings)
+	data.Forms = p.extractForms(doc, baseURL)
+	data.LoginConfidence, data.LoginSignals = pageLogin(data.Forms, p.hasOAuthButton(doc))
+	data.TextContent = p.extractVisibleText(doc)
 
 	return data, nil
 }
 
-// traverseNode recursively walks through the HTML DOM tree
-func (p *HTMLParser) traverseNode(n *html.Node, data *ParsedData, baseDomain, baseURL string) {
+// traverseNode recursively walks through the HTML DOM tree. region is the
+// innermost landmark containing n.
+func (p *HTMLParser) traverseNode(n *html.Node, data *ParsedData, baseDomain, baseURL, region string) {
 	if n.Type == html.ElementNode {
+		if landmark := landmarkRegion(n); landmark != "" {
+			region = landmark
+		}
+		if syntax, ok := structuredDataSyntax(n); ok {
+			data.addStructuredData(p.extractItem(n, syntax, baseURL))
+		}
+
 		switch strings.ToLower(n.Data) {
 		case "title":
 			if title := p.extractTextContent(n); title != "" {
 				data.PageTitle = &title
 			}
 
+		case "meta":
+			p.addMeta(&data.Meta, n, baseURL)
+
+		case "script":
+			if strings.EqualFold(strings.TrimSpace(getAttr(n, "type")), "application/ld+json") {
+				item := p.extractJSONLD(n)
+				if item.Error != "" {
+					data.ParseErrors = append(data.ParseErrors, item.Error)
+				}
+				data.addStructuredData(item)
+			}
+
+		case "link":
+			p.addCanonical(&data.Meta, n, baseURL)
+
 		case "h1", "h2", "h3", "h4", "h5", "h6":
 			data.HeadingCounts[strings.ToLower(n.Data)]++
+			data.addHeading(p.extractHeading(n))
 
 		case "a":
 			if linkInfo := p.extractLinkInfo(n, baseDomain, baseURL); linkInfo != nil {
-				if linkInfo.IsInternal {
-					data.InternalLinks = append(data.InternalLinks, *linkInfo)
-				} else {
-					data.ExternalLinks = append(data.ExternalLinks, *linkInfo)
-				}
+				linkInfo.Region = region
+				data.addLink(*linkInfo)
 			}
 
-		case "form":
-			if p.hasPasswordInput(n) {
-				data.HasLoginForm = true
-			}
+		case "img":
+			data.Images = append(data.Images, p.extractImageInfo(n, baseURL))
 		}
 	}
 
 	// Recursively process child nodes
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		p.traverseNode(c, data, baseDomain, baseURL)
+		p.traverseNode(c, data, baseDomain, baseURL, region)
 	}
 }
 
+// landmarkRoles maps ARIA landmark roles to link regions
+var landmarkRoles = map[string]string{
+	"banner":        "header",
+	"navigation":    "nav",
+	"main":          "main",
+	"complementary": "aside",
+	"contentinfo":   "footer",
+}
+
+// landmarkRegion returns the link region an element starts, or "" if it is not a landmark.
+// An explicit ARIA role takes precedence over the element name.
+func landmarkRegion(n *html.Node) string {
+	for _, role := range strings.Fields(strings.ToLower(getAttr(n, "role"))) {
+		if region, ok := landmarkRoles[role]; ok {
+			return region
+		}
+	}
+
+	switch tag := strings.ToLower(n.Data); tag {
+	case "header", "nav", "main", "aside", "footer":
+		return tag
+	}
+	return ""
+}
+
+// getAttr returns the value of an attribute, or "" if it is missing
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if strings.ToLower(attr.Key) == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// linkRel returns the recognized keywords of a rel attribute, in models.LinkRelValues order
+func linkRel(value string) []string {
+	present := make(map[string]bool)
+	for _, keyword := range strings.Fields(strings.ToLower(value)) {
+		present[keyword] = true
+	}
+
+	var rel []string
+	for _, keyword := range models.LinkRelValues {
+		if present[keyword] {
+			rel = append(rel, keyword)
+		}
+	}
+	return rel
+}
+
 // extractTextContent extracts the text content from a node and its children
 func (p *HTMLParser) extractTextContent(n *html.Node) string {
 	var text strings.Builder
@@ -128,6 +284,31 @@ func (p *HTMLParser) extractTextContent(n *html.Node) string {
 	return strings.TrimSpace(text.String())
 }
 
+// extractVisibleText extracts the human-visible text of a document, skipping
+// scripts, styles and other non-rendered elements, with whitespace collapsed
+func (p *HTMLParser) extractVisibleText(n *html.Node) string {
+	var words []string
+
+	var extractText func(*html.Node)
+	extractText = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			switch strings.ToLower(node.Data) {
+			case "script", "style", "noscript", "template", "head":
+				return
+			}
+		}
+		if node.Type == html.TextNode {
+			words = append(words, strings.Fields(node.Data)...)
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			extractText(c)
+		}
+	}
+
+	extractText(n)
+	return strings.Join(words, " ")
+}
+
 // extractLinkInfo extracts link information from an anchor tag
 func (p *HTMLParser) extractLinkInfo(n *html.Node, baseDomain, baseURL string) *LinkInfo {
 	var href string
@@ -145,9 +326,17 @@ func (p *HTMLParser) extractLinkInfo(n *html.Node, baseDomain, baseURL string) *
 		return nil
 	}
 
-	// Extract link text
-	linkText := p.extractTextContent(n)
-	if linkText == "" {
+	// Extract link text, with whitespace collapsed so repeats compare equal.
+	// Image links without text are named by the image's alternative text.
+	image := findLinkImage(n)
+	linkText := strings.Join(strings.Fields(p.extractLinkText(n)), " ")
+	if linkText == "" && image != nil {
+		linkText = imageLinkText(n, image)
+	}
+	var texts []string
+	if linkText != "" {
+		texts = []string{linkText}
+	} else {
 		linkText = href // Fallback to URL if no text
 	}
 
@@ -163,12 +352,90 @@ func (p *HTMLParser) extractLinkInfo(n *html.Node, baseDomain, baseURL string) *
 	return &LinkInfo{
 		URL:        resolvedURL,
 		Text:       linkText,
-		IsImage:    false, // Could be enhanced to detect image links
+		Texts:      texts,
+		IsImage:    image != nil,
 		IsInternal: isInternal,
+		Rel:        linkRel(getAttr(n, "rel")),
+		Target:     strings.TrimSpace(getAttr(n, "target")),
+		Title:      strings.Join(strings.Fields(getAttr(n, "title")), " "),
+	}
+}
+
+// extractLinkText extracts the visible text of a link. Text inside an <svg>
+// (its <title> or <text> elements) is part of the graphic and is skipped.
+func (p *HTMLParser) extractLinkText(n *html.Node) string {
+	var text strings.Builder
+
+	var extractText func(*html.Node)
+	extractText = func(node *html.Node) {
+		if node.Type == html.TextNode {
+			text.WriteString(node.Data)
+		}
+		if node.Type == html.ElementNode && strings.ToLower(node.Data) == "svg" {
+			return
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			extractText(c)
+		}
+	}
+
+	extractText(n)
+	return strings.TrimSpace(text.String())
+}
+
+// findLinkImage returns the first <img>, <picture> or <svg> inside a link, or nil
+func findLinkImage(n *html.Node) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch strings.ToLower(c.Data) {
+		case "img", "picture", "svg":
+			return c
+		}
+		if image := findLinkImage(c); image != nil {
+			return image
+		}
+	}
+	return nil
+}
+
+// imageLinkText names an image link from its alt, aria-label or title, looking
+// at the image before the anchor. It returns "" if none is set.
+func imageLinkText(anchor, image *html.Node) string {
+	// A <picture> carries its alt text on the <img> inside it
+	if strings.ToLower(image.Data) == "picture" {
+		if img := findLinkImage(image); img != nil {
+			image = img
+		}
+	}
+
+	candidates := []string{
+		getAttr(image, "alt"),
+		getAttr(image, "aria-label"),
+		getAttr(anchor, "aria-label"),
+		getAttr(image, "title"),
+		getAttr(anchor, "title"),
+	}
+	// An <svg> is titled by its <title> element
+	if strings.ToLower(image.Data) == "svg" {
+		for c := image.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && strings.ToLower(c.Data) == "title" && c.FirstChild != nil {
+				candidates = append(candidates, c.FirstChild.Data)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if text := strings.Join(strings.Fields(candidate), " "); text != "" {
+			return text
+		}
 	}
+	return ""
 }
 
-// resolveURL resolves relative URLs against the base URL
+// resolveURL resolves relative URLs against the base URL and normalizes the
+// result, so different spellings of the same target compare equal
 func (p *HTMLParser) resolveURL(href, baseURL string) string {
 	// Parse base URL
 	base, err := url.Parse(baseURL)
@@ -184,7 +451,26 @@ func (p *HTMLParser) resolveURL(href, baseURL string) string {
 
 	// Resolve relative URL
 	resolved := base.ResolveReference(ref)
-	return resolved.String()
+	return normalizeLinkURL(resolved)
+}
+
+// normalizeLinkURL drops the fragment and default port, lower-cases the host
+// and gives an empty path a trailing slash
+func normalizeLinkURL(u *url.URL) string {
+	normalized := *u
+	normalized.Fragment = ""
+	normalized.RawFragment = ""
+	normalized.Host = strings.ToLower(normalized.Host)
+
+	port := normalized.Port()
+	if (normalized.Scheme == "http" && port == "80") || (normalized.Scheme == "https" && port == "443") {
+		normalized.Host = strings.TrimSuffix(normalized.Host, ":"+port)
+	}
+	if normalized.Path == "" && normalized.Host != "" && normalized.Opaque == "" {
+		normalized.Path = "/"
+	}
+
+	return normalized.String()
 }
 
 // isInternalLink determines if a link is internal to the base domain
@@ -198,30 +484,6 @@ func (p *HTMLParser) isInternalLink(linkURL, baseDomain string) bool {
 	return linkDomain == baseDomain
 }
 
-// hasPasswordInput checks if a form contains a password input
-func (p *HTMLParser) hasPasswordInput(formNode *html.Node) bool {
-	var hasPassword bool
-
-	var checkInputs func(*html.Node)
-	checkInputs = func(n *html.Node) {
-		if n.Type == html.ElementNode && strings.ToLower(n.Data) == "input" {
-			for _, attr := range n.Attr {
-				if strings.ToLower(attr.Key) == "type" && strings.ToLower(attr.Val) == "password" {
-					hasPassword = true
-					return
-				}
-			}
-		}
-
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			checkInputs(c)
-		}
-	}
-
-	checkInputs(formNode)
-	return hasPassword
-}
-
 // extractHTMLVersion analyzes DOCTYPE declaration to determine HTML version
 func (p *HTMLParser) extractHTMLVersion(htmlContent string) *string {
 	// Convert to lowercase for case-insensitive matching
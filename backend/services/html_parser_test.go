@@ -1,6 +1,7 @@
 package services
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -78,7 +79,7 @@ func TestHTMLParser_Parse(t *testing.T) {
 	}
 
 	// Test login form detection
-	if !result.HasLoginForm {
+	if !result.HasLoginForm() {
 		t.Error("Expected login form to be detected")
 	}
 
@@ -134,6 +135,160 @@ func TestHTMLParser_ParseSimple(t *testing.T) {
 		result.PageTitle, result.HeadingCounts["h1"], len(result.ExternalLinks))
 }
 
+func TestHTMLParser_DeduplicatesLinks(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><body>
+		<nav><a href="/">Home</a> <a href="/docs">Docs</a></nav>
+		<a href="https://EXAMPLE.com:443/docs#intro">Read the
+			docs</a>
+		<a href="/docs">Docs</a>
+		<a href="https://example.com">Home</a>
+		<a href="/docs"><img src="/docs.png"></a>
+		<a href="https://other.example.org/a?x=1">Other</a>
+		<a href="https://other.example.org/a?x=2">Other</a>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.InternalLinks) != 2 {
+		t.Fatalf("Expected 2 distinct internal links, got %+v", result.InternalLinks)
+	}
+
+	home, docs := result.InternalLinks[0], result.InternalLinks[1]
+	if home.URL != "https://example.com/" || home.Occurrences != 2 || len(home.Texts) != 1 {
+		t.Errorf("Unexpected home link: %+v", home)
+	}
+	if docs.URL != "https://example.com/docs" || docs.Occurrences != 4 || docs.Text != "Docs" {
+		t.Errorf("Unexpected docs link: %+v", docs)
+	}
+	expectedTexts := []string{"Docs", "Read the docs"}
+	if len(docs.Texts) != len(expectedTexts) {
+		t.Fatalf("Expected texts %v, got %v", expectedTexts, docs.Texts)
+	}
+	for i, text := range expectedTexts {
+		if docs.Texts[i] != text {
+			t.Errorf("Expected text %q at %d, got %q", text, i, docs.Texts[i])
+		}
+	}
+
+	// Different query strings are different pages
+	if len(result.ExternalLinks) != 2 {
+		t.Errorf("Expected 2 external links, got %+v", result.ExternalLinks)
+	}
+}
+
+func TestHTMLParser_LinkContext(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><body>
+		<header><a href="/">Home</a><nav><a href="/docs">Docs</a></nav></header>
+		<div role="navigation"><a href="/blog">Blog</a></div>
+		<main>
+			<a href="https://ads.example.net/" rel="Sponsored NOFOLLOW noreferrer" target="_blank" title=" Our
+				sponsor ">Ad</a>
+			<aside><a href="/related">Related</a></aside>
+		</main>
+		<div role="contentinfo"><a href="/legal">Legal</a></div>
+		<a href="/loose" rel="external">Loose</a>
+		<footer><a href="/docs" rel="ugc">Docs again</a></footer>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	links := make(map[string]LinkInfo)
+	for _, link := range append(result.InternalLinks, result.ExternalLinks...) {
+		links[link.URL] = link
+	}
+
+	expectedRegions := map[string]string{
+		"https://example.com/":        "header",
+		"https://example.com/docs":    "nav", // First occurrence wins
+		"https://example.com/blog":    "nav",
+		"https://ads.example.net/":    "main",
+		"https://example.com/related": "aside",
+		"https://example.com/legal":   "footer",
+		"https://example.com/loose":   "",
+	}
+	for url, region := range expectedRegions {
+		if links[url].Region != region {
+			t.Errorf("%s: expected region %q, got %q", url, region, links[url].Region)
+		}
+	}
+
+	ad := links["https://ads.example.net/"]
+	if len(ad.Rel) != 2 || ad.Rel[0] != "nofollow" || ad.Rel[1] != "sponsored" {
+		t.Errorf("Expected rel [nofollow sponsored], got %v", ad.Rel)
+	}
+	if ad.Target != "_blank" || ad.Title != "Our sponsor" {
+		t.Errorf("Expected target _blank and title 'Our sponsor', got %q and %q", ad.Target, ad.Title)
+	}
+	if loose := links["https://example.com/loose"]; len(loose.Rel) != 0 {
+		t.Errorf("Expected unrecognized rel values to be dropped, got %v", loose.Rel)
+	}
+}
+
+func TestHTMLParser_ImageLinks(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><body>
+		<a href="/alt"><img src="/a.png" alt=" Company
+			logo "></a>
+		<a href="/labelled" aria-label="Go home"><img src="/b.png"></a>
+		<a href="/titled"><img src="/c.png" title="Gallery"></a>
+		<a href="/picture"><picture><source srcset="/d.webp"><img src="/d.png" alt="Photo"></picture></a>
+		<a href="/icon"><svg viewBox="0 0 10 10"><title>Search</title><path d="M0 0"/></svg></a>
+		<a href="/captioned"><img src="/e.png" alt="Arrow"> Next page</a>
+		<a href="/unnamed"><img src="/f.png"></a>
+		<a href="/text">Plain text</a>
+		<a href="/text"><img src="/g.png" alt="Text icon"></a>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	links := make(map[string]LinkInfo)
+	for _, link := range result.InternalLinks {
+		links[strings.TrimPrefix(link.URL, "https://example.com")] = link
+	}
+
+	expected := map[string]string{
+		"/alt":       "Company logo",
+		"/labelled":  "Go home",
+		"/titled":    "Gallery",
+		"/picture":   "Photo",
+		"/icon":      "Search",
+		"/captioned": "Next page", // Visible text wins over alt text
+		"/unnamed":   "/unnamed",  // Nothing names the image, so the href is used
+	}
+	for path, text := range expected {
+		link := links[path]
+		if !link.IsImage {
+			t.Errorf("%s: expected an image link", path)
+		}
+		if link.Text != text {
+			t.Errorf("%s: expected text %q, got %q", path, text, link.Text)
+		}
+	}
+	if unnamed := links["/unnamed"]; len(unnamed.Texts) != 0 {
+		t.Errorf("Expected no anchor texts for an unnamed image link, got %v", unnamed.Texts)
+	}
+
+	// A link is an image link if any of its occurrences is
+	text := links["/text"]
+	if !text.IsImage || text.Text != "Plain text" || len(text.Texts) != 2 {
+		t.Errorf("Expected a merged image link with 2 texts, got %+v", text)
+	}
+}
+
 func TestHTMLParser_EdgeCases(t *testing.T) {
 	parser := NewHTMLParser()
 
@@ -166,3 +321,30 @@ func TestHTMLParser_EdgeCases(t *testing.T) {
 		t.Error("Empty HTML should not have title")
 	}
 }
+
+func TestHTMLParser_TextContent(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `
+<html>
+<head><title>Ignored Title</title><style>body { color: red; }</style></head>
+<body>
+    <h1>Hello
+        World</h1>
+    <script>var tracking = "ignored";</script>
+    <p>Visible   text</p>
+    <noscript>Enable JavaScript</noscript>
+</body>
+</html>
+`
+
+	result, err := parser.Parse(testHTML, "https://example.com")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := "Hello World Visible text"
+	if result.TextContent != expected {
+		t.Errorf("Expected text content %q, got %q", expected, result.TextContent)
+	}
+}
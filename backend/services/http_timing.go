@@ -0,0 +1,103 @@
+package services
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// FetchTiming breaks down the time spent fetching a URL into network phases.
+// Phases that did not happen (e.g. TLS for plain HTTP, or DNS and connect on a
+// reused keep-alive connection) are left at zero. When redirects are followed,
+// DNS, connect and TLS times are summed over all hops.
+type FetchTiming struct {
+	DNSLookup       time.Duration // Resolving the host name
+	TCPConnect      time.Duration // Establishing the TCP connection
+	TLSHandshake    time.Duration // TLS handshake (HTTPS only)
+	TimeToFirstByte time.Duration // From sending the request to the first response byte
+	Download        time.Duration // Reading the response body
+	Total           time.Duration // Whole fetch, including redirects
+}
+
+// fetchTracer collects httptrace events for a single FetchURL call
+type fetchTracer struct {
+	mu sync.Mutex
+
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+
+	timing FetchTiming
+}
+
+// clientTrace returns the httptrace hooks that feed this tracer
+func (t *fetchTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if !t.dnsStart.IsZero() {
+				t.timing.DNSLookup += time.Since(t.dnsStart)
+			}
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil && !t.connectStart.IsZero() {
+				t.timing.TCPConnect += time.Since(t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if !t.tlsStart.IsZero() {
+				t.timing.TLSHandshake += time.Since(t.tlsStart)
+			}
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.wroteRequest = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Overwritten on every redirect hop, so the final response wins
+			t.firstByte = time.Now()
+			if !t.wroteRequest.IsZero() {
+				t.timing.TimeToFirstByte = t.firstByte.Sub(t.wroteRequest)
+			}
+		},
+	}
+}
+
+// finish records the body download time and total duration
+func (t *fetchTracer) finish(startTime, bodyReadAt time.Time) FetchTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.firstByte.IsZero() {
+		t.timing.Download = bodyReadAt.Sub(t.firstByte)
+	}
+	t.timing.Total = bodyReadAt.Sub(startTime)
+
+	return t.timing
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"web-crawler/models"
+
+	"golang.org/x/net/html"
+)
+
+// ImageInfo describes an <img> on a parsed page
+type ImageInfo struct {
+	Src       string                  `json:"src"`        // Resolved src, "" if missing
+	Srcset    []models.ImageCandidate `json:"srcset"`     // Resolved img srcset, then <source> srcsets of its <picture>
+	InPicture bool                    `json:"in_picture"` // Child of a <picture>
+	HasAlt    bool                    `json:"has_alt"`    // alt attribute present, even if empty
+	Alt       string                  `json:"alt"`        // alt text, whitespace-collapsed
+	AltStatus models.ImageAltStatus   `json:"alt_status"` // Set once the whole page is parsed
+	Width     *int                    `json:"width"`      // width attribute in pixels
+	Height    *int                    `json:"height"`     // height attribute in pixels
+	Lazy      bool                    `json:"lazy"`       // loading="lazy"
+}
+
+// ImageSummary counts a page's images by the issues found on them
+type ImageSummary struct {
+	Total             int
+	MissingAlt        int
+	EmptyAlt          int
+	PoorAlt           int // File name or duplicate alt
+	MissingDimensions int
+	Lazy              int
+}
+
+// imageFilenamePatterns match alt texts that are file names or camera defaults
+var imageFilenamePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^\S+\.(png|jpe?g|gif|webp|avif|svg|bmp|tiff?|ico)$`),
+	regexp.MustCompile(`(?i)^(img|dsc|dscn|dscf|pxl|image|photo|screenshot)[-_ ]?\d+`),
+}
+
+// extractImageInfo inventories an <img> element
+func (p *HTMLParser) extractImageInfo(n *html.Node, baseURL string) ImageInfo {
+	image := ImageInfo{
+		Src:    p.resolveImageURL(getAttr(n, "src"), baseURL),
+		Srcset: p.resolveSrcset(getAttr(n, "srcset"), baseURL),
+		Width:  imageDimension(getAttr(n, "width")),
+		Height: imageDimension(getAttr(n, "height")),
+		Lazy:   strings.EqualFold(strings.TrimSpace(getAttr(n, "loading")), "lazy"),
+	}
+
+	if alt, ok := lookupAttr(n, "alt"); ok {
+		image.HasAlt = true
+		image.Alt = strings.Join(strings.Fields(alt), " ")
+	}
+
+	// Sources of a <picture> are alternatives for the <img> inside it
+	if n.Parent != nil && n.Parent.Type == html.ElementNode && strings.ToLower(n.Parent.Data) == "picture" {
+		image.InPicture = true
+		for c := n.Parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && strings.ToLower(c.Data) == "source" {
+				image.Srcset = append(image.Srcset, p.resolveSrcset(getAttr(c, "srcset"), baseURL)...)
+			}
+		}
+	}
+
+	return image
+}
+
+// resolveImageURL resolves an image URL against the page. Data URIs are reduced
+// to their media type, e.g. "data:image/png", so inline images are not stored.
+func (p *HTMLParser) resolveImageURL(raw, baseURL string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	if len(raw) >= 5 && strings.EqualFold(raw[:5], "data:") {
+		mediaType := raw[5:]
+		if end := strings.IndexAny(mediaType, ";,"); end >= 0 {
+			mediaType = mediaType[:end]
+		}
+		return "data:" + strings.ToLower(mediaType)
+	}
+
+	return p.resolveURL(raw, baseURL)
+}
+
+// resolveSrcset parses a srcset attribute and resolves its candidate URLs
+func (p *HTMLParser) resolveSrcset(value, baseURL string) []models.ImageCandidate {
+	candidates := parseSrcset(value)
+	resolved := candidates[:0]
+	for _, candidate := range candidates {
+		if candidate.URL = p.resolveImageURL(candidate.URL, baseURL); candidate.URL != "" {
+			resolved = append(resolved, candidate)
+		}
+	}
+	return resolved
+}
+
+// parseSrcset splits a srcset attribute into candidates. A URL runs up to the
+// next whitespace, and a comma at its end closes the candidate without a descriptor.
+func parseSrcset(value string) []models.ImageCandidate {
+	var candidates []models.ImageCandidate
+	rest := value
+	for {
+		rest = strings.TrimLeft(rest, " \t\n\r\f,")
+		if rest == "" {
+			return candidates
+		}
+
+		end := strings.IndexAny(rest, " \t\n\r\f")
+		if end < 0 {
+			end = len(rest)
+		}
+		candidate := models.ImageCandidate{URL: rest[:end]}
+		rest = rest[end:]
+
+		if trimmed := strings.TrimRight(candidate.URL, ","); trimmed != candidate.URL {
+			candidate.URL = trimmed
+		} else {
+			comma := strings.IndexByte(rest, ',')
+			if comma < 0 {
+				comma = len(rest)
+			}
+			candidate.Descriptor = strings.Join(strings.Fields(rest[:comma]), " ")
+			rest = rest[comma:]
+		}
+
+		candidates = append(candidates, candidate)
+	}
+}
+
+// imageDimension parses a width or height attribute in pixels. Values such as
+// "100%" or "auto" are not dimensions and return nil.
+func imageDimension(value string) *int {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "px")
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return nil
+	}
+	return &size
+}
+
+// gradeImageAlts sets the alt status of every image. An alt text is a duplicate
+// when the page uses it for more than one distinct image; the same image repeated
+// with the same alt is fine.
+func gradeImageAlts(images []ImageInfo) {
+	sources := make(map[string]map[string]bool)
+	for _, image := range images {
+		if image.Alt == "" {
+			continue
+		}
+		key := strings.ToLower(image.Alt)
+		if sources[key] == nil {
+			sources[key] = make(map[string]bool)
+		}
+		sources[key][image.source()] = true
+	}
+
+	for i := range images {
+		image := &images[i]
+		switch {
+		case !image.HasAlt:
+			image.AltStatus = models.AltMissing
+		case image.Alt == "":
+			image.AltStatus = models.AltEmpty
+		case isFilenameAlt(image.Alt, image.source()):
+			image.AltStatus = models.AltFilename
+		case len(sources[strings.ToLower(image.Alt)]) > 1:
+			image.AltStatus = models.AltDuplicate
+		default:
+			image.AltStatus = models.AltOK
+		}
+	}
+}
+
+// source identifies the image file, falling back to the first srcset candidate
+func (i *ImageInfo) source() string {
+	if i.Src == "" && len(i.Srcset) > 0 {
+		return i.Srcset[0].URL
+	}
+	return i.Src
+}
+
+// isFilenameAlt reports whether an alt text is a file name, or repeats the
+// name of the image file without its extension
+func isFilenameAlt(alt, src string) bool {
+	for _, pattern := range imageFilenamePatterns {
+		if pattern.MatchString(alt) {
+			return true
+		}
+	}
+
+	if src == "" || strings.HasPrefix(src, "data:") {
+		return false
+	}
+	name := path.Base(strings.SplitN(src, "?", 2)[0])
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return name != "" && name != "/" && strings.EqualFold(alt, name)
+}
+
+// summarizeImages counts a page's images by the issues found on them
+func summarizeImages(images []ImageInfo) ImageSummary {
+	summary := ImageSummary{Total: len(images)}
+	for _, image := range images {
+		switch image.AltStatus {
+		case models.AltMissing:
+			summary.MissingAlt++
+		case models.AltEmpty:
+			summary.EmptyAlt++
+		case models.AltFilename, models.AltDuplicate:
+			summary.PoorAlt++
+		}
+		if image.Width == nil || image.Height == nil {
+			summary.MissingDimensions++
+		}
+		if image.Lazy {
+			summary.Lazy++
+		}
+	}
+	return summary
+}
+
+// lookupAttr returns an attribute's value and whether it is present
+func lookupAttr(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if strings.ToLower(attr.Key) == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
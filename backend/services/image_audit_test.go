@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+
+	"web-crawler/models"
+)
+
+func TestHTMLParser_ImageInventory(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><body>
+		<img src="/logo.png" alt="Company logo" width="120" height="40">
+		<img src="/photos/team.jpg?v=2" alt=" team ">
+		<img src="/banner.jpg" srcset="/banner-640.jpg 640w, /banner-1280.jpg 1280w" alt="" loading="LAZY">
+		<img src="/hero.jpg" alt="IMG_0042.JPG" width="100%" height="300px">
+		<img src="/a.png" alt="Product photo">
+		<img src="/b.png" alt="product  photo">
+		<img src="/logo.png" alt="Company logo">
+		<img src="data:image/png;base64,iVBORw0KGgo=" alt="Spacer dot">
+		<picture>
+			<source srcset="/card.avif 1x, /card@2x.avif 2x" type="image/avif">
+			<source srcset="/card.webp">
+			<img src="/card.jpg">
+		</picture>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com/page")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	images := result.Images
+	if len(images) != 9 {
+		t.Fatalf("Expected 9 images, got %d", len(images))
+	}
+
+	expected := []struct {
+		src    string
+		status models.ImageAltStatus
+	}{
+		{"https://example.com/logo.png", models.AltOK}, // Repeated with the same alt
+		{"https://example.com/photos/team.jpg?v=2", models.AltFilename},
+		{"https://example.com/banner.jpg", models.AltEmpty},
+		{"https://example.com/hero.jpg", models.AltFilename},
+		{"https://example.com/a.png", models.AltDuplicate},
+		{"https://example.com/b.png", models.AltDuplicate},
+		{"https://example.com/logo.png", models.AltOK},
+		{"data:image/png", models.AltOK},
+		{"https://example.com/card.jpg", models.AltMissing},
+	}
+	for i, want := range expected {
+		if images[i].Src != want.src || images[i].AltStatus != want.status {
+			t.Errorf("Image %d: expected %s (%s), got %s (%s)", i, want.src, want.status, images[i].Src, images[i].AltStatus)
+		}
+	}
+
+	if logo := images[0]; logo.Width == nil || *logo.Width != 120 || logo.Height == nil || *logo.Height != 40 {
+		t.Errorf("Expected logo dimensions 120x40, got %v x %v", logo.Width, logo.Height)
+	}
+	if hero := images[3]; hero.Width != nil || hero.Height == nil || *hero.Height != 300 {
+		t.Errorf("Expected a percentage width to be ignored and height 300, got %v x %v", hero.Width, hero.Height)
+	}
+
+	banner := images[2]
+	if !banner.HasAlt || !banner.Lazy || len(banner.Srcset) != 2 ||
+		banner.Srcset[1] != (models.ImageCandidate{URL: "https://example.com/banner-1280.jpg", Descriptor: "1280w"}) {
+		t.Errorf("Unexpected banner image: %+v", banner)
+	}
+
+	card := images[8]
+	if !card.InPicture || len(card.Srcset) != 3 || card.Srcset[1].Descriptor != "2x" || card.Srcset[2].URL != "https://example.com/card.webp" {
+		t.Errorf("Expected the picture sources on the card image, got %+v", card)
+	}
+
+	summary := summarizeImages(images)
+	if summary != (ImageSummary{Total: 9, MissingAlt: 1, EmptyAlt: 1, PoorAlt: 4, MissingDimensions: 8, Lazy: 1}) {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []models.ImageCandidate
+	}{
+		{"", nil},
+		{"/a.jpg", []models.ImageCandidate{{URL: "/a.jpg"}}},
+		{" /a.jpg 1x ,/b.jpg  2x ", []models.ImageCandidate{{URL: "/a.jpg", Descriptor: "1x"}, {URL: "/b.jpg", Descriptor: "2x"}}},
+		{"/a.jpg, /b.jpg 480w", []models.ImageCandidate{{URL: "/a.jpg"}, {URL: "/b.jpg", Descriptor: "480w"}}},
+		{"/img,v=1.jpg 2x", []models.ImageCandidate{{URL: "/img,v=1.jpg", Descriptor: "2x"}}},
+	}
+
+	for _, tt := range tests {
+		got := parseSrcset(tt.value)
+		if len(got) != len(tt.expected) {
+			t.Errorf("parseSrcset(%q): expected %v, got %v", tt.value, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("parseSrcset(%q)[%d]: expected %v, got %v", tt.value, i, tt.expected[i], got[i])
+			}
+		}
+	}
+}
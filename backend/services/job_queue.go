@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"web-crawler/models"
+)
+
+var (
+	// ErrQueueFull is returned when the queue is at capacity
+	ErrQueueFull = errors.New("crawl queue is full")
+
+	// ErrQueueClosed is returned when adding to a queue that has been shut down
+	ErrQueueClosed = errors.New("crawl queue is closed")
+
+	// ErrInvalidHost is returned when pausing a host that is not a valid host name
+	ErrInvalidHost = errors.New("invalid host")
+)
+
+// priorityWeights sets each priority's share of the worker when all lanes have
+// jobs: out of every 7 jobs, 4 are high, 2 normal and 1 low. Lower priorities
+// are slowed down but never starved.
+var priorityWeights = map[models.CrawlPriority]int{
+	models.PriorityHigh:   4,
+	models.PriorityNormal: 2,
+	models.PriorityLow:    1,
+}
+
+// priorityLane is the FIFO of jobs with one priority
+type priorityLane struct {
+	priority models.CrawlPriority
+	weight   int
+	credit   int // Smooth weighted round-robin state
+	jobs     []*CrawlJob
+}
+
+// jobQueue is a bounded queue of crawl jobs with one FIFO lane per priority.
+// It can be paused globally or per host; paused jobs stay queued and workers
+// skip them until they are resumed.
+type jobQueue struct {
+	mu       sync.Mutex
+	ready    *sync.Cond      // Signalled when a job may have become runnable
+	lanes    []*priorityLane // Highest priority first
+	capacity int
+	closed   bool
+
+	pausedAt    *time.Time           // Set while the whole queue is paused
+	pausedHosts map[string]time.Time // Host -> when it was paused
+}
+
+// newJobQueue creates a queue holding at most capacity jobs
+func newJobQueue(capacity int) *jobQueue {
+	q := &jobQueue{
+		capacity:    capacity,
+		pausedHosts: make(map[string]time.Time),
+	}
+	for _, priority := range models.CrawlPriorities {
+		q.lanes = append(q.lanes, &priorityLane{priority: priority, weight: priorityWeights[priority]})
+	}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+// lane returns the lane for a priority, treating unknown priorities as normal
+func (q *jobQueue) lane(priority models.CrawlPriority) *priorityLane {
+	for _, lane := range q.lanes {
+		if lane.priority == priority {
+			return lane
+		}
+	}
+	return q.lane(models.PriorityNormal)
+}
+
+// push adds a job to the back of the queue without blocking
+func (q *jobQueue) push(job *CrawlJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.lengthLocked() >= q.capacity {
+		return fmt.Errorf("%w (size: %d)", ErrQueueFull, q.capacity)
+	}
+
+	job.host = jobHost(job.URL)
+	lane := q.lane(job.Priority)
+	lane.jobs = append(lane.jobs, job)
+	q.ready.Signal()
+	return nil
+}
+
+// pop waits for a job that is not paused and removes it from the queue. Lanes
+// are picked by smooth weighted round-robin among those with a runnable job,
+// and each lane is served oldest first. It returns false once the queue is
+// closed; jobs still queued are dropped.
+func (q *jobQueue) pop() (*CrawlJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if q.closed {
+			return nil, false
+		}
+		if q.pausedAt == nil {
+			if job := q.takeNext(); job != nil {
+				return job, true
+			}
+		}
+		q.ready.Wait()
+	}
+}
+
+// takeNext removes the next runnable job, or returns nil if every job is paused
+func (q *jobQueue) takeNext() *CrawlJob {
+	var best *priorityLane
+	bestIndex := -1
+	totalWeight := 0
+
+	for _, lane := range q.lanes {
+		index := q.firstRunnable(lane)
+		if index < 0 {
+			continue
+		}
+		lane.credit += lane.weight
+		totalWeight += lane.weight
+		if best == nil || lane.credit > best.credit {
+			best, bestIndex = lane, index
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	best.credit -= totalWeight
+	job := best.jobs[bestIndex]
+	best.jobs = append(best.jobs[:bestIndex], best.jobs[bestIndex+1:]...)
+
+	// An idle lane starts from scratch rather than saving up credit
+	for _, lane := range q.lanes {
+		if len(lane.jobs) == 0 {
+			lane.credit = 0
+		}
+	}
+	return job
+}
+
+// firstRunnable returns the index of the oldest job in a lane whose host is not paused, or -1
+func (q *jobQueue) firstRunnable(lane *priorityLane) int {
+	for i, job := range lane.jobs {
+		if _, paused := q.pausedHosts[job.host]; !paused {
+			return i
+		}
+	}
+	return -1
+}
+
+// close wakes up waiting workers and rejects further jobs
+func (q *jobQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.ready.Broadcast()
+}
+
+// len returns the number of queued jobs, including paused ones
+func (q *jobQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.lengthLocked()
+}
+
+// lengthLocked counts the jobs in all lanes; the caller holds q.mu
+func (q *jobQueue) lengthLocked() int {
+	total := 0
+	for _, lane := range q.lanes {
+		total += len(lane.jobs)
+	}
+	return total
+}
+
+// depthByPriority returns the number of queued jobs in each lane
+func (q *jobQueue) depthByPriority() map[models.CrawlPriority]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	depths := make(map[models.CrawlPriority]int, len(q.lanes))
+	for _, lane := range q.lanes {
+		depths[lane.priority] = len(lane.jobs)
+	}
+	return depths
+}
+
+// pause stops workers from taking new jobs. It returns false if already paused.
+func (q *jobQueue) pause() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pausedAt != nil {
+		return false
+	}
+	now := time.Now()
+	q.pausedAt = &now
+	return true
+}
+
+// resume lets workers take jobs again. It returns false if the queue was not paused.
+func (q *jobQueue) resume() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pausedAt == nil {
+		return false
+	}
+	q.pausedAt = nil
+	q.ready.Broadcast()
+	return true
+}
+
+// pauseHost holds back jobs for one host. It returns false if the host was already paused.
+func (q *jobQueue) pauseHost(host string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, paused := q.pausedHosts[host]; paused {
+		return false
+	}
+	q.pausedHosts[host] = time.Now()
+	return true
+}
+
+// resumeHost releases a host's jobs. It returns false if the host was not paused.
+func (q *jobQueue) resumeHost(host string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, paused := q.pausedHosts[host]; !paused {
+		return false
+	}
+	delete(q.pausedHosts, host)
+	q.ready.Broadcast()
+	return true
+}
+
+// restorePauses re-applies pauses saved by a previous process
+func (q *jobQueue) restorePauses(pauses []models.QueuePause) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, pause := range pauses {
+		pausedAt := pause.PausedAt
+		if pause.Host == models.AllHosts {
+			q.pausedAt = &pausedAt
+		} else {
+			q.pausedHosts[pause.Host] = pausedAt
+		}
+	}
+}
+
+// PausedHost is a host whose jobs are held back
+type PausedHost struct {
+	Host       string    `json:"host"`
+	PausedAt   time.Time `json:"paused_at"`
+	QueuedJobs int       `json:"queued_jobs"` // Jobs waiting for the host to be resumed
+}
+
+// pauseState returns when the whole queue was paused (nil if it is not) and the paused hosts, sorted by host
+func (q *jobQueue) pauseState() (*time.Time, []PausedHost) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	hosts := make([]PausedHost, 0, len(q.pausedHosts))
+	for host, pausedAt := range q.pausedHosts {
+		queued := 0
+		for _, lane := range q.lanes {
+			for _, job := range lane.jobs {
+				if job.host == host {
+					queued++
+				}
+			}
+		}
+		hosts = append(hosts, PausedHost{Host: host, PausedAt: pausedAt, QueuedJobs: queued})
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Host < hosts[j].Host })
+
+	return q.pausedAt, hosts
+}
+
+// jobHost returns the lower-cased host name a job will fetch from
+func jobHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// NormalizeHost turns a host name or URL into the form hosts are paused by
+func NormalizeHost(input string) (string, error) {
+	rawURL := strings.TrimSpace(input)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	host := jobHost(rawURL)
+	if host == "" || strings.ContainsAny(host, " /?#@") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidHost, input)
+	}
+	return host, nil
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+)
+
+// popWithin pops a job, failing the test if none is available in time
+func popWithin(t *testing.T, q *jobQueue, timeout time.Duration) *CrawlJob {
+	t.Helper()
+
+	popped := make(chan *CrawlJob, 1)
+	go func() {
+		job, _ := q.pop()
+		popped <- job
+	}()
+
+	select {
+	case job := <-popped:
+		return job
+	case <-time.After(timeout):
+		t.Fatal("Expected a job to be available")
+		return nil
+	}
+}
+
+// expectNoPop checks that pop blocks, then closes the queue to release the waiting goroutine
+func expectNoPop(t *testing.T, q *jobQueue) {
+	t.Helper()
+
+	popped := make(chan *CrawlJob, 1)
+	go func() {
+		job, _ := q.pop()
+		popped <- job
+	}()
+
+	select {
+	case job := <-popped:
+		t.Fatalf("Expected pop to block, got job for %s", job.URL)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	q.close()
+	if job := <-popped; job != nil {
+		t.Errorf("Expected no job after close, got %s", job.URL)
+	}
+}
+
+func TestJobQueue_FIFOAndCapacity(t *testing.T) {
+	q := newJobQueue(2)
+
+	if err := q.push(&CrawlJob{URLID: 1, URL: "https://a.example.com/"}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if err := q.push(&CrawlJob{URLID: 2, URL: "https://b.example.com/"}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if err := q.push(&CrawlJob{URLID: 3, URL: "https://c.example.com/"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+
+	for _, expected := range []uint{1, 2} {
+		if job := popWithin(t, q, time.Second); job.URLID != expected {
+			t.Errorf("Expected URL ID=%d, got %d", expected, job.URLID)
+		}
+	}
+
+	q.close()
+	if err := q.push(&CrawlJob{URLID: 4, URL: "https://d.example.com/"}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+}
+
+func TestJobQueue_PriorityLanes(t *testing.T) {
+	q := newJobQueue(30)
+
+	// A job queued later with a higher priority is served first
+	q.push(&CrawlJob{URLID: 1, URL: "https://example.com/low", Priority: models.PriorityLow})
+	q.push(&CrawlJob{URLID: 2, URL: "https://example.com/high", Priority: models.PriorityHigh})
+	if job := popWithin(t, q, time.Second); job.URLID != 2 {
+		t.Fatalf("Expected the high priority job first, got URL ID=%d", job.URLID)
+	}
+	if job := popWithin(t, q, time.Second); job.URLID != 1 {
+		t.Fatalf("Expected the low priority job next, got URL ID=%d", job.URLID)
+	}
+
+	// Jobs without a priority go to the normal lane
+	for i := uint(0); i < 8; i++ {
+		q.push(&CrawlJob{URLID: 100 + i, URL: "https://example.com/h", Priority: models.PriorityHigh})
+		q.push(&CrawlJob{URLID: 200 + i, URL: "https://example.com/n"})
+		q.push(&CrawlJob{URLID: 300 + i, URL: "https://example.com/l", Priority: models.PriorityLow})
+	}
+
+	depths := q.depthByPriority()
+	for _, priority := range models.CrawlPriorities {
+		if depths[priority] != 8 {
+			t.Errorf("Expected 8 %s jobs queued, got %d", priority, depths[priority])
+		}
+	}
+
+	// With every lane busy, each round of 7 jobs is split 4:2:1
+	served := make(map[models.CrawlPriority]int)
+	for i := 0; i < 7; i++ {
+		job := popWithin(t, q, time.Second)
+		served[q.lane(job.Priority).priority]++
+	}
+	if served[models.PriorityHigh] != 4 || served[models.PriorityNormal] != 2 || served[models.PriorityLow] != 1 {
+		t.Errorf("Expected 4 high, 2 normal and 1 low job, got %v", served)
+	}
+}
+
+func TestJobQueue_PauseGlobally(t *testing.T) {
+	q := newJobQueue(10)
+	q.pause()
+
+	// Jobs are accepted while paused but not handed out
+	if err := q.push(&CrawlJob{URLID: 1, URL: "https://example.com/"}); err != nil {
+		t.Fatalf("Expected push to succeed while paused: %v", err)
+	}
+
+	popped := make(chan *CrawlJob, 1)
+	go func() {
+		job, _ := q.pop()
+		popped <- job
+	}()
+	select {
+	case <-popped:
+		t.Fatal("Expected no job while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if !q.resume() {
+		t.Error("Expected resume to report a change")
+	}
+	select {
+	case job := <-popped:
+		if job.URLID != 1 {
+			t.Errorf("Expected URL ID=1, got %d", job.URLID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job after resuming")
+	}
+
+	if q.resume() {
+		t.Error("Expected a second resume to report no change")
+	}
+}
+
+func TestJobQueue_PauseHost(t *testing.T) {
+	q := newJobQueue(10)
+	q.pauseHost("slow.example.com")
+
+	q.push(&CrawlJob{URLID: 1, URL: "https://SLOW.example.com/a"})
+	q.push(&CrawlJob{URLID: 2, URL: "https://fast.example.com/"})
+	q.push(&CrawlJob{URLID: 3, URL: "https://slow.example.com:8443/b"})
+
+	// Other hosts are served past the paused host's jobs
+	if job := popWithin(t, q, time.Second); job.URLID != 2 {
+		t.Fatalf("Expected URL ID=2, got %d", job.URLID)
+	}
+
+	pausedAt, hosts := q.pauseState()
+	if pausedAt != nil || len(hosts) != 1 || hosts[0].Host != "slow.example.com" || hosts[0].QueuedJobs != 2 {
+		t.Errorf("Unexpected pause state: %v %+v", pausedAt, hosts)
+	}
+
+	q.resumeHost("slow.example.com")
+	for _, expected := range []uint{1, 3} {
+		if job := popWithin(t, q, time.Second); job.URLID != expected {
+			t.Errorf("Expected URL ID=%d, got %d", expected, job.URLID)
+		}
+	}
+
+	expectNoPop(t, q)
+}
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"example.com", "example.com", true},
+		{" Example.COM ", "example.com", true},
+		{"https://www.example.com/path?q=1", "www.example.com", true},
+		{"example.com:8080", "example.com", true},
+		{"", "", false},
+		{"https://", "", false},
+	}
+
+	for _, tt := range tests {
+		host, err := NormalizeHost(tt.input)
+		if tt.valid && (err != nil || host != tt.expected) {
+			t.Errorf("NormalizeHost(%q) = %q, %v; want %q", tt.input, host, err, tt.expected)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidHost) {
+			t.Errorf("NormalizeHost(%q) = %q; want ErrInvalidHost", tt.input, host)
+		}
+	}
+}
+
+func TestCrawlManager_PauseAndResume(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "home", `{"url": "https://example.com"}`,
+		`<html><head><title>Home</title></head><body></body></html>`)
+
+	manager := setupFixtureManager(t, dir)
+	manager.Pause()
+
+	url := models.URL{URL: "https://example.com", Status: models.StatusQueued}
+	database.DB.Create(&url)
+	if err := manager.QueueURL(url.ID, url.URL); err != nil {
+		t.Fatalf("Expected QueueURL to accept jobs while paused: %v", err)
+	}
+
+	status := manager.GetQueueStatus()
+	if status["paused"] != true || status["queue_length"] != 1 {
+		t.Errorf("Expected a paused queue holding 1 job, got %+v", status)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	var current models.URL
+	database.DB.First(&current, url.ID)
+	if current.Status != models.StatusQueued {
+		t.Fatalf("Expected the URL to stay queued while paused, got %s", current.Status)
+	}
+
+	manager.Resume()
+	waitForStatus(t, url.ID, models.StatusCompleted)
+
+	if status := manager.GetQueueStatus(); status["paused"] != false {
+		t.Errorf("Expected the queue to be resumed, got %+v", status)
+	}
+}
+
+func TestCrawlManager_RestoresPausesAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	manager := setupFixtureManager(t, dir)
+	manager.Pause()
+	if _, _, err := manager.PauseHost("https://Slow.example.com/page"); err != nil {
+		t.Fatalf("PauseHost failed: %v", err)
+	}
+	if _, _, err := manager.PauseHost("other.example.com"); err != nil {
+		t.Fatalf("PauseHost failed: %v", err)
+	}
+	manager.ResumeHost("other.example.com")
+
+	// A new process picks up the pauses left in the database
+	restarted := NewCrawlManagerWithConfig(DefaultManagerConfig())
+	status := restarted.GetQueueStatus()
+	hosts, _ := status["paused_hosts"].([]PausedHost)
+	if status["paused"] != true || len(hosts) != 1 || hosts[0].Host != "slow.example.com" {
+		t.Fatalf("Expected the queue and slow.example.com to stay paused, got %+v", status)
+	}
+
+	restarted.Resume()
+	restarted.ResumeHost("slow.example.com")
+	var saved int64
+	database.DB.Model(&models.QueuePause{}).Count(&saved)
+	if saved != 0 {
+		t.Errorf("Expected resuming to delete the saved pauses, %d left", saved)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PageMeta holds the meta tags of a parsed page. The first occurrence of a
+// single-valued tag wins; Open Graph and Twitter properties keep every value.
+type PageMeta struct {
+	Description string              `json:"description"` // <meta name="description">
+	Robots      string              `json:"robots"`      // <meta name="robots">, lower-cased
+	Canonical   string              `json:"canonical"`   // Resolved <link rel="canonical"> href
+	Viewport    string              `json:"viewport"`    // <meta name="viewport">
+	ThemeColor  string              `json:"theme_color"` // <meta name="theme-color">
+	OpenGraph   map[string][]string `json:"open_graph"`  // og:* properties, e.g. "og:image"
+	Twitter     map[string][]string `json:"twitter"`     // twitter:* properties, e.g. "twitter:card"
+}
+
+// Limits that keep a page from storing unbounded meta data
+const (
+	maxMetaProperties  = 50   // Distinct Open Graph or Twitter properties kept per page, each
+	maxMetaKeyLength   = 100  // Longer property names are ignored
+	maxMetaValues      = 10   // Values kept per Open Graph or Twitter property
+	maxMetaValueLength = 2000 // Bytes kept per meta value
+)
+
+// metaURLProperties are social properties whose values are URLs and are
+// resolved against the page like links
+var metaURLProperties = map[string]bool{
+	"og:url":                true,
+	"og:image":              true,
+	"og:image:url":          true,
+	"og:image:secure_url":   true,
+	"og:video":              true,
+	"og:video:url":          true,
+	"og:video:secure_url":   true,
+	"og:audio":              true,
+	"og:audio:url":          true,
+	"og:audio:secure_url":   true,
+	"twitter:image":         true,
+	"twitter:image:src":     true,
+	"twitter:player":        true,
+	"twitter:player:stream": true,
+}
+
+// NoIndex reports whether the robots meta tag asks search engines not to index the page
+func (m *PageMeta) NoIndex() bool {
+	return m.hasRobotsDirective("noindex")
+}
+
+// NoFollow reports whether the robots meta tag asks search engines not to follow links
+func (m *PageMeta) NoFollow() bool {
+	return m.hasRobotsDirective("nofollow")
+}
+
+// hasRobotsDirective checks the robots meta tag for a directive; "none" means
+// both noindex and nofollow
+func (m *PageMeta) hasRobotsDirective(directive string) bool {
+	for _, value := range strings.FieldsFunc(m.Robots, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if value == directive || value == "none" {
+			return true
+		}
+	}
+	return false
+}
+
+// addMeta records a <meta> element. Social properties are accepted in either
+// the property or the name attribute, as both are common in the wild.
+func (p *HTMLParser) addMeta(meta *PageMeta, n *html.Node, baseURL string) {
+	content, ok := lookupAttr(n, "content")
+	if !ok {
+		return
+	}
+	content = truncateString(strings.Join(strings.Fields(content), " "), maxMetaValueLength)
+
+	key := strings.ToLower(strings.TrimSpace(getAttr(n, "property")))
+	if key == "" {
+		key = strings.ToLower(strings.TrimSpace(getAttr(n, "name")))
+	}
+
+	switch {
+	case key == "description":
+		setFirst(&meta.Description, content)
+	case key == "robots":
+		setFirst(&meta.Robots, strings.ToLower(content))
+	case key == "viewport":
+		setFirst(&meta.Viewport, content)
+	case key == "theme-color":
+		setFirst(&meta.ThemeColor, content)
+	case strings.HasPrefix(key, "og:"):
+		meta.OpenGraph = addMetaValue(meta.OpenGraph, key, p.metaValue(key, content, baseURL))
+	case strings.HasPrefix(key, "twitter:"):
+		meta.Twitter = addMetaValue(meta.Twitter, key, p.metaValue(key, content, baseURL))
+	}
+}
+
+// addCanonical records the first <link rel="canonical"> of the page
+func (p *HTMLParser) addCanonical(meta *PageMeta, n *html.Node, baseURL string) {
+	for _, rel := range strings.Fields(strings.ToLower(getAttr(n, "rel"))) {
+		if rel == "canonical" {
+			if href := strings.TrimSpace(getAttr(n, "href")); href != "" {
+				setFirst(&meta.Canonical, p.resolveURL(href, baseURL))
+			}
+			return
+		}
+	}
+}
+
+// metaValue resolves URL-valued social properties against the page
+func (p *HTMLParser) metaValue(key, content, baseURL string) string {
+	if metaURLProperties[key] && content != "" {
+		if resolved := p.resolveURL(content, baseURL); resolved != "" {
+			return resolved
+		}
+	}
+	return content
+}
+
+// setFirst sets a value unless one was already found
+func setFirst(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// addMetaValue appends a non-empty value to a property, up to maxMetaValues.
+// Properties beyond the first maxMetaProperties are dropped.
+func addMetaValue(properties map[string][]string, key, value string) map[string][]string {
+	if value == "" || len(key) > maxMetaKeyLength {
+		return properties
+	}
+	if properties == nil {
+		properties = make(map[string][]string)
+	}
+	if _, ok := properties[key]; !ok && len(properties) >= maxMetaProperties {
+		return properties
+	}
+	if len(properties[key]) < maxMetaValues {
+		properties[key] = append(properties[key], value)
+	}
+	return properties
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHTMLParser_MetaTags(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><head>
+		<title>Blog post</title>
+		<meta name="description" content=" A post
+			about crawling ">
+		<meta name="description" content="Ignored second description">
+		<meta name="ROBOTS" content="NoIndex, follow">
+		<meta name="viewport" content="width=device-width, initial-scale=1">
+		<meta name="theme-color" content="#336699" media="(prefers-color-scheme: light)">
+		<meta name="theme-color" content="#000000" media="(prefers-color-scheme: dark)">
+		<link rel="alternate canonical" href="/blog/post?ref=feed#top">
+		<link rel="canonical" href="/ignored">
+		<meta property="og:title" content="Crawling, explained">
+		<meta property="og:image" content="/img/cover.png">
+		<meta property="og:image" content="https://cdn.example.net/cover-2.png">
+		<meta property="og:type" content="article">
+		<meta name="og:site_name" content="Example">
+		<meta name="twitter:card" content="summary_large_image">
+		<meta property="twitter:image" content="/img/card.png">
+		<meta property="og:description">
+	</head><body></body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com/blog/post")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	meta := result.Meta
+
+	if meta.Description != "A post about crawling" {
+		t.Errorf("Expected the first description, got %q", meta.Description)
+	}
+	if meta.Robots != "noindex, follow" || !meta.NoIndex() || meta.NoFollow() {
+		t.Errorf("Expected noindex without nofollow, got %q", meta.Robots)
+	}
+	if meta.Viewport != "width=device-width, initial-scale=1" || meta.ThemeColor != "#336699" {
+		t.Errorf("Unexpected viewport %q or theme color %q", meta.Viewport, meta.ThemeColor)
+	}
+	if meta.Canonical != "https://example.com/blog/post?ref=feed" {
+		t.Errorf("Expected the first resolved canonical URL, got %q", meta.Canonical)
+	}
+
+	images := meta.OpenGraph["og:image"]
+	if len(images) != 2 || images[0] != "https://example.com/img/cover.png" || images[1] != "https://cdn.example.net/cover-2.png" {
+		t.Errorf("Expected both resolved og:image values, got %v", images)
+	}
+	if meta.OpenGraph["og:site_name"][0] != "Example" || meta.OpenGraph["og:type"][0] != "article" {
+		t.Errorf("Unexpected Open Graph properties: %v", meta.OpenGraph)
+	}
+	if _, ok := meta.OpenGraph["og:description"]; ok {
+		t.Error("Expected a meta tag without content to be ignored")
+	}
+	if meta.Twitter["twitter:card"][0] != "summary_large_image" || meta.Twitter["twitter:image"][0] != "https://example.com/img/card.png" {
+		t.Errorf("Unexpected Twitter properties: %v", meta.Twitter)
+	}
+}
+
+func TestPageMeta_RobotsNone(t *testing.T) {
+	meta := PageMeta{Robots: "none"}
+	if !meta.NoIndex() || !meta.NoFollow() {
+		t.Error("Expected robots none to mean noindex and nofollow")
+	}
+
+	meta = PageMeta{Robots: "index,nofollow"}
+	if meta.NoIndex() || !meta.NoFollow() {
+		t.Error("Expected only nofollow")
+	}
+}
+
+func TestAddMetaValue_BoundsProperties(t *testing.T) {
+	var properties map[string][]string
+	for i := 0; i < maxMetaProperties+10; i++ {
+		properties = addMetaValue(properties, fmt.Sprintf("og:custom:%d", i), "value")
+	}
+	if len(properties) != maxMetaProperties {
+		t.Errorf("Expected %d properties, got %d", maxMetaProperties, len(properties))
+	}
+
+	// Properties already kept still collect values
+	properties = addMetaValue(properties, "og:custom:0", "second")
+	if len(properties["og:custom:0"]) != 2 {
+		t.Errorf("Expected a kept property to take more values, got %v", properties["og:custom:0"])
+	}
+
+	properties = addMetaValue(nil, "og:"+strings.Repeat("x", maxMetaKeyLength), "value")
+	if len(properties) != 0 {
+		t.Errorf("Expected an overlong property name to be ignored, got %v", properties)
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+)
+
+// ReaperConfig controls recovery of crawls stuck in "running"
+type ReaperConfig struct {
+	Interval      time.Duration // How often to look for stale crawls (1m)
+	LeaseTimeout  time.Duration // A crawl running longer than this is stale, even on a live worker (10m)
+	WorkerTimeout time.Duration // A worker without a heartbeat for this long is dead (1m)
+	Requeue       bool          // Requeue stale crawls; false moves them to "error" (true)
+	MaxRequeues   int           // Recoveries since the last successful crawl before giving up on a URL (3)
+}
+
+// DefaultReaperConfig returns the default reaper configuration
+func DefaultReaperConfig() *ReaperConfig {
+	return &ReaperConfig{
+		Interval:      time.Minute,
+		LeaseTimeout:  10 * time.Minute,
+		WorkerTimeout: time.Minute,
+		Requeue:       true,
+		MaxRequeues:   3,
+	}
+}
+
+// deadWorkerRetention is how long rows of workers that stopped without deregistering are kept
+const deadWorkerRetention = 24 * time.Hour
+
+// Reaper finds URLs left in "running" by a worker that died, panicked or hung,
+// and requeues or fails them so they can be crawled again. Every recovery is
+// recorded in crawl_recoveries.
+type Reaper struct {
+	manager *CrawlManager
+	config  *ReaperConfig
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReaper creates a reaper that requeues stale crawls on the given manager (nil config = defaults)
+func NewReaper(cm *CrawlManager, config *ReaperConfig) *Reaper {
+	if config == nil {
+		config = DefaultReaperConfig()
+	}
+
+	return &Reaper{
+		manager: cm,
+		config:  config,
+		stop:    make(chan struct{}),
+	}
+}
+
+// Start sweeps for stale crawls immediately and then every Interval
+func (r *Reaper) Start() {
+	log.Printf("Starting stale crawl reaper (every %v, lease %v, worker timeout %v)",
+		r.config.Interval, r.config.LeaseTimeout, r.config.WorkerTimeout)
+
+	go func() {
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := r.Sweep(); err != nil {
+				log.Printf("Stale crawl sweep failed: %v", err)
+			}
+
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the background sweeps
+func (r *Reaper) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Sweep recovers every stale "running" URL once and returns the recoveries made.
+// It also queues retries that are due but were lost with the process that scheduled them.
+func (r *Reaper) Sweep() ([]models.CrawlRecovery, error) {
+	now := time.Now()
+
+	var liveWorkers []string
+	err := database.DB.Model(&models.CrawlWorker{}).
+		Where("last_heartbeat_at >= ?", now.Add(-r.config.WorkerTimeout)).
+		Pluck("id", &liveWorkers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load live workers: %w", err)
+	}
+
+	live := make(map[string]bool, len(liveWorkers))
+	for _, id := range liveWorkers {
+		live[id] = true
+	}
+
+	var running []models.URL
+	if err := database.DB.Where("status = ?", models.StatusRunning).Find(&running).Error; err != nil {
+		return nil, fmt.Errorf("failed to load running URLs: %w", err)
+	}
+
+	var recoveries []models.CrawlRecovery
+	for i := range running {
+		url := &running[i]
+
+		reason, stale := r.staleReason(url, live, now)
+		if !stale {
+			continue
+		}
+
+		recovery, err := r.recoverURL(url, reason)
+		if err != nil {
+			log.Printf("Failed to recover stale crawl of URL ID=%d: %v", url.ID, err)
+			continue
+		}
+		if recovery != nil {
+			recoveries = append(recoveries, *recovery)
+		}
+	}
+
+	// Retries are timers in the process that scheduled them, so a restart drops them
+	if requeued, err := r.manager.RequeueDueRetries(); err != nil {
+		log.Printf("Failed to requeue due retries: %v", err)
+	} else if requeued > 0 {
+		log.Printf("Requeued %d due retries", requeued)
+	}
+
+	// Forget workers that died without deregistering once nothing can refer to them
+	if err := database.DB.Where("last_heartbeat_at < ?", now.Add(-deadWorkerRetention)).Delete(&models.CrawlWorker{}).Error; err != nil {
+		log.Printf("Failed to delete dead crawl workers: %v", err)
+	}
+
+	return recoveries, nil
+}
+
+// staleReason reports whether a running URL should be recovered, and why
+func (r *Reaper) staleReason(url *models.URL, live map[string]bool, now time.Time) (models.CrawlRecoveryReason, bool) {
+	switch {
+	case url.WorkerID == nil:
+		return models.RecoveryNoOwner, true
+	case !live[*url.WorkerID]:
+		return models.RecoveryWorkerLost, true
+	case url.CrawlStartedAt == nil || now.Sub(*url.CrawlStartedAt) > r.config.LeaseTimeout:
+		return models.RecoveryLeaseExpired, true
+	}
+	return "", false
+}
+
+// recoverURL requeues or fails one stale URL, closes its open attempts and records the recovery.
+// It returns nil if the URL stopped running before it could be recovered.
+func (r *Reaper) recoverURL(url *models.URL, reason models.CrawlRecoveryReason) (*models.CrawlRecovery, error) {
+	message := staleMessage(url, reason)
+
+	action := models.RecoveryFailed
+	if r.config.Requeue {
+		recent, err := r.recentRecoveries(url.ID)
+		if err != nil {
+			return nil, err
+		}
+		if recent < r.config.MaxRequeues {
+			action = models.RecoveryRequeued
+		} else {
+			message = fmt.Sprintf("%s; recovered %d times since the last successful crawl, giving up", message, recent)
+		}
+	}
+
+	status := models.StatusError
+	if action == models.RecoveryRequeued {
+		status = models.StatusQueued
+	}
+
+	// Only take the URL if it is still held by the same worker
+	query := database.DB.Model(&models.URL{}).Where("id = ? AND status = ?", url.ID, models.StatusRunning)
+	if url.WorkerID != nil {
+		query = query.Where("worker_id = ?", *url.WorkerID)
+	} else {
+		query = query.Where("worker_id IS NULL")
+	}
+	result := query.Updates(map[string]interface{}{
+		"status":           status,
+		"error_message":    message,
+		"error_type":       "abandoned",
+		"next_retry_at":    nil,
+		"worker_id":        nil,
+		"crawl_started_at": nil,
+		"updated_at":       time.Now(),
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update URL: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	r.abandonAttempts(url.ID, message)
+
+	if action == models.RecoveryRequeued {
+		if err := r.manager.QueueURLWithPriority(url.ID, url.URL, models.PriorityLow); err != nil {
+			action = models.RecoveryFailed
+			message = fmt.Sprintf("%s; requeue failed: %v", message, err)
+			database.DB.Model(&models.URL{}).Where("id = ?", url.ID).Updates(map[string]interface{}{
+				"status":        models.StatusError,
+				"error_message": message,
+				"updated_at":    time.Now(),
+			})
+		}
+	}
+
+	recovery := &models.CrawlRecovery{
+		URLID:          url.ID,
+		URL:            url.URL,
+		WorkerID:       url.WorkerID,
+		CrawlStartedAt: url.CrawlStartedAt,
+		Reason:         reason,
+		Action:         action,
+		Message:        message,
+		ReaperID:       r.manager.WorkerID(),
+	}
+	if err := database.DB.Create(recovery).Error; err != nil {
+		log.Printf("Failed to record recovery of URL ID=%d: %v", url.ID, err)
+	}
+
+	log.Printf("Recovered stale crawl: URL ID=%d, URL=%s, worker=%s, reason=%s, action=%s",
+		url.ID, url.URL, formatOptionalString(url.WorkerID), reason, action)
+
+	return recovery, nil
+}
+
+// recentRecoveries counts a URL's recoveries since its last successful attempt
+func (r *Reaper) recentRecoveries(urlID uint) (int, error) {
+	query := database.DB.Model(&models.CrawlRecovery{}).Where("url_id = ?", urlID)
+
+	var lastSuccess models.CrawlAttempt
+	err := database.DB.
+		Where("url_id = ? AND outcome IN ?", urlID,
+			[]models.CrawlAttemptOutcome{models.AttemptCompleted, models.AttemptNotModified}).
+		Order("id DESC").
+		Limit(1).
+		Find(&lastSuccess).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load last successful attempt: %w", err)
+	}
+	if lastSuccess.ID != 0 {
+		query = query.Where("created_at > ?", lastSuccess.StartedAt)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count recoveries: %w", err)
+	}
+	return int(count), nil
+}
+
+// abandonAttempts closes a URL's attempts that never finished
+func (r *Reaper) abandonAttempts(urlID uint, message string) {
+	err := database.DB.Model(&models.CrawlAttempt{}).
+		Where("url_id = ? AND outcome = ?", urlID, models.AttemptRunning).
+		Updates(map[string]interface{}{
+			"outcome":       models.AttemptAbandoned,
+			"finished_at":   time.Now(),
+			"error_type":    "abandoned",
+			"error_message": message,
+		}).Error
+	if err != nil {
+		log.Printf("Failed to close abandoned attempts of URL ID=%d: %v", urlID, err)
+	}
+}
+
+// staleMessage describes why a crawl was taken away from its worker
+func staleMessage(url *models.URL, reason models.CrawlRecoveryReason) string {
+	switch reason {
+	case models.RecoveryNoOwner:
+		return "Crawl abandoned: running without a worker"
+	case models.RecoveryWorkerLost:
+		return fmt.Sprintf("Crawl abandoned: worker %s stopped responding", *url.WorkerID)
+	default:
+		started := "unknown"
+		if url.CrawlStartedAt != nil {
+			started = url.CrawlStartedAt.UTC().Format(time.RFC3339)
+		}
+		return fmt.Sprintf("Crawl abandoned: lease expired (running since %s)", started)
+	}
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+)
+
+// createRunningURL inserts a URL left in "running" by the given worker
+func createRunningURL(t *testing.T, url string, workerID *string, startedAt time.Time) *models.URL {
+	t.Helper()
+
+	record := models.URL{URL: url, Status: models.StatusRunning, WorkerID: workerID, CrawlStartedAt: &startedAt}
+	if err := database.DB.Create(&record).Error; err != nil {
+		t.Fatalf("Failed to create URL: %v", err)
+	}
+	return &record
+}
+
+// waitForStatus waits until a URL reaches the given status
+func waitForStatus(t *testing.T, urlID uint, status models.URLStatus) *models.URL {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var current models.URL
+		database.DB.First(&current, urlID)
+		if current.Status == status {
+			return &current
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("URL ID=%d did not reach status %s in time", urlID, status)
+	return nil
+}
+
+func TestReaper_RecoversStaleCrawls(t *testing.T) {
+	dir := t.TempDir()
+	page := `<html><head><title>Recovered</title></head><body></body></html>`
+	writeFixture(t, dir, "orphan", `{"url": "https://example.com/orphan"}`, page)
+	writeFixture(t, dir, "lost", `{"url": "https://example.com/lost"}`, page)
+	writeFixture(t, dir, "active", `{"url": "https://example.com/active"}`, page)
+	writeFixture(t, dir, "hung", `{"url": "https://example.com/hung"}`, page)
+
+	manager := setupFixtureManager(t, dir)
+	reaper := NewReaper(manager, &ReaperConfig{
+		Interval:      time.Hour,
+		LeaseTimeout:  10 * time.Minute,
+		WorkerTimeout: time.Minute,
+		Requeue:       true,
+		MaxRequeues:   3,
+	})
+
+	// A worker that died without deregistering
+	deadWorker := "dead-host-1-abcd"
+	database.DB.Create(&models.CrawlWorker{ID: deadWorker, StartedAt: time.Now().Add(-time.Hour),
+		LastHeartbeatAt: time.Now().Add(-10 * time.Minute)})
+
+	liveWorker := manager.WorkerID()
+	orphan := createRunningURL(t, "https://example.com/orphan", nil, time.Now())
+	lost := createRunningURL(t, "https://example.com/lost", &deadWorker, time.Now())
+	active := createRunningURL(t, "https://example.com/active", &liveWorker, time.Now())
+	hung := createRunningURL(t, "https://example.com/hung", &liveWorker, time.Now().Add(-time.Hour))
+
+	// The dead worker's attempt was never finished
+	database.DB.Create(&models.CrawlAttempt{URLID: lost.ID, Attempt: 1, WorkerID: deadWorker,
+		Outcome: models.AttemptRunning, StartedAt: time.Now()})
+
+	recoveries, err := reaper.Sweep()
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+
+	reasons := make(map[uint]models.CrawlRecoveryReason)
+	for _, recovery := range recoveries {
+		reasons[recovery.URLID] = recovery.Reason
+		if recovery.Action != models.RecoveryRequeued {
+			t.Errorf("Expected URL ID=%d to be requeued, got %s", recovery.URLID, recovery.Action)
+		}
+	}
+	expected := map[uint]models.CrawlRecoveryReason{
+		orphan.ID: models.RecoveryNoOwner,
+		lost.ID:   models.RecoveryWorkerLost,
+		hung.ID:   models.RecoveryLeaseExpired,
+	}
+	if len(reasons) != len(expected) {
+		t.Fatalf("Expected %d recoveries, got %+v", len(expected), reasons)
+	}
+	for urlID, reason := range expected {
+		if reasons[urlID] != reason {
+			t.Errorf("URL ID=%d: expected reason %s, got %s", urlID, reason, reasons[urlID])
+		}
+	}
+
+	// Requeued URLs are crawled again
+	for _, url := range []*models.URL{orphan, lost, hung} {
+		recovered := waitForStatus(t, url.ID, models.StatusCompleted)
+		if recovered.WorkerID != nil || recovered.CrawlStartedAt != nil {
+			t.Errorf("URL ID=%d: expected the lease to be released after crawling", url.ID)
+		}
+	}
+
+	// A crawl within its lease on a live worker is left alone
+	var current models.URL
+	database.DB.First(&current, active.ID)
+	if current.Status != models.StatusRunning {
+		t.Errorf("Expected the active crawl to stay running, got %s", current.Status)
+	}
+
+	var abandoned models.CrawlAttempt
+	database.DB.Where("url_id = ? AND attempt = 1 AND worker_id = ?", lost.ID, deadWorker).First(&abandoned)
+	if abandoned.Outcome != models.AttemptAbandoned || abandoned.FinishedAt == nil {
+		t.Errorf("Expected the dead worker's attempt to be abandoned, got %s", abandoned.Outcome)
+	}
+
+	var audited int64
+	database.DB.Model(&models.CrawlRecovery{}).Where("reaper_id = ?", liveWorker).Count(&audited)
+	if audited != 3 {
+		t.Errorf("Expected 3 recovery records, got %d", audited)
+	}
+}
+
+func TestReaper_FailsStaleCrawls(t *testing.T) {
+	manager := setupFixtureManager(t, t.TempDir())
+
+	// With requeueing disabled stale crawls go straight to "error"
+	reaper := NewReaper(manager, &ReaperConfig{Interval: time.Hour, LeaseTimeout: time.Minute,
+		WorkerTimeout: time.Minute, Requeue: false})
+
+	orphan := createRunningURL(t, "https://example.com/orphan", nil, time.Now())
+	if _, err := reaper.Sweep(); err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+
+	failed := waitForStatus(t, orphan.ID, models.StatusError)
+	if failed.ErrorType == nil || *failed.ErrorType != "abandoned" || failed.ErrorMessage == nil {
+		t.Errorf("Expected an abandoned error, got %v", formatOptionalString(failed.ErrorType))
+	}
+
+	// A URL that keeps getting stuck is failed once it runs out of requeues
+	reaper = NewReaper(manager, &ReaperConfig{Interval: time.Hour, LeaseTimeout: time.Minute,
+		WorkerTimeout: time.Minute, Requeue: true, MaxRequeues: 2})
+
+	stuck := createRunningURL(t, "https://example.com/stuck", nil, time.Now())
+	for i := 0; i < 2; i++ {
+		database.DB.Create(&models.CrawlRecovery{URLID: stuck.ID, URL: stuck.URL,
+			Reason: models.RecoveryNoOwner, Action: models.RecoveryRequeued})
+	}
+
+	recoveries, err := reaper.Sweep()
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if len(recoveries) != 1 || recoveries[0].Action != models.RecoveryFailed {
+		t.Fatalf("Expected the stuck URL to be failed, got %+v", recoveries)
+	}
+	waitForStatus(t, stuck.ID, models.StatusError)
+}
+
+// blockingFetcher holds the first fetch until released, like a worker that is slow but alive
+type blockingFetcher struct {
+	Fetcher
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *blockingFetcher) FetchURL(rawURL string, validators *CacheValidators) (*CrawlResponse, error) {
+	f.once.Do(func() {
+		close(f.started)
+		<-f.release
+	})
+	return f.Fetcher.FetchURL(rawURL, validators)
+}
+
+func TestReaper_SlowWorkerCannotOverwriteRecoveredURL(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "slow", `{"url": "https://example.com/slow"}`,
+		`<html><head><title>Slow</title></head><body></body></html>`)
+
+	fetcher := &blockingFetcher{started: make(chan struct{}), release: make(chan struct{})}
+	manager := setupFixtureManagerWithConfig(t, dir, func(config *ManagerConfig) {
+		fetcher.Fetcher = config.Fetcher
+		config.Fetcher = fetcher
+	})
+
+	url := models.URL{URL: "https://example.com/slow", Status: models.StatusQueued}
+	database.DB.Create(&url)
+	if err := manager.QueueURL(url.ID, url.URL); err != nil {
+		t.Fatalf("Failed to queue URL: %v", err)
+	}
+	<-fetcher.started
+
+	// The lease expires while the live worker is still fetching; hold the requeued job back
+	manager.Pause()
+	reaper := NewReaper(manager, &ReaperConfig{Interval: time.Hour, LeaseTimeout: time.Nanosecond,
+		WorkerTimeout: time.Minute, Requeue: true, MaxRequeues: 3})
+	recoveries, err := reaper.Sweep()
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if len(recoveries) != 1 || recoveries[0].Reason != models.RecoveryLeaseExpired {
+		t.Fatalf("Expected the slow crawl to be recovered, got %+v", recoveries)
+	}
+
+	close(fetcher.release)
+
+	// The slow worker's attempt ends without touching the requeued URL
+	deadline := time.Now().Add(5 * time.Second)
+	var attempt models.CrawlAttempt
+	for time.Now().Before(deadline) {
+		database.DB.Where("url_id = ? AND error_type = ?", url.ID, "lease_lost").Find(&attempt)
+		if attempt.ID != 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if attempt.ID == 0 || attempt.Outcome != models.AttemptAbandoned {
+		t.Fatalf("Expected the slow attempt to be abandoned with a lost lease, got %+v", attempt)
+	}
+
+	var current models.URL
+	database.DB.First(&current, url.ID)
+	if current.Status != models.StatusQueued {
+		t.Errorf("Expected the URL to stay queued, got %s", current.Status)
+	}
+	var results int64
+	database.DB.Model(&models.CrawlResult{}).Where("url_id = ?", url.ID).Count(&results)
+	if results != 0 {
+		t.Errorf("Expected the slow crawl's result to be discarded, got %d results", results)
+	}
+
+	// The requeued crawl completes normally
+	manager.Resume()
+	waitForStatus(t, url.ID, models.StatusCompleted)
+}
+
+func TestReaper_RequeuesRetriesLostOnRestart(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "due", `{"url": "https://example.com/due"}`,
+		`<html><head><title>Due</title></head><body></body></html>`)
+
+	manager := setupFixtureManager(t, dir)
+	reaper := NewReaper(manager, &ReaperConfig{Interval: time.Hour, LeaseTimeout: 10 * time.Minute,
+		WorkerTimeout: time.Minute, Requeue: true, MaxRequeues: 3})
+
+	// Retries scheduled by a previous process, which no timer will fire
+	errorType := "timeout"
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	due := models.URL{URL: "https://example.com/due", Status: models.StatusQueued, ErrorType: &errorType,
+		RetryCount: 1, NextRetryAt: &past}
+	database.DB.Create(&due)
+	later := models.URL{URL: "https://example.com/later", Status: models.StatusQueued, ErrorType: &errorType,
+		RetryCount: 1, NextRetryAt: &future}
+	database.DB.Create(&later)
+
+	if _, err := reaper.Sweep(); err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+
+	crawled := waitForStatus(t, due.ID, models.StatusCompleted)
+	if crawled.NextRetryAt != nil {
+		t.Error("Expected the retry time to be cleared")
+	}
+	attempts := waitForAttempts(t, due.ID, 1)
+	if attempts[0].Attempt != 2 || attempts[0].Outcome != models.AttemptCompleted {
+		t.Errorf("Expected attempt 2 to complete, got attempt %d %s", attempts[0].Attempt, attempts[0].Outcome)
+	}
+
+	// A second sweep does not queue the retry again, and retries not yet due wait
+	if _, err := reaper.Sweep(); err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	var current models.URL
+	database.DB.First(&current, later.ID)
+	if current.Status != models.StatusQueued || current.NextRetryAt == nil {
+		t.Errorf("Expected the later retry to stay scheduled, got %s", current.Status)
+	}
+	var count int64
+	database.DB.Model(&models.CrawlAttempt{}).Where("url_id IN ?", []uint{due.ID, later.ID}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected a single attempt, got %d", count)
+	}
+}
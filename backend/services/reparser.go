@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultReparseBatchSize is the number of URLs re-parsed per checkpoint
+const DefaultReparseBatchSize = 50
+
+var (
+	// ErrReparseRunning is returned when a re-parse job is already in progress
+	ErrReparseRunning = errors.New("a re-parse job is already running")
+	// ErrReparseCompleted is returned when resuming a job that already finished
+	ErrReparseCompleted = errors.New("re-parse job has already completed")
+	// ErrSnapshotsDisabled is returned when there are no stored snapshots to re-parse
+	ErrSnapshotsDisabled = errors.New("raw HTML snapshots are disabled")
+)
+
+// errReparseSkipped marks a URL left untouched because it is busy or was re-crawled meanwhile
+var errReparseSkipped = errors.New("crawl result changed during re-parse")
+
+// Reparser re-runs the HTML parser over stored snapshots and rewrites the
+// derived crawl result fields and found links, without fetching anything
+type Reparser struct {
+	manager *CrawlManager
+	mu      sync.Mutex
+	running bool
+}
+
+// NewReparser creates a re-parser that uses the crawl manager's parser and snapshot store
+func NewReparser(manager *CrawlManager) *Reparser {
+	return &Reparser{
+		manager: manager,
+	}
+}
+
+// StartJob creates a new re-parse job over every URL with a stored snapshot and runs it in the background
+func (r *Reparser) StartJob(batchSize int) (*models.ReparseJob, error) {
+	if r.manager.snapshots == nil {
+		return nil, ErrSnapshotsDisabled
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultReparseBatchSize
+	}
+
+	if !r.acquire() {
+		return nil, ErrReparseRunning
+	}
+
+	job := &models.ReparseJob{
+		Status:    models.ReparsePending,
+		BatchSize: batchSize,
+	}
+	if err := database.DB.Create(job).Error; err != nil {
+		r.release()
+		return nil, fmt.Errorf("failed to create re-parse job: %w", err)
+	}
+
+	// Return a copy; the background run keeps updating job
+	created := *job
+	go r.run(job)
+	return &created, nil
+}
+
+// ResumeJob continues a failed or interrupted job from its last checkpoint
+func (r *Reparser) ResumeJob(id uint) (*models.ReparseJob, error) {
+	if r.manager.snapshots == nil {
+		return nil, ErrSnapshotsDisabled
+	}
+
+	var job models.ReparseJob
+	if err := database.DB.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	if job.Status == models.ReparseCompleted {
+		return nil, ErrReparseCompleted
+	}
+
+	if !r.acquire() {
+		return nil, ErrReparseRunning
+	}
+
+	resumed := job
+	go r.run(&job)
+	return &resumed, nil
+}
+
+// ResumeInterrupted restarts a job left running by a previous process, if any.
+// It should be called once at startup.
+func (r *Reparser) ResumeInterrupted() {
+	var job models.ReparseJob
+	err := database.DB.Where("status IN ?", []models.ReparseJobStatus{models.ReparsePending, models.ReparseRunning}).
+		Order("id").First(&job).Error
+	if err != nil {
+		return
+	}
+
+	log.Printf("Resuming interrupted re-parse job ID=%d from URL ID=%d", job.ID, job.LastURLID)
+	if _, err := r.ResumeJob(job.ID); err != nil {
+		log.Printf("Failed to resume re-parse job ID=%d: %v", job.ID, err)
+	}
+}
+
+// IsRunning reports whether a re-parse job is in progress
+func (r *Reparser) IsRunning() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
+
+// acquire claims the single job slot
+func (r *Reparser) acquire() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return false
+	}
+	r.running = true
+	return true
+}
+
+// release frees the job slot
+func (r *Reparser) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running = false
+}
+
+// run processes the job batch by batch, saving a checkpoint after each batch.
+// The caller must have acquired the job slot.
+func (r *Reparser) run(job *models.ReparseJob) {
+	defer r.release()
+
+	// Count what is left so progress stays meaningful after a resume
+	var remaining int64
+	if err := r.pendingResults(job.LastURLID).Count(&remaining).Error; err != nil {
+		r.fail(job, fmt.Errorf("failed to count snapshots: %w", err))
+		return
+	}
+
+	now := time.Now()
+	job.Status = models.ReparseRunning
+	job.Total = job.Processed + int(remaining)
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	job.FinishedAt = nil
+	if err := database.DB.Save(job).Error; err != nil {
+		log.Printf("Failed to start re-parse job ID=%d: %v", job.ID, err)
+		return
+	}
+
+	log.Printf("Re-parse job ID=%d started: %d URLs remaining, batch size %d", job.ID, remaining, job.BatchSize)
+
+	for {
+		var batch []models.CrawlResult
+		if err := r.pendingResults(job.LastURLID).Order("url_id").Limit(job.BatchSize).Find(&batch).Error; err != nil {
+			r.fail(job, fmt.Errorf("failed to load batch: %w", err))
+			return
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for i := range batch {
+			err := r.reparseSafely(&batch[i])
+			switch {
+			case err == nil:
+				job.Succeeded++
+			case errors.Is(err, errReparseSkipped):
+				job.Skipped++
+			default:
+				job.Failed++
+				errorMsg := fmt.Sprintf("URL ID=%d: %v", batch[i].URLID, err)
+				job.LastError = &errorMsg
+				log.Printf("Re-parse job ID=%d failed for %s", job.ID, errorMsg)
+			}
+			job.Processed++
+			job.LastURLID = batch[i].URLID
+		}
+
+		// Checkpoint: a restart resumes after the last URL of this batch
+		if err := database.DB.Save(job).Error; err != nil {
+			log.Printf("Failed to save re-parse job ID=%d progress: %v", job.ID, err)
+			return
+		}
+		log.Printf("Re-parse job ID=%d progress: %d/%d", job.ID, job.Processed, job.Total)
+	}
+
+	finished := time.Now()
+	job.Status = models.ReparseCompleted
+	job.FinishedAt = &finished
+	if err := database.DB.Save(job).Error; err != nil {
+		log.Printf("Failed to complete re-parse job ID=%d: %v", job.ID, err)
+		return
+	}
+
+	log.Printf("Re-parse job ID=%d completed: %d succeeded, %d failed, %d skipped",
+		job.ID, job.Succeeded, job.Failed, job.Skipped)
+}
+
+// pendingResults selects crawl results with a snapshot after the given URL ID
+func (r *Reparser) pendingResults(afterURLID uint) *gorm.DB {
+	return database.DB.Model(&models.CrawlResult{}).
+		Where("snapshot_id IS NOT NULL AND url_id > ?", afterURLID)
+}
+
+// fail marks the job as failed; it can be resumed from its last checkpoint
+func (r *Reparser) fail(job *models.ReparseJob, err error) {
+	log.Printf("Re-parse job ID=%d failed: %v", job.ID, err)
+
+	finished := time.Now()
+	errorMsg := err.Error()
+	job.Status = models.ReparseFailed
+	job.LastError = &errorMsg
+	job.FinishedAt = &finished
+	if saveErr := database.DB.Save(job).Error; saveErr != nil {
+		log.Printf("Failed to save re-parse job ID=%d: %v", job.ID, saveErr)
+	}
+}
+
+// reparseSafely re-parses one URL, turning a panic into an error so a single
+// bad snapshot fails its URL instead of the process. A resumed job would
+// otherwise hit the same snapshot again and again.
+func (r *Reparser) reparseSafely(crawlResult *models.CrawlResult) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			excerpt := stackExcerpt(debug.Stack(), maxPanicStackLines)
+			log.Printf("Re-parse panicked for URL ID=%d: %v\n%s", crawlResult.URLID, value, excerpt)
+			err = fmt.Errorf("panic: %v\n%s", value, excerpt)
+		}
+	}()
+
+	return r.reparseResult(crawlResult)
+}
+
+// reparseResult re-parses one URL's snapshot and rewrites its derived data
+func (r *Reparser) reparseResult(crawlResult *models.CrawlResult) error {
+	var url models.URL
+	if err := database.DB.First(&url, crawlResult.URLID).Error; err != nil {
+		return fmt.Errorf("failed to load URL: %w", err)
+	}
+
+	// A live crawl will overwrite the result anyway
+	if url.Status == models.StatusRunning {
+		return errReparseSkipped
+	}
+
+	var snapshot models.PageSnapshot
+	if err := database.DB.First(&snapshot, *crawlResult.SnapshotID).Error; err != nil {
+		return fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	body, err := r.manager.snapshots.LoadBody(&snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot body: %w", err)
+	}
+
+	parseStart := time.Now()
+	data, err := r.manager.parser.Parse(body, url.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	parseMs := int(time.Since(parseStart).Milliseconds())
+
+	// Change status compares fetches, so only the text fingerprint is refreshed
+	applyParsedData(crawlResult, data)
+	r.manager.applyLinkLimit(crawlResult, data)
+	hashes := HashContent(body, data.TextContent)
+	crawlResult.TextHash = &hashes.TextHash
+	crawlResult.ParseDurationMs = &parseMs
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// Guard against a re-crawl replacing the result since it was loaded
+		columns := append([]string{"text_hash", "parse_duration_ms", "saved_links_count", "links_truncated"},
+			parsedDataColumns...)
+		result := tx.Model(crawlResult).
+			Where("snapshot_id = ?", snapshot.ID).
+			Select(columns).
+			Updates(crawlResult)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update crawl result: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errReparseSkipped
+		}
+
+		return r.manager.savePageDetails(tx, crawlResult.URLID, data)
+	})
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+)
+
+// setupReparseTest creates an in-memory database and a manager with snapshots enabled
+func setupReparseTest(t *testing.T) *CrawlManager {
+	t.Helper()
+
+	setupTestDB(t, &models.URL{}, &models.CrawlResult{}, &models.FoundLink{},
+		&models.PageImage{}, &models.StructuredData{}, &models.PageForm{}, &models.PageSnapshot{}, &models.ReparseJob{})
+
+	blobs, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+
+	config := DefaultManagerConfig()
+	config.SnapshotStore = NewSnapshotStore(blobs, DefaultRetentionPolicy())
+	return NewCrawlManagerWithConfig(config)
+}
+
+// seedSnapshot stores a crawled page with stale derived data, as left by an older parser
+func seedSnapshot(t *testing.T, cm *CrawlManager, rawURL, html string) *models.CrawlResult {
+	t.Helper()
+
+	url := models.URL{URL: rawURL, Status: models.StatusCompleted}
+	if err := database.DB.Create(&url).Error; err != nil {
+		t.Fatalf("Failed to create URL: %v", err)
+	}
+
+	snapshot, err := cm.snapshots.StoreBody(url.ID, &CrawlResponse{HTML: html, StatusCode: 200, URL: rawURL})
+	if err != nil {
+		t.Fatalf("StoreBody failed: %v", err)
+	}
+	if err := database.DB.Create(snapshot).Error; err != nil {
+		t.Fatalf("Failed to create snapshot: %v", err)
+	}
+
+	result := models.CrawlResult{
+		URLID:        url.ID,
+		CrawledAt:    time.Now(),
+		SnapshotID:   &snapshot.ID,
+		ChangeStatus: models.ChangeChanged,
+	}
+	if err := database.DB.Create(&result).Error; err != nil {
+		t.Fatalf("Failed to create crawl result: %v", err)
+	}
+	return &result
+}
+
+func TestReparser_RewritesDerivedData(t *testing.T) {
+	cm := setupReparseTest(t)
+	reparser := NewReparser(cm)
+
+	html := `<html><head><title>Stored Page</title></head><body>
+		<h1>One</h1><h2>Two</h2><h2>Three</h2>
+		<a href="/about">About</a>
+		<a href="https://other.example.org/">Other</a>
+	</body></html>`
+	original := seedSnapshot(t, cm, "https://example.com", html)
+
+	job := &models.ReparseJob{BatchSize: 10}
+	database.DB.Create(job)
+	reparser.acquire()
+	reparser.run(job)
+
+	var result models.CrawlResult
+	database.DB.First(&result, original.ID)
+
+	if result.PageTitle == nil || *result.PageTitle != "Stored Page" {
+		t.Errorf("Expected title 'Stored Page', got %v", formatOptionalString(result.PageTitle))
+	}
+	if result.H1Count != 1 || result.H2Count != 2 {
+		t.Errorf("Expected 1 h1 and 2 h2, got %d and %d", result.H1Count, result.H2Count)
+	}
+	if result.InternalLinksCount != 1 || result.ExternalLinksCount != 1 {
+		t.Errorf("Expected 1 internal and 1 external link, got %d and %d",
+			result.InternalLinksCount, result.ExternalLinksCount)
+	}
+	if result.TextHash == nil {
+		t.Error("Expected text hash to be recomputed")
+	}
+	if result.ChangeStatus != models.ChangeChanged {
+		t.Errorf("Re-parsing must not change the change status, got %q", result.ChangeStatus)
+	}
+
+	var links int64
+	database.DB.Model(&models.FoundLink{}).Where("url_id = ?", original.URLID).Count(&links)
+	if links != 2 {
+		t.Errorf("Expected 2 found links, got %d", links)
+	}
+
+	database.DB.First(job, job.ID)
+	if job.Status != models.ReparseCompleted {
+		t.Errorf("Expected job to be completed, got %q", job.Status)
+	}
+	if job.Total != 1 || job.Succeeded != 1 || job.Progress() != 100 {
+		t.Errorf("Unexpected job progress: total=%d succeeded=%d progress=%v", job.Total, job.Succeeded, job.Progress())
+	}
+	if reparser.IsRunning() {
+		t.Error("Job slot should be released after the run")
+	}
+}
+
+func TestReparser_ResumesFromCheckpoint(t *testing.T) {
+	cm := setupReparseTest(t)
+	reparser := NewReparser(cm)
+
+	first := seedSnapshot(t, cm, "https://example.com/a", "<html><head><title>A</title></head></html>")
+	second := seedSnapshot(t, cm, "https://example.com/b", "<html><head><title>B</title></head></html>")
+
+	// Busy URLs are skipped rather than raced with a live crawl
+	third := seedSnapshot(t, cm, "https://example.com/c", "<html><head><title>C</title></head></html>")
+	database.DB.Model(&models.URL{}).Where("id = ?", third.URLID).Update("status", models.StatusRunning)
+
+	// An interrupted job that already processed the first URL
+	job := &models.ReparseJob{
+		Status:    models.ReparseRunning,
+		BatchSize: 1,
+		Processed: 1,
+		Succeeded: 1,
+		LastURLID: first.URLID,
+	}
+	database.DB.Create(job)
+	reparser.acquire()
+	reparser.run(job)
+
+	var firstResult, secondResult models.CrawlResult
+	database.DB.First(&firstResult, first.ID)
+	database.DB.First(&secondResult, second.ID)
+
+	if firstResult.PageTitle != nil {
+		t.Error("URLs before the checkpoint should not be re-parsed")
+	}
+	if secondResult.PageTitle == nil || *secondResult.PageTitle != "B" {
+		t.Errorf("Expected title 'B', got %v", formatOptionalString(secondResult.PageTitle))
+	}
+
+	database.DB.First(job, job.ID)
+	if job.Total != 3 || job.Processed != 3 || job.Succeeded != 2 || job.Skipped != 1 {
+		t.Errorf("Unexpected job counters: total=%d processed=%d succeeded=%d skipped=%d",
+			job.Total, job.Processed, job.Succeeded, job.Skipped)
+	}
+	if job.LastURLID != third.URLID {
+		t.Errorf("Expected checkpoint at URL ID=%d, got %d", third.URLID, job.LastURLID)
+	}
+}
+
+func TestReparser_RecoversFromPanics(t *testing.T) {
+	cm := setupReparseTest(t)
+	cm.parser = &panickingParser{}
+	reparser := NewReparser(cm)
+
+	malformed := seedSnapshot(t, cm, "https://example.com/a", "<html><body><!-- boom --></body></html>")
+	fine := seedSnapshot(t, cm, "https://example.com/b", "<html><head><title>B</title></head></html>")
+
+	job := &models.ReparseJob{BatchSize: 10}
+	database.DB.Create(job)
+	reparser.acquire()
+	reparser.run(job)
+
+	database.DB.First(job, job.ID)
+	if job.Status != models.ReparseCompleted || job.Failed != 1 || job.Succeeded != 1 || job.Processed != 2 {
+		t.Fatalf("Expected a completed job with 1 failed and 1 succeeded URL, got %+v", job)
+	}
+	if job.LastError == nil || !strings.Contains(*job.LastError, "assignment to entry in nil map") {
+		t.Errorf("Expected the panic as the last error, got %v", formatOptionalString(job.LastError))
+	}
+	if job.LastURLID != fine.URLID {
+		t.Errorf("Expected checkpoint past the panicking URL ID=%d, got %d", malformed.URLID, job.LastURLID)
+	}
+
+	var result models.CrawlResult
+	database.DB.First(&result, fine.ID)
+	if result.PageTitle == nil || *result.PageTitle != "B" {
+		t.Errorf("Expected the next URL to be re-parsed, got title %v", formatOptionalString(result.PageTitle))
+	}
+	if reparser.IsRunning() {
+		t.Error("Job slot should be released after the run")
+	}
+}
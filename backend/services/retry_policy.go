@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryRule controls retries for one type of crawl error
+type RetryRule struct {
+	MaxAttempts int           // Total attempts including the first one
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each further retry
+	MaxDelay    time.Duration // Upper bound for the computed delay
+}
+
+// RetryPolicy decides whether a failed crawl is retried and after how long.
+// Rules are keyed on CrawlError.Type; error types without a rule are not retried.
+type RetryPolicy struct {
+	Rules         map[string]RetryRule
+	Jitter        float64       // Random extra delay as a fraction of the computed delay (0.2 = up to +20%)
+	MaxRetryAfter time.Duration // Upper bound for delays requested by a Retry-After header
+}
+
+// DefaultRetryPolicy retries transient network failures and HTTP 5xx/429 responses
+func DefaultRetryPolicy() *RetryPolicy {
+	network := RetryRule{MaxAttempts: 3, BaseDelay: 2 * time.Second, MaxDelay: time.Minute}
+
+	return &RetryPolicy{
+		Rules: map[string]RetryRule{
+			"timeout":          network,
+			"connection_error": network,
+			"network":          network,
+			"read_error":       network,
+			"http_error":       {MaxAttempts: 3, BaseDelay: 5 * time.Second, MaxDelay: 2 * time.Minute},
+		},
+		Jitter:        0.2,
+		MaxRetryAfter: 5 * time.Minute,
+	}
+}
+
+// NextRetry returns the delay before retrying a crawl that failed with err on
+// the given attempt (1 = first attempt), or false if it should not be retried
+func (p *RetryPolicy) NextRetry(err error, attempt int) (time.Duration, bool) {
+	var crawlErr *CrawlError
+	if p == nil || !errors.As(err, &crawlErr) {
+		return 0, false
+	}
+
+	rule, ok := p.Rules[crawlErr.Type]
+	if !ok || attempt >= rule.MaxAttempts {
+		return 0, false
+	}
+
+	// Only server errors and rate limiting are worth retrying; other HTTP errors are permanent
+	if crawlErr.Type == "http_error" && !isRetryableStatus(crawlErr.StatusCode) {
+		return 0, false
+	}
+
+	// Exponential backoff: BaseDelay, 2*BaseDelay, 4*BaseDelay, ...
+	delay := rule.BaseDelay
+	for i := 1; i < attempt && delay < rule.MaxDelay; i++ {
+		delay *= 2
+	}
+	if rule.MaxDelay > 0 && delay > rule.MaxDelay {
+		delay = rule.MaxDelay
+	}
+
+	// Spread retries out so failures from one host do not retry in lockstep
+	if p.Jitter > 0 {
+		delay += time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+
+	// Never retry earlier than the server asked us to
+	if crawlErr.RetryAfter > delay {
+		delay = crawlErr.RetryAfter
+		if p.MaxRetryAfter > 0 && delay > p.MaxRetryAfter {
+			delay = p.MaxRetryAfter
+		}
+	}
+
+	return delay, true
+}
+
+// isRetryableStatus reports whether an HTTP error status is likely to be transient
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
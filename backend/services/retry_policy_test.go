@@ -0,0 +1,91 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_NextRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0 // Deterministic delays
+
+	tests := []struct {
+		name    string
+		err     error
+		attempt int
+		retry   bool
+		delay   time.Duration
+	}{
+		{"timeout first retry", NewCrawlError("timeout", "Request timed out", "", nil), 1, true, 2 * time.Second},
+		{"timeout backs off", NewCrawlError("timeout", "Request timed out", "", nil), 2, true, 4 * time.Second},
+		{"timeout exhausted", NewCrawlError("timeout", "Request timed out", "", nil), 3, false, 0},
+		{"server error", &CrawlError{Type: "http_error", StatusCode: 503}, 1, true, 5 * time.Second},
+		{"rate limited", &CrawlError{Type: "http_error", StatusCode: 429}, 1, true, 5 * time.Second},
+		{"client error", &CrawlError{Type: "http_error", StatusCode: 404}, 1, false, 0},
+		{"permanent type", NewCrawlError("invalid_url", "Invalid URL format", "", nil), 1, false, 0},
+		{"not a crawl error", http.ErrHandlerTimeout, 1, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := policy.NextRetry(tt.err, tt.attempt)
+			if retry != tt.retry {
+				t.Fatalf("Expected retry=%v, got %v", tt.retry, retry)
+			}
+			if delay != tt.delay {
+				t.Errorf("Expected delay %v, got %v", tt.delay, delay)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_RespectsRetryAfter(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+
+	err := &CrawlError{Type: "http_error", StatusCode: 429, RetryAfter: 30 * time.Second}
+	if delay, _ := policy.NextRetry(err, 1); delay != 30*time.Second {
+		t.Errorf("Expected Retry-After delay of 30s, got %v", delay)
+	}
+
+	// Excessive server-requested delays are capped
+	err.RetryAfter = time.Hour
+	if delay, _ := policy.NextRetry(err, 1); delay != policy.MaxRetryAfter {
+		t.Errorf("Expected delay capped at %v, got %v", policy.MaxRetryAfter, delay)
+	}
+}
+
+func TestRetryPolicy_JitterAddsBoundedDelay(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	err := NewCrawlError("network", "Network error occurred", "", nil)
+
+	for i := 0; i < 100; i++ {
+		delay, _ := policy.NextRetry(err, 1)
+		if delay < 2*time.Second || delay > 2*time.Second+400*time.Millisecond {
+			t.Fatalf("Delay %v outside jitter bounds", delay)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 7, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"120", 2 * time.Minute},
+		{"Fri, 04 Jul 2025 12:01:30 GMT", 90 * time.Second},
+		{"Fri, 04 Jul 2025 11:00:00 GMT", 0}, // In the past
+		{"-5", 0},
+		{"soon", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
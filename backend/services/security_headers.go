@@ -0,0 +1,352 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"web-crawler/models"
+)
+
+// SecurityHeaderReport grades the security headers of a single response
+type SecurityHeaderReport struct {
+	Score    int                            `json:"score"` // 0-100
+	Grade    string                         `json:"grade"` // A-F
+	Findings []models.SecurityHeaderFinding `json:"findings"`
+}
+
+// Points available per header; they add up to 100
+const (
+	hstsPoints               = 25
+	cspPoints                = 25
+	frameOptionsPoints       = 15
+	contentTypeOptionsPoints = 15
+	referrerPolicyPoints     = 10
+	permissionsPolicyPoints  = 10
+)
+
+// minHSTSMaxAge is the shortest HSTS max-age considered strong (180 days)
+const minHSTSMaxAge = 15552000
+
+// Limits on the response headers stored with a crawl result. The total keeps
+// the JSON within the 64 KB response_headers TEXT column.
+const (
+	maxStoredHeaderValueLength = 2048
+	maxStoredHeadersSize       = 48 * 1024
+)
+
+// redactedHeaderValue replaces the values of credential headers
+const redactedHeaderValue = "[redacted]"
+
+// credentialHeaders carry session tokens or credentials that must not be
+// stored or served back through the API
+var credentialHeaders = map[string]bool{
+	"Set-Cookie":           true,
+	"Set-Cookie2":          true,
+	"Cookie":               true,
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"X-Api-Key":            true,
+	"X-Auth-Token":         true,
+	"X-Csrf-Token":         true,
+	"X-Xsrf-Token":         true,
+	"X-Amz-Security-Token": true,
+}
+
+// storedResponseHeaders prepares response headers for storage: credential
+// headers keep their name and number of values but not the values, long
+// values are truncated, and headers that would push the JSON past
+// maxStoredHeadersSize are dropped, keeping them in name order
+func storedResponseHeaders(headers http.Header) map[string][]string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stored := make(map[string][]string, len(headers))
+	size := 2 // {}
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		values := make([]string, len(headers[name]))
+		for i, value := range headers[name] {
+			if credentialHeaders[canonical] {
+				values[i] = redactedHeaderValue
+			} else {
+				values[i] = truncateString(value, maxStoredHeaderValueLength)
+			}
+		}
+
+		encodedName, _ := json.Marshal(name)
+		encodedValues, _ := json.Marshal(values)
+		entrySize := len(encodedName) + len(encodedValues) + 2 // colon and comma
+		if size+entrySize > maxStoredHeadersSize {
+			continue
+		}
+		stored[name] = values
+		size += entrySize
+	}
+	return stored
+}
+
+// AuditSecurityHeaders grades the presence and quality of security headers on a response
+func AuditSecurityHeaders(headers http.Header, isHTTPS bool) *SecurityHeaderReport {
+	findings := []models.SecurityHeaderFinding{
+		auditHSTS(headers, isHTTPS),
+		auditCSP(headers),
+		auditFrameOptions(headers),
+		auditContentTypeOptions(headers),
+		auditReferrerPolicy(headers),
+		auditPermissionsPolicy(headers),
+	}
+
+	score := 0
+	for _, finding := range findings {
+		score += finding.Points
+	}
+
+	return &SecurityHeaderReport{
+		Score:    score,
+		Grade:    securityGrade(score),
+		Findings: findings,
+	}
+}
+
+// securityGrade maps a 0-100 score to a letter grade
+func securityGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 75:
+		return "B"
+	case score >= 60:
+		return "C"
+	case score >= 40:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// newFinding builds a finding, awarding points according to its status
+func newFinding(header string, status models.SecurityHeaderStatus, severity, value, message string, maxPoints int) models.SecurityHeaderFinding {
+	finding := models.SecurityHeaderFinding{
+		Header:    header,
+		Status:    status,
+		Severity:  severity,
+		Message:   message,
+		MaxPoints: maxPoints,
+	}
+
+	if value != "" {
+		finding.Value = &value
+	}
+
+	switch status {
+	case models.SecurityHeaderPresent:
+		finding.Points = maxPoints
+	case models.SecurityHeaderWeak:
+		finding.Points = maxPoints / 2
+	}
+
+	return finding
+}
+
+// auditHSTS checks Strict-Transport-Security
+func auditHSTS(headers http.Header, isHTTPS bool) models.SecurityHeaderFinding {
+	const name = "Strict-Transport-Security"
+	value := strings.TrimSpace(headers.Get(name))
+
+	if !isHTTPS {
+		return newFinding(name, models.SecurityHeaderMissing, "high", value,
+			"Page is served over plain HTTP, so HSTS cannot protect it", hstsPoints)
+	}
+
+	if value == "" {
+		return newFinding(name, models.SecurityHeaderMissing, "high", value,
+			"Missing HSTS header; browsers may be downgraded to HTTP", hstsPoints)
+	}
+
+	maxAge := -1
+	for _, directive := range strings.Split(value, ";") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+		if strings.HasPrefix(directive, "max-age=") {
+			if parsed, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(directive, "max-age="), `"`)); err == nil {
+				maxAge = parsed
+			}
+		}
+	}
+
+	switch {
+	case maxAge < 0:
+		return newFinding(name, models.SecurityHeaderMisconfigured, "high", value,
+			"HSTS header has no valid max-age directive", hstsPoints)
+	case maxAge == 0:
+		return newFinding(name, models.SecurityHeaderMisconfigured, "high", value,
+			"HSTS max-age=0 disables the policy", hstsPoints)
+	case maxAge < minHSTSMaxAge:
+		return newFinding(name, models.SecurityHeaderWeak, "medium", value,
+			"HSTS max-age is shorter than 180 days", hstsPoints)
+	}
+
+	return newFinding(name, models.SecurityHeaderPresent, "info", value,
+		"HSTS is enabled with a sufficient max-age", hstsPoints)
+}
+
+// auditCSP checks Content-Security-Policy
+func auditCSP(headers http.Header) models.SecurityHeaderFinding {
+	const name = "Content-Security-Policy"
+	value := strings.TrimSpace(headers.Get(name))
+
+	if value == "" {
+		if reportOnly := strings.TrimSpace(headers.Get("Content-Security-Policy-Report-Only")); reportOnly != "" {
+			return newFinding(name, models.SecurityHeaderWeak, "medium", reportOnly,
+				"CSP is only set in report-only mode and is not enforced", cspPoints)
+		}
+		return newFinding(name, models.SecurityHeaderMissing, "high", value,
+			"Missing Content-Security-Policy header", cspPoints)
+	}
+
+	directives := parseCSP(value)
+	scriptSources, ok := directives["script-src"]
+	if !ok {
+		scriptSources = directives["default-src"]
+	}
+
+	var problems []string
+	for _, source := range scriptSources {
+		switch source {
+		case "'unsafe-inline'":
+			problems = append(problems, "allows 'unsafe-inline' scripts")
+		case "'unsafe-eval'":
+			problems = append(problems, "allows 'unsafe-eval'")
+		case "*", "http:", "https:", "data:":
+			problems = append(problems, "allows scripts from "+source)
+		}
+	}
+
+	if len(scriptSources) == 0 {
+		problems = append(problems, "does not restrict script sources")
+	}
+
+	if len(problems) > 0 {
+		return newFinding(name, models.SecurityHeaderWeak, "medium", value,
+			"CSP "+strings.Join(problems, ", "), cspPoints)
+	}
+
+	return newFinding(name, models.SecurityHeaderPresent, "info", value,
+		"CSP restricts script sources", cspPoints)
+}
+
+// parseCSP splits a Content-Security-Policy value into directive -> sources
+func parseCSP(value string) map[string][]string {
+	directives := make(map[string][]string)
+	for _, part := range strings.Split(value, ";") {
+		fields := strings.Fields(strings.ToLower(part))
+		if len(fields) == 0 {
+			continue
+		}
+		directives[fields[0]] = fields[1:]
+	}
+	return directives
+}
+
+// auditFrameOptions checks X-Frame-Options (or an equivalent CSP frame-ancestors directive)
+func auditFrameOptions(headers http.Header) models.SecurityHeaderFinding {
+	const name = "X-Frame-Options"
+	value := strings.TrimSpace(headers.Get(name))
+
+	if value == "" {
+		if csp := headers.Get("Content-Security-Policy"); csp != "" {
+			if _, ok := parseCSP(csp)["frame-ancestors"]; ok {
+				return newFinding(name, models.SecurityHeaderPresent, "info", "",
+					"Framing is restricted by CSP frame-ancestors", frameOptionsPoints)
+			}
+		}
+		return newFinding(name, models.SecurityHeaderMissing, "medium", value,
+			"Missing X-Frame-Options header; page may be vulnerable to clickjacking", frameOptionsPoints)
+	}
+
+	switch strings.ToUpper(value) {
+	case "DENY", "SAMEORIGIN":
+		return newFinding(name, models.SecurityHeaderPresent, "info", value,
+			"Framing is restricted", frameOptionsPoints)
+	}
+
+	if strings.HasPrefix(strings.ToUpper(value), "ALLOW-FROM") {
+		return newFinding(name, models.SecurityHeaderWeak, "low", value,
+			"ALLOW-FROM is obsolete and ignored by modern browsers; use CSP frame-ancestors", frameOptionsPoints)
+	}
+
+	return newFinding(name, models.SecurityHeaderMisconfigured, "medium", value,
+		"X-Frame-Options must be DENY or SAMEORIGIN", frameOptionsPoints)
+}
+
+// auditContentTypeOptions checks X-Content-Type-Options
+func auditContentTypeOptions(headers http.Header) models.SecurityHeaderFinding {
+	const name = "X-Content-Type-Options"
+	value := strings.TrimSpace(headers.Get(name))
+
+	if value == "" {
+		return newFinding(name, models.SecurityHeaderMissing, "medium", value,
+			"Missing X-Content-Type-Options header; browsers may MIME-sniff responses", contentTypeOptionsPoints)
+	}
+
+	if !strings.EqualFold(value, "nosniff") {
+		return newFinding(name, models.SecurityHeaderMisconfigured, "medium", value,
+			"X-Content-Type-Options must be 'nosniff'", contentTypeOptionsPoints)
+	}
+
+	return newFinding(name, models.SecurityHeaderPresent, "info", value,
+		"MIME sniffing is disabled", contentTypeOptionsPoints)
+}
+
+// auditReferrerPolicy checks Referrer-Policy
+func auditReferrerPolicy(headers http.Header) models.SecurityHeaderFinding {
+	const name = "Referrer-Policy"
+	value := strings.TrimSpace(headers.Get(name))
+
+	if value == "" {
+		return newFinding(name, models.SecurityHeaderMissing, "low", value,
+			"Missing Referrer-Policy header; browser default applies", referrerPolicyPoints)
+	}
+
+	// Browsers use the last policy they understand when several are listed
+	policies := strings.Split(value, ",")
+	policy := strings.ToLower(strings.TrimSpace(policies[len(policies)-1]))
+
+	switch policy {
+	case "no-referrer", "same-origin", "strict-origin", "strict-origin-when-cross-origin":
+		return newFinding(name, models.SecurityHeaderPresent, "info", value,
+			"Referrer information is limited", referrerPolicyPoints)
+	case "origin", "origin-when-cross-origin", "no-referrer-when-downgrade":
+		return newFinding(name, models.SecurityHeaderWeak, "low", value,
+			"Referrer policy '"+policy+"' leaks more than necessary to other origins", referrerPolicyPoints)
+	case "unsafe-url":
+		return newFinding(name, models.SecurityHeaderMisconfigured, "medium", value,
+			"'unsafe-url' sends the full URL, including path and query, to every origin", referrerPolicyPoints)
+	}
+
+	return newFinding(name, models.SecurityHeaderMisconfigured, "low", value,
+		"Unrecognized Referrer-Policy value", referrerPolicyPoints)
+}
+
+// auditPermissionsPolicy checks Permissions-Policy (or the legacy Feature-Policy)
+func auditPermissionsPolicy(headers http.Header) models.SecurityHeaderFinding {
+	const name = "Permissions-Policy"
+	value := strings.TrimSpace(headers.Get(name))
+
+	if value == "" {
+		if legacy := strings.TrimSpace(headers.Get("Feature-Policy")); legacy != "" {
+			return newFinding(name, models.SecurityHeaderWeak, "low", legacy,
+				"Only the deprecated Feature-Policy header is set", permissionsPolicyPoints)
+		}
+		return newFinding(name, models.SecurityHeaderMissing, "low", value,
+			"Missing Permissions-Policy header; browser features are not restricted", permissionsPolicyPoints)
+	}
+
+	return newFinding(name, models.SecurityHeaderPresent, "info", value,
+		"Browser features are restricted", permissionsPolicyPoints)
+}
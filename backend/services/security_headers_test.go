@@ -0,0 +1,140 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"web-crawler/models"
+)
+
+func TestAuditSecurityHeaders_AllPresent(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+	headers.Set("Content-Security-Policy", "default-src 'self'; script-src 'self' https://cdn.example.com")
+	headers.Set("X-Frame-Options", "DENY")
+	headers.Set("X-Content-Type-Options", "nosniff")
+	headers.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+	headers.Set("Permissions-Policy", "geolocation=(), camera=()")
+
+	report := AuditSecurityHeaders(headers, true)
+
+	if report.Score != 100 {
+		t.Errorf("Expected score 100, got %d", report.Score)
+	}
+
+	if report.Grade != "A" {
+		t.Errorf("Expected grade A, got %s", report.Grade)
+	}
+
+	for _, finding := range report.Findings {
+		if finding.Status != models.SecurityHeaderPresent {
+			t.Errorf("Expected %s to be present, got %s (%s)", finding.Header, finding.Status, finding.Message)
+		}
+	}
+}
+
+func TestAuditSecurityHeaders_NoneOverHTTP(t *testing.T) {
+	report := AuditSecurityHeaders(http.Header{}, false)
+
+	if report.Score != 0 {
+		t.Errorf("Expected score 0, got %d", report.Score)
+	}
+
+	if report.Grade != "F" {
+		t.Errorf("Expected grade F, got %s", report.Grade)
+	}
+
+	if len(report.Findings) != 6 {
+		t.Errorf("Expected 6 findings, got %d", len(report.Findings))
+	}
+}
+
+func TestAuditSecurityHeaders_Quality(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		value    string
+		expected models.SecurityHeaderStatus
+	}{
+		{"short HSTS max-age", "Strict-Transport-Security", "max-age=3600", models.SecurityHeaderWeak},
+		{"HSTS disabled", "Strict-Transport-Security", "max-age=0", models.SecurityHeaderMisconfigured},
+		{"CSP with unsafe-inline", "Content-Security-Policy", "default-src 'self' 'unsafe-inline'", models.SecurityHeaderWeak},
+		{"CSP without script restriction", "Content-Security-Policy", "img-src 'self'", models.SecurityHeaderWeak},
+		{"obsolete ALLOW-FROM", "X-Frame-Options", "ALLOW-FROM https://example.com", models.SecurityHeaderWeak},
+		{"invalid frame options", "X-Frame-Options", "ALLOWALL", models.SecurityHeaderMisconfigured},
+		{"invalid content type options", "X-Content-Type-Options", "sniff", models.SecurityHeaderMisconfigured},
+		{"unsafe-url referrer", "Referrer-Policy", "unsafe-url", models.SecurityHeaderMisconfigured},
+		{"last referrer policy wins", "Referrer-Policy", "unsafe-url, no-referrer", models.SecurityHeaderPresent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := http.Header{}
+			headers.Set(tc.header, tc.value)
+
+			report := AuditSecurityHeaders(headers, true)
+
+			for _, finding := range report.Findings {
+				if finding.Header != tc.header {
+					continue
+				}
+				if finding.Status != tc.expected {
+					t.Errorf("Expected %s to be %s, got %s (%s)", tc.header, tc.expected, finding.Status, finding.Message)
+				}
+				return
+			}
+			t.Errorf("No finding reported for %s", tc.header)
+		})
+	}
+}
+
+func TestAuditSecurityHeaders_FrameAncestorsSatisfiesFrameOptions(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+
+	report := AuditSecurityHeaders(headers, true)
+
+	for _, finding := range report.Findings {
+		if finding.Header == "X-Frame-Options" && finding.Status != models.SecurityHeaderPresent {
+			t.Errorf("Expected CSP frame-ancestors to satisfy X-Frame-Options, got %s", finding.Status)
+		}
+	}
+}
+
+func TestStoredResponseHeaders_RedactsAndBounds(t *testing.T) {
+	headers := http.Header{}
+	headers.Add("Set-Cookie", "session=secret; HttpOnly")
+	headers.Add("Set-Cookie", "tracking=abc")
+	headers.Set("Content-Type", "text/html")
+	headers.Set("Link", strings.Repeat("a", maxStoredHeaderValueLength+100))
+	for i := 0; i < 100; i++ {
+		headers.Set(fmt.Sprintf("X-Filler-%03d", i), strings.Repeat("b", 1000))
+	}
+
+	stored := storedResponseHeaders(headers)
+
+	cookies := stored["Set-Cookie"]
+	if len(cookies) != 2 || cookies[0] != redactedHeaderValue || cookies[1] != redactedHeaderValue {
+		t.Errorf("Expected both cookies to be redacted, got %v", cookies)
+	}
+	if got := stored["Content-Type"]; len(got) != 1 || got[0] != "text/html" {
+		t.Errorf("Expected Content-Type to be kept, got %v", got)
+	}
+	if got := stored["Link"]; len(got) != 1 || len(got[0]) != maxStoredHeaderValueLength {
+		t.Errorf("Expected Link to be truncated to %d bytes", maxStoredHeaderValueLength)
+	}
+
+	encoded, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatalf("Failed to encode headers: %v", err)
+	}
+	if len(encoded) > maxStoredHeadersSize {
+		t.Errorf("Expected at most %d bytes of headers, got %d", maxStoredHeadersSize, len(encoded))
+	}
+	if len(stored) == len(headers) {
+		t.Error("Expected headers beyond the size limit to be dropped")
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+)
+
+// RetentionPolicy controls how many raw HTML snapshots are kept.
+// Zero values mean "no limit". The latest snapshot of each URL is always kept.
+type RetentionPolicy struct {
+	MaxSnapshotsPerURL int           // Keep at most this many snapshots per URL
+	MaxAge             time.Duration // Drop snapshots fetched longer ago than this
+}
+
+// DefaultRetentionPolicy returns the default snapshot retention policy
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		MaxSnapshotsPerURL: 10,
+		MaxAge:             90 * 24 * time.Hour,
+	}
+}
+
+// SnapshotStore keeps gzip-compressed raw HTML bodies in a BlobStore,
+// keyed by content hash, with one PageSnapshot row per crawl
+type SnapshotStore struct {
+	blobs     BlobStore
+	retention RetentionPolicy
+	mu        sync.Mutex // Serializes blob removal with RestoreBody
+}
+
+// NewSnapshotStore creates a snapshot store on top of the given blob store
+func NewSnapshotStore(blobs BlobStore, retention RetentionPolicy) *SnapshotStore {
+	return &SnapshotStore{
+		blobs:     blobs,
+		retention: retention,
+	}
+}
+
+// snapshotKey returns the blob key for a content hash
+func snapshotKey(contentHash string) string {
+	return contentHash + ".html.gz"
+}
+
+// StoreBody writes a response body to the blob store if it is not already there.
+// It returns an unsaved PageSnapshot describing the body; the caller saves it
+// alongside the crawl result so both are committed together.
+func (s *SnapshotStore) StoreBody(urlID uint, response *CrawlResponse) (*models.PageSnapshot, error) {
+	contentHash := sha256Hex(response.HTML)
+	key := snapshotKey(contentHash)
+
+	// Unchanged pages are the common case on re-crawls, so only compress new bodies
+	compressedSize, err := s.blobs.Size(key)
+	if errors.Is(err, ErrBlobNotFound) {
+		compressedSize, err = s.writeBody(key, response.HTML)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store snapshot blob: %w", err)
+	}
+
+	return &models.PageSnapshot{
+		URLID:          urlID,
+		ContentHash:    contentHash,
+		StorageKey:     key,
+		Size:           int64(len(response.HTML)),
+		CompressedSize: compressedSize,
+		ContentType:    response.ContentType,
+		StatusCode:     response.StatusCode,
+		FinalURL:       response.URL,
+		FetchedAt:      time.Now().UTC(),
+	}, nil
+}
+
+// writeBody compresses a body and stores it under key, returning the stored size
+func (s *SnapshotStore) writeBody(key, html string) (int64, error) {
+	compressed, err := gzipBytes([]byte(html))
+	if err != nil {
+		return 0, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+	if err := s.blobs.Put(key, compressed); err != nil {
+		return 0, err
+	}
+	return int64(len(compressed)), nil
+}
+
+// RestoreBody writes the blob of a committed snapshot again if it is missing.
+// StoreBody runs before the crawl commits, so deleting the last other URL
+// sharing the blob in between removes it; once the snapshot row is committed,
+// RemoveUnreferencedBlobs sees the reference and keeps the blob.
+func (s *SnapshotStore) RestoreBody(snapshot *models.PageSnapshot, response *CrawlResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists, err := s.blobs.Exists(snapshot.StorageKey)
+	if err != nil {
+		return fmt.Errorf("failed to check snapshot blob: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := s.writeBody(snapshot.StorageKey, response.HTML); err != nil {
+		return fmt.Errorf("failed to restore snapshot blob: %w", err)
+	}
+	log.Printf("Restored snapshot blob %s removed while URL ID=%d was being crawled", snapshot.StorageKey, snapshot.URLID)
+	return nil
+}
+
+// LoadBody returns the decompressed HTML of a snapshot
+func (s *SnapshotStore) LoadBody(snapshot *models.PageSnapshot) (string, error) {
+	compressed, err := s.blobs.Get(snapshot.StorageKey)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer reader.Close()
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// ApplyRetention deletes a URL's snapshots that fall outside the retention
+// policy, along with any blobs no longer referenced by a snapshot
+func (s *SnapshotStore) ApplyRetention(urlID uint) error {
+	var snapshots []models.PageSnapshot
+	if err := database.DB.Where("url_id = ?", urlID).Order("fetched_at DESC, id DESC").Find(&snapshots).Error; err != nil {
+		return fmt.Errorf("failed to load snapshots: %w", err)
+	}
+
+	cutoff := time.Time{}
+	if s.retention.MaxAge > 0 {
+		cutoff = time.Now().UTC().Add(-s.retention.MaxAge)
+	}
+
+	var expired []models.PageSnapshot
+	for i, snapshot := range snapshots {
+		if i == 0 {
+			continue // Always keep the latest snapshot
+		}
+		tooMany := s.retention.MaxSnapshotsPerURL > 0 && i >= s.retention.MaxSnapshotsPerURL
+		tooOld := !cutoff.IsZero() && snapshot.FetchedAt.Before(cutoff)
+		if tooMany || tooOld {
+			expired = append(expired, snapshot)
+		}
+	}
+
+	if len(expired) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(expired))
+	keys := make([]string, len(expired))
+	for i, snapshot := range expired {
+		ids[i] = snapshot.ID
+		keys[i] = snapshot.StorageKey
+	}
+
+	if err := database.DB.Where("id IN ?", ids).Delete(&models.PageSnapshot{}).Error; err != nil {
+		return fmt.Errorf("failed to delete expired snapshots: %w", err)
+	}
+
+	// Blobs are shared between identical pages, so only delete unreferenced ones
+	removedBlobs, err := s.RemoveUnreferencedBlobs(keys)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Snapshot retention for URL ID=%d: removed %d snapshots, %d blobs", urlID, len(expired), removedBlobs)
+	return nil
+}
+
+// StorageKeys returns the distinct blob keys of the given URLs' snapshots
+func (s *SnapshotStore) StorageKeys(urlIDs []uint) ([]string, error) {
+	var keys []string
+	if err := database.DB.Model(&models.PageSnapshot{}).Where("url_id IN ?", urlIDs).Distinct().Pluck("storage_key", &keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to load snapshot storage keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RemoveUnreferencedBlobs deletes the blobs of the given keys that no snapshot
+// references anymore and returns how many were removed. Blobs are shared
+// between identical pages, so a key may still be in use by another URL.
+func (s *SnapshotStore) RemoveUnreferencedBlobs(keys []string) (int, error) {
+	// Counting and deleting must not interleave with RestoreBody
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removedBlobs := 0
+	for _, key := range keys {
+		var references int64
+		if err := database.DB.Model(&models.PageSnapshot{}).Where("storage_key = ?", key).Count(&references).Error; err != nil {
+			return removedBlobs, fmt.Errorf("failed to count snapshot references: %w", err)
+		}
+		if references > 0 {
+			continue
+		}
+		if err := s.blobs.Delete(key); err != nil {
+			log.Printf("Failed to delete snapshot blob %s: %v", key, err)
+			continue
+		}
+		removedBlobs++
+	}
+	return removedBlobs, nil
+}
+
+// gzipBytes compresses data with gzip
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"web-crawler/database"
+	"web-crawler/models"
+)
+
+func TestFileBlobStore(t *testing.T) {
+	store, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+
+	key := "abcdef0123.html.gz"
+
+	if exists, _ := store.Exists(key); exists {
+		t.Error("Blob should not exist before Put")
+	}
+
+	if _, err := store.Get(key); err != ErrBlobNotFound {
+		t.Errorf("Expected ErrBlobNotFound, got %v", err)
+	}
+
+	if err := store.Put(key, []byte("first")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	// Content-addressed blobs are immutable: a second Put is a no-op
+	if err := store.Put(key, []byte("second")); err != nil {
+		t.Fatalf("Second Put failed: %v", err)
+	}
+
+	data, err := store.Get(key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(data) != "first" {
+		t.Errorf("Expected original content, got %q", data)
+	}
+	if size, err := store.Size(key); err != nil || size != int64(len("first")) {
+		t.Errorf("Expected size %d, got %d (%v)", len("first"), size, err)
+	}
+
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if exists, _ := store.Exists(key); exists {
+		t.Error("Blob should not exist after Delete")
+	}
+	if _, err := store.Size(key); err != ErrBlobNotFound {
+		t.Errorf("Expected ErrBlobNotFound for a deleted blob, got %v", err)
+	}
+
+	// Keys must not escape the store directory
+	if err := store.Put("../escape", []byte("x")); err == nil {
+		t.Error("Expected invalid key to be rejected")
+	}
+}
+
+func TestSnapshotStore_StoreAndLoad(t *testing.T) {
+	blobs, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+	store := NewSnapshotStore(blobs, DefaultRetentionPolicy())
+
+	html := "<html><body>" + string(make([]byte, 1000)) + "</body></html>"
+	response := &CrawlResponse{HTML: html, StatusCode: 200, ContentType: "text/html", URL: "https://example.com"}
+
+	first, err := store.StoreBody(1, response)
+	if err != nil {
+		t.Fatalf("StoreBody failed: %v", err)
+	}
+
+	second, err := store.StoreBody(2, response)
+	if err != nil {
+		t.Fatalf("StoreBody failed: %v", err)
+	}
+
+	if first.StorageKey != second.StorageKey {
+		t.Error("Identical bodies should share a storage key")
+	}
+	if first.CompressedSize != second.CompressedSize {
+		t.Errorf("Expected the stored blob's size for an unchanged body, got %d and %d", first.CompressedSize, second.CompressedSize)
+	}
+
+	if first.CompressedSize >= first.Size {
+		t.Errorf("Expected compression, got %d bytes from %d", first.CompressedSize, first.Size)
+	}
+
+	body, err := store.LoadBody(first)
+	if err != nil {
+		t.Fatalf("LoadBody failed: %v", err)
+	}
+	if body != html {
+		t.Error("Loaded body does not match stored body")
+	}
+}
+
+func TestSnapshotStore_ApplyRetention(t *testing.T) {
+	setupTestDB(t, &models.PageSnapshot{})
+
+	blobs, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+	store := NewSnapshotStore(blobs, RetentionPolicy{MaxSnapshotsPerURL: 2, MaxAge: 24 * time.Hour})
+
+	// Four crawls of URL 1; the oldest two share a body with URL 2
+	bodies := []string{"<p>shared</p>", "<p>shared</p>", "<p>v3</p>", "<p>v4</p>"}
+	var stored []*models.PageSnapshot
+	for i, body := range bodies {
+		snapshot, err := store.StoreBody(1, &CrawlResponse{HTML: body})
+		if err != nil {
+			t.Fatalf("StoreBody failed: %v", err)
+		}
+		snapshot.FetchedAt = time.Now().UTC().Add(time.Duration(i-len(bodies)) * time.Hour)
+		database.DB.Create(snapshot)
+		stored = append(stored, snapshot)
+	}
+
+	other, _ := store.StoreBody(2, &CrawlResponse{HTML: "<p>shared</p>"})
+	database.DB.Create(other)
+
+	if err := store.ApplyRetention(1); err != nil {
+		t.Fatalf("ApplyRetention failed: %v", err)
+	}
+
+	var remaining int64
+	database.DB.Model(&models.PageSnapshot{}).Where("url_id = ?", 1).Count(&remaining)
+	if remaining != 2 {
+		t.Errorf("Expected 2 snapshots to remain, got %d", remaining)
+	}
+
+	// The shared blob is still referenced by URL 2 and must survive
+	if exists, _ := blobs.Exists(stored[0].StorageKey); !exists {
+		t.Error("Shared blob should not be deleted while still referenced")
+	}
+
+	if _, err := store.LoadBody(stored[3]); err != nil {
+		t.Errorf("Latest snapshot should still be loadable: %v", err)
+	}
+}
+
+func TestSnapshotStore_RemoveUnreferencedBlobs(t *testing.T) {
+	setupTestDB(t, &models.PageSnapshot{})
+
+	blobs, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+	store := NewSnapshotStore(blobs, DefaultRetentionPolicy())
+
+	// URL 1 has its own body and one shared with URL 2
+	var stored []*models.PageSnapshot
+	for _, crawl := range []struct {
+		urlID uint
+		body  string
+	}{{1, "<p>own</p>"}, {1, "<p>shared</p>"}, {1, "<p>own</p>"}, {2, "<p>shared</p>"}} {
+		snapshot, err := store.StoreBody(crawl.urlID, &CrawlResponse{HTML: crawl.body})
+		if err != nil {
+			t.Fatalf("StoreBody failed: %v", err)
+		}
+		database.DB.Create(snapshot)
+		stored = append(stored, snapshot)
+	}
+
+	keys, err := store.StorageKeys([]uint{1})
+	if err != nil {
+		t.Fatalf("StorageKeys failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 distinct storage keys, got %v", keys)
+	}
+
+	// Deleting the URL cascades to its snapshots
+	database.DB.Where("url_id = ?", 1).Delete(&models.PageSnapshot{})
+
+	removed, err := store.RemoveUnreferencedBlobs(keys)
+	if err != nil {
+		t.Fatalf("RemoveUnreferencedBlobs failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 blob to be removed, got %d", removed)
+	}
+	if exists, _ := blobs.Exists(stored[0].StorageKey); exists {
+		t.Error("Blob of the deleted URL should be removed")
+	}
+	if exists, _ := blobs.Exists(stored[3].StorageKey); !exists {
+		t.Error("Shared blob should not be deleted while still referenced")
+	}
+}
+
+func TestSnapshotStore_RestoresBlobRemovedBeforeCommit(t *testing.T) {
+	setupTestDB(t, &models.PageSnapshot{})
+
+	blobs, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+	store := NewSnapshotStore(blobs, DefaultRetentionPolicy())
+	response := &CrawlResponse{HTML: "<p>shared</p>"}
+
+	existing, _ := store.StoreBody(1, response)
+	database.DB.Create(existing)
+
+	// URL 2 is crawled: its body is already stored, so StoreBody writes nothing
+	crawled, err := store.StoreBody(2, response)
+	if err != nil {
+		t.Fatalf("StoreBody failed: %v", err)
+	}
+
+	// URL 1 is deleted before URL 2's crawl commits, taking the blob with it
+	database.DB.Where("url_id = ?", 1).Delete(&models.PageSnapshot{})
+	if removed, _ := store.RemoveUnreferencedBlobs([]string{existing.StorageKey}); removed != 1 {
+		t.Fatalf("Expected the blob to be removed, got %d", removed)
+	}
+
+	database.DB.Create(crawled)
+	if err := store.RestoreBody(crawled, response); err != nil {
+		t.Fatalf("RestoreBody failed: %v", err)
+	}
+
+	body, err := store.LoadBody(crawled)
+	if err != nil || body != response.HTML {
+		t.Errorf("Expected the committed snapshot to be loadable, got %q (%v)", body, err)
+	}
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"web-crawler/models"
+
+	"golang.org/x/net/html"
+)
+
+// StructuredDataInfo is a JSON-LD block, or a top-level microdata or RDFa item
+type StructuredDataInfo struct {
+	Format    models.StructuredDataFormat `json:"format"`
+	Types     []string                    `json:"types"`     // Distinct schema.org types, including nested items
+	Payload   string                      `json:"payload"`   // Raw JSON-LD, or the item as JSON
+	Truncated bool                        `json:"truncated"` // Payload was cut to fit the size limits
+	Error     string                      `json:"error"`     // Set when a JSON-LD block is not valid JSON
+}
+
+// Limits that keep a page from storing unbounded structured data
+const (
+	maxStructuredDataItems  = 50        // Items kept per page
+	maxStructuredDataLength = 256 << 10 // Bytes kept per payload
+	maxStructuredDataTotal  = 1 << 20   // Payload bytes kept per page, so a page's items fit in one insert
+)
+
+// schemaOrgPrefixes are stripped from types so "https://schema.org/Product" and "Product" compare equal
+var schemaOrgPrefixes = []string{"http://schema.org/", "https://schema.org/", "schema:"}
+
+// itemSyntax describes the attributes of an HTML-embedded structured data syntax
+type itemSyntax struct {
+	format    models.StructuredDataFormat
+	scopeAttr string // Starts an item
+	typeAttr  string // Space-separated item types
+	propAttr  string // Space-separated property names
+}
+
+var (
+	microdataSyntax = itemSyntax{models.FormatMicrodata, "itemscope", "itemtype", "itemprop"}
+	rdfaSyntax      = itemSyntax{models.FormatRDFa, "typeof", "typeof", "property"}
+)
+
+// addStructuredData records an item, up to maxStructuredDataItems per page. The
+// payload is truncated to maxStructuredDataLength and to what is left of the
+// page's maxStructuredDataTotal; a truncated payload is no longer valid JSON.
+func (d *ParsedData) addStructuredData(item StructuredDataInfo) {
+	if len(d.StructuredData) >= maxStructuredDataItems {
+		return
+	}
+
+	remaining := maxStructuredDataTotal
+	for _, kept := range d.StructuredData {
+		remaining -= len(kept.Payload)
+	}
+	if limit := min(maxStructuredDataLength, remaining); len(item.Payload) > limit {
+		item.Payload = truncateString(item.Payload, limit)
+		item.Truncated = true
+	}
+	d.StructuredData = append(d.StructuredData, item)
+}
+
+// SchemaTypes returns the distinct schema.org types found on the page, sorted
+func (d *ParsedData) SchemaTypes() []string {
+	seen := make(map[string]bool)
+	types := make([]string, 0)
+	for _, item := range d.StructuredData {
+		for _, schemaType := range item.Types {
+			if !seen[schemaType] {
+				seen[schemaType] = true
+				types = append(types, schemaType)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// extractJSONLD reads a <script type="application/ld+json"> block. Invalid JSON
+// is kept with its error so broken markup can be audited.
+func (p *HTMLParser) extractJSONLD(n *html.Node) StructuredDataInfo {
+	var raw strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			raw.WriteString(c.Data)
+		}
+	}
+
+	item := StructuredDataInfo{
+		Format:  models.FormatJSONLD,
+		Types:   []string{},
+		Payload: strings.TrimSpace(raw.String()),
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(item.Payload), &value); err != nil {
+		item.Error = fmt.Sprintf("invalid JSON-LD: %v", err)
+		return item
+	}
+
+	types := newTypeSet()
+	collectJSONLDTypes(value, types)
+	item.Types = types.list
+	return item
+}
+
+// collectJSONLDTypes adds the @type of every object in a JSON-LD value, including
+// @graph members and nested objects
+func collectJSONLDTypes(value interface{}, types *typeSet) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t := v["@type"].(type) {
+		case string:
+			types.add(t)
+		case []interface{}:
+			for _, entry := range t {
+				if s, ok := entry.(string); ok {
+					types.add(s)
+				}
+			}
+		}
+		// Visit keys in order so the types come out the same on every parse
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			collectJSONLDTypes(v[key], types)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectJSONLDTypes(child, types)
+		}
+	}
+}
+
+// structuredDataSyntax returns the syntax of an element that starts a top-level
+// item. Elements that are also a property belong to an enclosing item.
+func structuredDataSyntax(n *html.Node) (itemSyntax, bool) {
+	for _, syntax := range []itemSyntax{microdataSyntax, rdfaSyntax} {
+		if _, scope := lookupAttr(n, syntax.scopeAttr); !scope {
+			continue
+		}
+		if _, nested := lookupAttr(n, syntax.propAttr); !nested {
+			return syntax, true
+		}
+	}
+	return itemSyntax{}, false
+}
+
+// extractItem serializes a top-level microdata or RDFa item as JSON
+func (p *HTMLParser) extractItem(n *html.Node, syntax itemSyntax, baseURL string) StructuredDataInfo {
+	types := newTypeSet()
+	value := p.itemValue(n, syntax, baseURL, types)
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return StructuredDataInfo{Format: syntax.format, Types: types.list, Error: err.Error()}
+	}
+
+	return StructuredDataInfo{
+		Format:  syntax.format,
+		Types:   types.list,
+		Payload: string(payload),
+	}
+}
+
+// itemValue builds {"@type": [...], "properties": {name: [values]}} for an item
+func (p *HTMLParser) itemValue(n *html.Node, syntax itemSyntax, baseURL string, types *typeSet) map[string]interface{} {
+	itemTypes := strings.Fields(getAttr(n, syntax.typeAttr))
+	for _, itemType := range itemTypes {
+		types.add(itemType)
+	}
+
+	properties := make(map[string][]interface{})
+	p.collectItemProperties(n, syntax, baseURL, types, properties)
+
+	return map[string]interface{}{
+		"@type":      itemTypes,
+		"properties": properties,
+	}
+}
+
+// collectItemProperties walks an item's descendants for properties, without
+// entering nested items, which collect their own
+func (p *HTMLParser) collectItemProperties(n *html.Node, syntax itemSyntax, baseURL string, types *typeSet, properties map[string][]interface{}) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+
+		_, isItem := lookupAttr(c, syntax.scopeAttr)
+		if names := strings.Fields(getAttr(c, syntax.propAttr)); len(names) > 0 {
+			var value interface{}
+			if isItem {
+				value = p.itemValue(c, syntax, baseURL, types)
+			} else {
+				value = p.propertyValue(c, baseURL)
+			}
+			for _, name := range names {
+				properties[name] = append(properties[name], value)
+			}
+		}
+
+		if !isItem {
+			p.collectItemProperties(c, syntax, baseURL, types, properties)
+		}
+	}
+}
+
+// propertyValue reads a property the way microdata defines it: from content,
+// from the URL of link-like elements, from machine-readable attributes, or from the text
+func (p *HTMLParser) propertyValue(n *html.Node, baseURL string) string {
+	if content, ok := lookupAttr(n, "content"); ok {
+		return strings.TrimSpace(content)
+	}
+
+	switch strings.ToLower(n.Data) {
+	case "a", "area", "link":
+		return p.resolveURL(strings.TrimSpace(getAttr(n, "href")), baseURL)
+	case "img", "audio", "video", "source", "iframe", "embed", "track":
+		return p.resolveURL(strings.TrimSpace(getAttr(n, "src")), baseURL)
+	case "object":
+		return p.resolveURL(strings.TrimSpace(getAttr(n, "data")), baseURL)
+	case "time":
+		if datetime, ok := lookupAttr(n, "datetime"); ok {
+			return strings.TrimSpace(datetime)
+		}
+	case "data", "meter":
+		return strings.TrimSpace(getAttr(n, "value"))
+	}
+
+	return strings.Join(strings.Fields(p.extractTextContent(n)), " ")
+}
+
+// typeSet collects distinct schema.org types in the order they are found
+type typeSet struct {
+	seen map[string]bool
+	list []string
+}
+
+// newTypeSet creates an empty type set
+func newTypeSet() *typeSet {
+	return &typeSet{seen: make(map[string]bool), list: []string{}}
+}
+
+// add records a type, without its schema.org prefix
+func (s *typeSet) add(schemaType string) {
+	schemaType = strings.TrimSpace(schemaType)
+	for _, prefix := range schemaOrgPrefixes {
+		if len(schemaType) > len(prefix) && strings.EqualFold(schemaType[:len(prefix)], prefix) {
+			schemaType = schemaType[len(prefix):]
+			break
+		}
+	}
+	if schemaType != "" && !s.seen[schemaType] {
+		s.seen[schemaType] = true
+		s.list = append(s.list, schemaType)
+	}
+}
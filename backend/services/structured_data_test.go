@@ -0,0 +1,149 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"web-crawler/models"
+)
+
+func TestHTMLParser_StructuredData(t *testing.T) {
+	parser := NewHTMLParser()
+
+	testHTML := `<html><head>
+		<script type="application/ld+json">
+		{
+			"@context": "https://schema.org",
+			"@graph": [
+				{"@type": "WebSite", "name": "Shop"},
+				{"@type": ["Product", "http://schema.org/IndividualProduct"], "offers": {"@type": "Offer", "price": "9.99"}}
+			]
+		}
+		</script>
+		<script type="application/ld+json">{"@type": "BreadcrumbList",}</script>
+		<script type="text/javascript">var x = {"@type": "Ignored"};</script>
+	</head><body>
+		<div itemscope itemtype="https://schema.org/Recipe">
+			<h1 itemprop="name">Pancakes</h1>
+			<img itemprop="image" src="/pancakes.jpg">
+			<time itemprop="totalTime" datetime="PT20M">20 minutes</time>
+			<div itemprop="author" itemscope itemtype="https://schema.org/Person">
+				<span itemprop="name">Ada</span>
+			</div>
+			<meta itemprop="recipeYield" content="4">
+		</div>
+		<div vocab="https://schema.org/" typeof="Event">
+			<span property="name">Launch</span>
+			<a property="url" href="/launch">Details</a>
+		</div>
+	</body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com/page")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	items := result.StructuredData
+	if len(items) != 4 {
+		t.Fatalf("Expected 4 structured data items, got %d", len(items))
+	}
+
+	graph := items[0]
+	if graph.Format != models.FormatJSONLD || graph.Error != "" || !strings.Contains(graph.Payload, `"@graph"`) {
+		t.Errorf("Unexpected JSON-LD item: %+v", graph)
+	}
+	if strings.Join(graph.Types, ",") != "WebSite,Product,IndividualProduct,Offer" {
+		t.Errorf("Expected 4 types from the graph, got %v", graph.Types)
+	}
+
+	broken := items[1]
+	if broken.Error == "" || broken.Payload != `{"@type": "BreadcrumbList",}` || len(broken.Types) != 0 {
+		t.Errorf("Expected the invalid block to be kept with an error, got %+v", broken)
+	}
+	if len(result.ParseErrors) != 1 || !strings.HasPrefix(result.ParseErrors[0], "invalid JSON-LD") {
+		t.Errorf("Expected the invalid block in the parse errors, got %v", result.ParseErrors)
+	}
+
+	recipe := items[2]
+	if recipe.Format != models.FormatMicrodata || strings.Join(recipe.Types, ",") != "Recipe,Person" {
+		t.Errorf("Unexpected microdata item: %+v", recipe)
+	}
+	var payload struct {
+		Type       []string                     `json:"@type"`
+		Properties map[string][]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(recipe.Payload), &payload); err != nil {
+		t.Fatalf("Expected the microdata payload to be JSON: %v", err)
+	}
+	expected := map[string]string{
+		"name":        `"Pancakes"`,
+		"image":       `"https://example.com/pancakes.jpg"`,
+		"totalTime":   `"PT20M"`,
+		"recipeYield": `"4"`,
+	}
+	for name, value := range expected {
+		if values := payload.Properties[name]; len(values) != 1 || string(values[0]) != value {
+			t.Errorf("Property %s: expected %s, got %s", name, value, values)
+		}
+	}
+	if author := payload.Properties["author"]; len(author) != 1 || !strings.Contains(string(author[0]), `"Ada"`) {
+		t.Errorf("Expected the nested author item, got %s", author)
+	}
+
+	event := items[3]
+	if event.Format != models.FormatRDFa || len(event.Types) != 1 || event.Types[0] != "Event" ||
+		!strings.Contains(event.Payload, `"https://example.com/launch"`) {
+		t.Errorf("Unexpected RDFa item: %+v", event)
+	}
+
+	types := result.SchemaTypes()
+	if strings.Join(types, ",") != "Event,IndividualProduct,Offer,Person,Product,Recipe,WebSite" {
+		t.Errorf("Unexpected page types: %v", types)
+	}
+}
+
+func TestParsedData_BoundsStructuredDataPayloads(t *testing.T) {
+	parser := NewHTMLParser()
+
+	// Five valid JSON-LD blocks of just under the per-payload limit, over the per-page total
+	block := `{"@type": "Product", "description": "` + strings.Repeat("x", maxStructuredDataLength-100) + `"}`
+	testHTML := `<html><head>` +
+		strings.Repeat(`<script type="application/ld+json">`+block+`</script>`, 5) +
+		`<script type="application/ld+json">{"@type": "Product", "description": "` + strings.Repeat("y", maxStructuredDataLength) + `"}</script>` +
+		`</head><body></body></html>`
+
+	result, err := parser.Parse(testHTML, "https://example.com/")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	items := result.StructuredData
+	if len(items) != 6 {
+		t.Fatalf("Expected 6 items, got %d", len(items))
+	}
+	total := 0
+	for i, item := range items {
+		total += len(item.Payload)
+		if item.Error != "" || len(item.Types) != 1 || item.Types[0] != "Product" {
+			t.Errorf("Item %d: expected a valid Product regardless of truncation, got error %q and types %v", i, item.Error, item.Types)
+		}
+		if len(item.Payload) > maxStructuredDataLength {
+			t.Errorf("Item %d: expected at most %d payload bytes, got %d", i, maxStructuredDataLength, len(item.Payload))
+		}
+	}
+	if total > maxStructuredDataTotal {
+		t.Errorf("Expected at most %d payload bytes per page, got %d", maxStructuredDataTotal, total)
+	}
+	for i := 0; i < 4; i++ {
+		if items[i].Truncated || items[i].Payload != block {
+			t.Errorf("Item %d: expected the whole payload, got truncated=%v", i, items[i].Truncated)
+		}
+	}
+	if !items[4].Truncated || items[4].Payload == block {
+		t.Error("Expected the item over the page total to be truncated")
+	}
+	if !items[5].Truncated || items[5].Payload != "" {
+		t.Errorf("Expected nothing left of the page total for the last item, got %d bytes", len(items[5].Payload))
+	}
+}
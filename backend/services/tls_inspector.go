@@ -0,0 +1,61 @@
+package services
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"web-crawler/models"
+)
+
+// TLSInfo describes the TLS connection used to fetch a page
+type TLSInfo struct {
+	Version      string                   // Negotiated protocol version, e.g. "TLS 1.3"
+	CipherSuite  string                   // Negotiated cipher suite name
+	ServerName   string                   // SNI server name
+	Certificates []models.CertificateInfo // Peer chain, leaf certificate first
+}
+
+// Leaf returns the server's own certificate, or nil if the chain is empty
+func (t *TLSInfo) Leaf() *models.CertificateInfo {
+	if len(t.Certificates) == 0 {
+		return nil
+	}
+	return &t.Certificates[0]
+}
+
+// inspectTLS extracts connection and certificate details from a TLS connection state.
+// Returns nil for plain HTTP responses.
+func inspectTLS(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &TLSInfo{
+		Version:      tls.VersionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		ServerName:   state.ServerName,
+		Certificates: make([]models.CertificateInfo, 0, len(state.PeerCertificates)),
+	}
+
+	for _, cert := range state.PeerCertificates {
+		info.Certificates = append(info.Certificates, certificateInfo(cert))
+	}
+
+	return info
+}
+
+// certificateInfo converts an x509 certificate to its stored representation
+func certificateInfo(cert *x509.Certificate) models.CertificateInfo {
+	dnsNames := make([]string, len(cert.DNSNames))
+	copy(dnsNames, cert.DNSNames)
+
+	return models.CertificateInfo{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		DNSNames:     dnsNames,
+		SerialNumber: cert.SerialNumber.Text(16),
+		NotBefore:    cert.NotBefore.UTC(),
+		NotAfter:     cert.NotAfter.UTC(),
+		IsCA:         cert.IsCA,
+	}
+}
@@ -1,6 +1,14 @@
 -- Web Crawler Database Schema
+--
+-- The backend's GORM AutoMigrate (runMigrations in backend/database/connection.go)
+-- owns the schema. This script only creates the base tables and seeds the development
+-- token when the MySQL container is first initialized; every other table and column
+-- (crawl attempts, workers, recoveries, batches, snapshots, images, structured data,
+-- re-parse jobs, TLS certificate and timing columns, ...) is added by AutoMigrate on
+-- startup. Change the models, not this file, when the schema changes.
 
 -- Drop tables if they exist (for clean recreation)
+DROP TABLE IF EXISTS page_forms;
 DROP TABLE IF EXISTS found_links;
 DROP TABLE IF EXISTS crawl_results;
 DROP TABLE IF EXISTS api_tokens;
@@ -38,7 +46,10 @@ CREATE TABLE crawl_results (
     internal_links_count INT DEFAULT 0,
     external_links_count INT DEFAULT 0,
     inaccessible_links_count INT DEFAULT 0,
-    has_login_form BOOLEAN DEFAULT FALSE,
+    forms_count INT DEFAULT 0,
+    insecure_forms_count INT DEFAULT 0,
+    login_confidence INT DEFAULT 0, -- 0-100, see models.LoginFormThreshold
+    login_signals TEXT NULL, -- JSON array of login signals
     
     -- Metadata
     crawled_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -48,7 +59,8 @@ CREATE TABLE crawl_results (
     CONSTRAINT fk_crawl_results_url_id 
         FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
     INDEX idx_url_id (url_id),
-    INDEX idx_crawled_at (crawled_at)
+    INDEX idx_crawled_at (crawled_at),
+    INDEX idx_login_confidence (login_confidence)
 );
 
 -- Links found during crawling - for detailed analysis
@@ -73,6 +85,30 @@ CREATE TABLE found_links (
     INDEX idx_status_code (status_code)
 );
 
+-- Forms found during crawling, with controls outside any <form> grouped as standalone
+CREATE TABLE page_forms (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    url_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    action VARCHAR(2048) NULL,
+    method VARCHAR(10) NULL,
+    standalone BOOLEAN NOT NULL DEFAULT FALSE,
+    fields TEXT NULL, -- JSON array of {name, type, autocomplete}
+    has_csrf_token BOOLEAN NOT NULL DEFAULT FALSE,
+    insecure_action BOOLEAN NOT NULL DEFAULT FALSE,
+    login_confidence INT NOT NULL DEFAULT 0,
+    login_signals TEXT NULL,
+    
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    -- Foreign key with CASCADE DELETE
+    CONSTRAINT fk_page_forms_url_id 
+        FOREIGN KEY (url_id) REFERENCES urls(id) ON DELETE CASCADE,
+    INDEX idx_url_id (url_id),
+    INDEX idx_insecure_action (insecure_action),
+    INDEX idx_login_confidence (login_confidence)
+);
+
 -- API tokens for authentication
 CREATE TABLE api_tokens (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,